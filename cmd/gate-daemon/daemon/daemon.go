@@ -38,7 +38,6 @@ import (
 	"gate.computer/gate/server/webserver"
 	"gate.computer/gate/service"
 	"gate.computer/gate/service/origin"
-	"gate.computer/gate/service/random"
 	"gate.computer/internal/bus"
 	"gate.computer/internal/cmdconf"
 	"gate.computer/internal/logging"
@@ -82,6 +81,8 @@ type Config struct {
 
 	Inventory map[string]database.Config
 
+	KeyValue map[string]database.Config
+
 	Service map[string]any
 
 	Server struct {
@@ -115,9 +116,10 @@ var userID = strconv.Itoa(os.Getuid())
 
 var terminate = make(chan os.Signal, 1)
 
+// Files passed by clients, referenced by InvokeOptions.
 var (
-	debugLogMu sync.Mutex
-	debugLogs  = make(map[string]io.WriteCloser)
+	invokeFileMu sync.Mutex
+	invokeFiles  = make(map[string]*os.File)
 )
 
 func Main() {
@@ -144,6 +146,7 @@ func mainResult() int {
 	c.Runtime.Container.Namespace.Newgidmap = DefaultNewgidmap
 	c.Image.StateDir = cmdconf.ExpandEnv(DefaultImageStateDir)
 	c.Inventory = database.NewInventoryConfigs()
+	c.KeyValue = database.NewKeyValueConfigs()
 	c.Service = service.Config()
 	c.Principal = server.DefaultAccessConfig
 	c.Principal.MaxModules = 1e9
@@ -166,13 +169,10 @@ func mainResult() int {
 		}
 	}
 
-	originConfig := origin.DefaultConfig
-	originConfig.MaxConns = 1e9
-	originConfig.BufSize = origin.DefaultBufSize
-	c.Service["origin"] = &originConfig
-
-	randomConfig := random.DefaultConfig
-	c.Service["random"] = &randomConfig
+	servicesConfig := services.DefaultConfig
+	servicesConfig.Origin.MaxConns = 1e9
+	servicesConfig.Origin.BufSize = origin.DefaultBufSize
+	servicesConfig.Register(c.Service)
 
 	c.HTTP.Static = nil
 
@@ -196,7 +196,13 @@ func mainResult() int {
 	c.HTTP.AddEvent = tracing.EventAdder()
 	c.HTTP.DetachTrace = tracing.TraceDetacher()
 
-	c.Principal.Services = must(services.Init(context.Background(), &originConfig, &randomConfig, log))
+	if kvDB, err := database.Resolve(c.KeyValue); err != database.ErrNoConfig {
+		z.Check(err)
+		defer kvDB.Close()
+		servicesConfig.KeyValueStore = must(kvDB.InitKeyValue(context.Background()))
+	}
+
+	c.Principal.Services = must(services.Init(context.Background(), &servicesConfig, log))
 
 	exec := must(runtime.NewExecutor(&c.Runtime.Config))
 	defer exec.Close()
@@ -244,7 +250,9 @@ func mainResult() int {
 	c.Server.Inventory = inventory
 	c.Server.ProcessFactory = exec
 	c.Server.AccessPolicy = &access{server.PublicAccess{AccessConfig: c.Principal}}
-	c.Server.OpenDebugLog = openDebugLog
+	c.Server.OpenDebugLog = openInvokeWriter
+	c.Server.OpenRecording = openInvokeWriter
+	c.Server.OpenReplay = openInvokeReader
 	c.Server.StartSpan = tracing.SpanStarter(nil)
 	c.Server.AddEvent = tracing.EventAdder()
 	if n := c.Runtime.PrepareProcesses; n > 0 {
@@ -322,6 +330,8 @@ func mainResult() int {
 
 	inited <- s
 
+	go emitInstanceChanges(ctx, conn, s)
+
 	must(daemon.SdNotify(false, daemon.SdNotifyReady))
 
 	select {
@@ -361,7 +371,7 @@ func methods(ctx Context, inited <-chan api.Server) map[string]any {
 	}
 
 	methods := map[string]any{
-		"Call": func(moduleID, function string, instanceTags, scop []string, transient bool, suspendFD, rFD, wFD, debugFD dbus.UnixFD, debugLogging bool) (instanceID string, state api.State, cause api.Cause, result int32, err *dbus.Error) {
+		"Call": func(moduleID, function string, instanceTags, scop []string, transient bool, suspendFD, rFD, wFD, debugFD dbus.UnixFD, debugLogging bool, recordFD dbus.UnixFD, recording bool, replayFD dbus.UnixFD, replaying bool) (instanceID string, state api.State, cause api.Cause, result int32, err *dbus.Error) {
 			defer func() { err = asBusError(recover()) }()
 			ctx, span := startSpan(ctx, "Call")
 			defer span.End()
@@ -371,11 +381,11 @@ func methods(ctx Context, inited <-chan api.Server) map[string]any {
 				Tags:      instanceTags,
 			}
 			ctx = scope.Context(ctx, scop)
-			instanceID, state, cause, result = doCall(ctx, s(), moduleID, nil, nil, launch, suspendFD, rFD, wFD, debugFD, debugLogging)
+			instanceID, state, cause, result = doCall(ctx, s(), moduleID, nil, nil, launch, suspendFD, rFD, wFD, debugFD, debugLogging, recordFD, recording, replayFD, replaying)
 			return
 		},
 
-		"CallFile": func(moduleFD dbus.UnixFD, modulePin bool, moduleTags []string, function string, instanceTags, scop []string, transient bool, suspendFD, rFD, wFD, debugFD dbus.UnixFD, debugLogging bool) (instanceID string, state api.State, cause api.Cause, result int32, err *dbus.Error) {
+		"CallFile": func(moduleFD dbus.UnixFD, modulePin bool, moduleTags []string, function string, instanceTags, scop []string, transient bool, suspendFD, rFD, wFD, debugFD dbus.UnixFD, debugLogging bool, recordFD dbus.UnixFD, recording bool, replayFD dbus.UnixFD, replaying bool) (instanceID string, state api.State, cause api.Cause, result int32, err *dbus.Error) {
 			defer func() { err = asBusError(recover()) }()
 			ctx, span := startSpan(ctx, "CallFile")
 			defer span.End()
@@ -387,7 +397,7 @@ func methods(ctx Context, inited <-chan api.Server) map[string]any {
 				Tags:      instanceTags,
 			}
 			ctx = scope.Context(ctx, scop)
-			instanceID, state, cause, result = doCall(ctx, s(), "", moduleFile, moduleOpt, launch, suspendFD, rFD, wFD, debugFD, debugLogging)
+			instanceID, state, cause, result = doCall(ctx, s(), "", moduleFile, moduleOpt, launch, suspendFD, rFD, wFD, debugFD, debugLogging, recordFD, recording, replayFD, replaying)
 			return
 		},
 
@@ -429,6 +439,14 @@ func methods(ctx Context, inited <-chan api.Server) map[string]any {
 			return
 		},
 
+		"GetInstanceDetails": func(instanceID string) (infoProtoBuf []byte, err *dbus.Error) {
+			defer func() { err = asBusError(recover()) }()
+			ctx, span := startSpan(ctx, "GetInstanceDetails")
+			defer span.End()
+			infoProtoBuf = must(proto.Marshal(must(s().InstanceInfo(ctx, instanceID))))
+			return
+		},
+
 		"GetInstanceInfo": func(instanceID string) (state api.State, cause api.Cause, result int32, tags []string, err *dbus.Error) {
 			defer func() { err = asBusError(recover()) }()
 			ctx, span := startSpan(ctx, "GetInstanceInfo")
@@ -485,6 +503,22 @@ func methods(ctx Context, inited <-chan api.Server) map[string]any {
 			return
 		},
 
+		"FindInstances": func(optProtoBuf []byte) (resProtoBuf []byte, err *dbus.Error) {
+			defer func() { err = asBusError(recover()) }()
+			ctx, span := startSpan(ctx, "FindInstances")
+			defer span.End()
+			resProtoBuf = findInstances(ctx, s(), optProtoBuf)
+			return
+		},
+
+		"FindModules": func(optProtoBuf []byte) (resProtoBuf []byte, err *dbus.Error) {
+			defer func() { err = asBusError(recover()) }()
+			ctx, span := startSpan(ctx, "FindModules")
+			defer span.End()
+			resProtoBuf = findModules(ctx, s(), optProtoBuf)
+			return
+		},
+
 		"ListInstances": func(traceID, spanID []byte) (list []string, err *dbus.Error) {
 			defer func() { err = asBusError(recover()) }()
 			ctx, span := startSpan(ctx, "ListInstances")
@@ -592,8 +626,17 @@ func methods(ctx Context, inited <-chan api.Server) map[string]any {
 	return methods
 }
 
+func findModules(ctx Context, s api.Server, optBuf []byte) []byte {
+	opt := new(api.ModuleListOptions)
+	z.Check(proto.Unmarshal(optBuf, opt))
+
+	res := must(s.Modules(ctx, opt))
+
+	return must(proto.Marshal(res))
+}
+
 func listModules(ctx Context, s api.Server) []string {
-	refs := must(s.Modules(ctx))
+	refs := must(s.Modules(ctx, nil))
 	ids := make([]string, 0, len(refs.Modules))
 	for _, ref := range refs.Modules {
 		ids = append(ids, ref.Module)
@@ -616,8 +659,9 @@ func uploadModule(ctx Context, s api.Server, file *os.File, length int64, hash s
 	return must(s.UploadModule(ctx, upload, opt))
 }
 
-// doCall module id or file.  Module options apply only to module file.
-func doCall(ctx Context, s api.Server, moduleID string, moduleFile *os.File, moduleOpt *api.ModuleOptions, launch *api.LaunchOptions, suspendFD dbus.UnixFD, rFD dbus.UnixFD, wFD dbus.UnixFD, debugFD dbus.UnixFD, debugLogging bool) (string, api.State, api.Cause, int32) {
+// doCall module id or file.  Module options apply only to module file.  I/O is
+// not connected when replaying.
+func doCall(ctx Context, s api.Server, moduleID string, moduleFile *os.File, moduleOpt *api.ModuleOptions, launch *api.LaunchOptions, suspendFD dbus.UnixFD, rFD dbus.UnixFD, wFD dbus.UnixFD, debugFD dbus.UnixFD, debugLogging bool, recordFD dbus.UnixFD, recording bool, replayFD dbus.UnixFD, replaying bool) (string, api.State, api.Cause, int32) {
 	syscall.SetNonblock(int(suspendFD), true)
 	suspend := os.NewFile(uintptr(suspendFD), "suspend")
 	defer func() {
@@ -639,6 +683,19 @@ func doCall(ctx Context, s api.Server, moduleID string, moduleFile *os.File, mod
 		}
 	}()
 
+	record, cancelRecord := registerInvokeFile(recordFD, "record", recording)
+	defer cancelRecord()
+
+	replay, cancelReplay := registerInvokeFile(replayFD, "replay", replaying)
+	defer cancelReplay()
+
+	if record != "" || replay != "" {
+		launch.Invoke = &api.InvokeOptions{
+			Record: record,
+			Replay: replay,
+		}
+	}
+
 	inst := doLaunch(ctx, s, moduleID, moduleFile, moduleOpt, launch, debugFD, debugLogging)
 	defer func() {
 		if err := inst.Kill(ctx); err != nil {
@@ -656,15 +713,17 @@ func doCall(ctx Context, s api.Server, moduleID string, moduleFile *os.File, mod
 	}(suspend)
 	suspend = nil
 
-	wrote = true
-	z.Check(inst.Connect(ctx, r, w))
+	if !replaying {
+		wrote = true
+		z.Check(inst.Connect(ctx, r, w))
+	}
 	status := inst.Wait(ctx)
 	return inst.ID(), status.State, status.Cause, status.Result
 }
 
 // doLaunch module id or file.  Module options apply only to module file.
 func doLaunch(ctx Context, s api.Server, moduleID string, moduleFile *os.File, moduleOpt *api.ModuleOptions, launch *api.LaunchOptions, debugFD dbus.UnixFD, debugLogging bool) api.Instance {
-	invoke, cancel := invokeOptions(debugFD, debugLogging)
+	invoke, cancel := invokeOptions(launch.Invoke, debugFD, debugLogging)
 	defer cancel()
 
 	launch.Invoke = invoke
@@ -680,8 +739,17 @@ func doLaunch(ctx Context, s api.Server, moduleID string, moduleFile *os.File, m
 	}
 }
 
+func findInstances(ctx Context, s api.Server, optBuf []byte) []byte {
+	opt := new(api.InstanceListOptions)
+	z.Check(proto.Unmarshal(optBuf, opt))
+
+	res := must(s.Instances(ctx, opt))
+
+	return must(proto.Marshal(res))
+}
+
 func listInstances(ctx Context, s api.Server) []string {
-	instances := must(s.Instances(ctx))
+	instances := must(s.Instances(ctx, nil))
 	ids := make([]string, 0, len(instances.Instances))
 	for _, i := range instances.Instances {
 		ids = append(ids, i.Instance)
@@ -700,7 +768,7 @@ func waitInstance(ctx Context, s api.Server, instanceID string) (state api.State
 }
 
 func resumeInstance(ctx Context, s api.Server, instance string, resume *api.ResumeOptions, debugFD dbus.UnixFD, debugLogging bool) {
-	invoke, cancel := invokeOptions(debugFD, debugLogging)
+	invoke, cancel := invokeOptions(nil, debugFD, debugLogging)
 	defer cancel()
 
 	resume.Invoke = invoke
@@ -811,46 +879,72 @@ func moduleOptions(pin bool, tags []string) *api.ModuleOptions {
 	}
 }
 
-func invokeOptions(debugFD dbus.UnixFD, debugLogging bool) (*api.InvokeOptions, func()) {
-	f := os.NewFile(uintptr(debugFD), "debug")
-	if !debugLogging {
-		f.Close()
-		return nil, func() {}
+// invokeOptions adds debug log to opt.
+func invokeOptions(opt *api.InvokeOptions, debugFD dbus.UnixFD, debugLogging bool) (*api.InvokeOptions, func()) {
+	id, cancel := registerInvokeFile(debugFD, "debug", debugLogging)
+	if id != "" {
+		if opt == nil {
+			opt = new(api.InvokeOptions)
+		}
+		opt.DebugLog = id
 	}
+	return opt, cancel
+}
 
-	id := fmt.Sprint(debugFD)
-	opt := &api.InvokeOptions{
-		DebugLog: id,
+// registerInvokeFile returns a name which can be specified in InvokeOptions.
+// The file is closed if it's not used, or if cancel is called before the
+// server has opened it.
+func registerInvokeFile(fd dbus.UnixFD, name string, used bool) (string, func()) {
+	f := os.NewFile(uintptr(fd), name)
+	if !used {
+		f.Close()
+		return "", func() {}
 	}
 
+	id := fmt.Sprint(fd)
+
 	cancel := func() {
-		debugLogMu.Lock()
-		defer debugLogMu.Unlock()
+		invokeFileMu.Lock()
+		defer invokeFileMu.Unlock()
 
-		if _, found := debugLogs[id]; found {
-			delete(debugLogs, id)
+		if _, found := invokeFiles[id]; found {
+			delete(invokeFiles, id)
 			f.Close()
 		}
 	}
 
-	debugLogMu.Lock()
-	defer debugLogMu.Unlock()
+	invokeFileMu.Lock()
+	defer invokeFileMu.Unlock()
 
-	debugLogs[id] = f
+	invokeFiles[id] = f
 
-	return opt, cancel
+	return id, cancel
 }
 
-func openDebugLog(id string) io.WriteCloser {
-	debugLogMu.Lock()
-	defer debugLogMu.Unlock()
+func openInvokeFile(id string) *os.File {
+	invokeFileMu.Lock()
+	defer invokeFileMu.Unlock()
 
-	f := debugLogs[id]
-	delete(debugLogs, id)
+	f := invokeFiles[id]
+	delete(invokeFiles, id)
 
 	return f
 }
 
+func openInvokeWriter(id string) io.WriteCloser {
+	if f := openInvokeFile(id); f != nil {
+		return f
+	}
+	return nil
+}
+
+func openInvokeReader(id string) io.ReadCloser {
+	if f := openInvokeFile(id); f != nil {
+		return f
+	}
+	return nil
+}
+
 func asBusError(x any) *dbus.Error {
 	if x == nil {
 		return nil
@@ -943,6 +1037,21 @@ func serveUnix(ctx Context, s *server.Server, l *net.UnixListener) error {
 	}
 }
 
+func emitInstanceChanges(ctx Context, conn *dbus.Conn, s api.Server) {
+	infos, err := s.WatchInstances(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "daemon: instance watch", "err", err)
+		return
+	}
+
+	for info := range infos {
+		status := info.Status
+		if err := conn.Emit(bus.DaemonPath, bus.DaemonInstanceChanged, info.Instance, status.State, status.Cause, status.Result, info.Tags); err != nil {
+			slog.ErrorContext(ctx, "daemon: instance signal", "err", err)
+		}
+	}
+}
+
 func startSpan(ctx Context, method string, logargs ...any) (Context, trace.Span) {
 	tracer := otel.GetTracerProvider().Tracer("gate/cmd/gate-daemon")
 	ctx, span := tracer.Start(ctx, method, trace.WithSpanKind(trace.SpanKindServer))
@@ -0,0 +1,72 @@
+// Copyright (c) 2026 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"gate.computer/gate/database"
+	"gate.computer/gate/image"
+	"gate.computer/gate/server"
+
+	. "import.name/type/context"
+)
+
+// fsck checks consistency of image storage and inventory.  The server must
+// not be running.  It returns exit status.
+func fsck(ctx Context, args []string) int {
+	flags := flag.NewFlagSet("fsck", flag.ExitOnError)
+	repair := flags.Bool("repair", false, "quarantine bad image files and remove inventory records without files")
+	flags.Parse(args)
+
+	if err := fsck2(ctx, *repair); err != nil {
+		fmt.Fprintf(os.Stderr, "fsck: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func fsck2(ctx Context, repair bool) error {
+	if c.Image.ProgramStorage != "filesystem" || c.Image.InstanceStorage != "filesystem" {
+		return fmt.Errorf("image storage is not filesystem")
+	}
+
+	fs, err := image.NewFilesystem(c.Image.StateDir)
+	if err != nil {
+		return fmt.Errorf("filesystem: %w", err)
+	}
+	defer fs.Close()
+
+	inventoryDB, err := database.Resolve(c.Inventory)
+	if err != nil {
+		return err
+	}
+	defer inventoryDB.Close()
+
+	inventory, err := inventoryDB.InitInventory(ctx)
+	if err != nil {
+		return err
+	}
+
+	issues, err := server.CheckStorage(ctx, fs, inventory, repair)
+	if err != nil {
+		return err
+	}
+
+	var unrepaired int
+	for _, x := range issues {
+		fmt.Println(x)
+		if x.Repair == "" {
+			unrepaired++
+		}
+	}
+
+	if unrepaired > 0 {
+		return fmt.Errorf("%d unrepaired issues", unrepaired)
+	}
+	return nil
+}
@@ -33,8 +33,6 @@ import (
 	"gate.computer/gate/server/webserver"
 	"gate.computer/gate/server/webserver/router"
 	"gate.computer/gate/service"
-	"gate.computer/gate/service/origin"
-	"gate.computer/gate/service/random"
 	"gate.computer/gate/source"
 	httpsource "gate.computer/gate/source/http"
 	"gate.computer/gate/source/ipfs"
@@ -91,6 +89,8 @@ type Config struct {
 
 	Inventory map[string]database.Config
 
+	KeyValue map[string]database.Config
+
 	Service map[string]any
 
 	Server struct {
@@ -200,6 +200,7 @@ func Main() {
 	c.Image.InstanceStorage = DefaultImageStorage
 	c.Image.StateDir = DefaultImageStateDir
 	c.Inventory = database.NewInventoryConfigs()
+	c.KeyValue = database.NewKeyValueConfigs()
 	c.Service = service.Config()
 	c.Principal = server.DefaultAccessConfig
 	c.Source.Cache = database.NewSourceCacheConfigs()
@@ -223,11 +224,8 @@ func Main() {
 		}
 	}
 
-	originConfig := origin.DefaultConfig
-	c.Service["origin"] = &originConfig
-
-	randomConfig := random.DefaultConfig
-	c.Service["random"] = &randomConfig
+	servicesConfig := services.DefaultConfig
+	servicesConfig.Register(c.Service)
 
 	flag.Usage = confi.FlagUsage(nil, c)
 	cmdconf.Parse(c, flag.CommandLine, false, DefaultConfigFiles...)
@@ -267,7 +265,22 @@ func Main() {
 
 	ctx := context.Background()
 
-	c.Principal.Services, err = services.Init(router.Context(ctx, extMux), &originConfig, &randomConfig, log)
+	if flag.Arg(0) == "fsck" {
+		os.Exit(fsck(ctx, flag.Args()[1:]))
+	}
+
+	if kvDB, err := database.Resolve(c.KeyValue); err == nil {
+		servicesConfig.KeyValueStore, err = kvDB.InitKeyValue(ctx)
+		if err != nil {
+			log.ErrorContext(ctx, "key-value storage initialization failed", "error", err)
+			os.Exit(1)
+		}
+	} else if err != database.ErrNoConfig {
+		log.ErrorContext(ctx, "key-value storage configuration failed", "error", err)
+		os.Exit(1)
+	}
+
+	c.Principal.Services, err = services.Init(router.Context(ctx, extMux), &servicesConfig, log)
 	if err != nil {
 		log.ErrorContext(ctx, "service initialization failed", "error", err)
 		os.Exit(1)
@@ -8,6 +8,7 @@ import (
 	"bufio"
 	"debug/dwarf"
 	"encoding/binary"
+	"encoding/hex"
 	"errors"
 	"flag"
 	"fmt"
@@ -71,6 +72,7 @@ func debug(call debugCallFunc) {
 
 		case "delete":
 			req.Op = api.DebugOpConfigComplement
+			req.Config = new(api.DebugConfig)
 
 		case "detach":
 			req.Op = api.DebugOpConfigSet
@@ -84,16 +86,58 @@ func debug(call debugCallFunc) {
 				fatal("stacktrace command does not support offsets")
 			}
 
+		case "trace":
+			req.Op = api.DebugOpReadStacktrace
+			if flag.NArg() > 2 {
+				fatal("trace command does not support arguments")
+			}
+
 		case "dumptext":
 			if flag.NArg() > 2 {
 				fatal("dumptext command does not support offsets")
 			}
 
+		case "globals":
+			req.Op = api.DebugOpReadGlobals
+			if flag.NArg() > 2 {
+				fatal("globals command does not support arguments")
+			}
+
+		case "setglobal":
+			req.Op = api.DebugOpWriteGlobal
+			if flag.NArg() != 4 {
+				fatal("setglobal command requires index and value")
+			}
+			req.Addr = parseDebugUint(flag.Arg(2))
+			req.Data = binary.LittleEndian.AppendUint64(nil, parseDebugUint(flag.Arg(3)))
+
+		case "read":
+			req.Op = api.DebugOpReadMemory
+			if flag.NArg() != 4 {
+				fatal("read command requires address and size")
+			}
+			req.Addr = parseDebugUint(flag.Arg(2))
+			req.Size = parseDebugUint(flag.Arg(3))
+
+		case "write":
+			req.Op = api.DebugOpWriteMemory
+			if flag.NArg() != 4 {
+				fatal("write command requires address and hex data")
+			}
+			req.Addr = parseDebugUint(flag.Arg(2))
+			req.Data = must(hex.DecodeString(flag.Arg(3)))
+
+		case "step":
+			req.Op = api.DebugOpStep
+			if flag.NArg() > 2 {
+				fatal("step command does not support arguments")
+			}
+
 		default:
 			fatalf("unknown debug op: %s", flag.Arg(1))
 		}
 
-		if flag.NArg() > 2 {
+		if flag.NArg() > 2 && req.Config != nil {
 			req.Config.Breakpoints = parseBreakpoints(flag.Args()[2:], call, flag.Arg(0))
 		}
 	}
@@ -104,10 +148,21 @@ func debug(call debugCallFunc) {
 	case "bt", "backtrace":
 		debugBacktrace(res)
 
+	case "trace":
+		debugTrace(res)
+
 	case "dumptext":
 		_, text, codeMap, names, _ := build(res)
 		z.Check(dumpText(text, codeMap.FuncAddrs, &names))
 
+	case "globals":
+		for i := 0; i+8 <= len(res.Data); i += 8 {
+			fmt.Printf("%d: 0x%x\n", i/8, binary.LittleEndian.Uint64(res.Data[i:]))
+		}
+
+	case "read":
+		fmt.Print(hex.Dump(res.Data))
+
 	default:
 		modkey := res.Module
 		if x := strings.SplitN(res.Module, "/", 2); len(x) == 2 && x[0] == api.KnownModuleSource {
@@ -125,6 +180,14 @@ func debug(call debugCallFunc) {
 	}
 }
 
+func parseDebugUint(s string) uint64 {
+	n, err := strconv.ParseUint(s, 0, 64)
+	if err != nil {
+		fatalf("invalid number: %q", s)
+	}
+	return n
+}
+
 func parseBreakpoints(args []string, call debugCallFunc, instID string) (breakOffs []uint64) {
 	var (
 		breakLocs  []location
@@ -292,6 +355,29 @@ func parseBreakpoints(args []string, call debugCallFunc, instID string) (breakOf
 	return
 }
 
+// debugTrace prints a stacktrace which was symbolized by the server.
+func debugTrace(res *api.DebugResponse) {
+	if len(res.Stacktrace) == 0 {
+		fatal("no stack")
+	}
+
+	for i, f := range res.Stacktrace {
+		name := f.Name
+		if name == "" {
+			name = fmt.Sprintf("func-%d", f.Function)
+		}
+
+		fmt.Printf("#%-2d  0x%06x in %s", i, f.Offset, name)
+		if f.File != "" {
+			fmt.Printf(" at %s:%d", f.File, f.Line)
+			if f.Column != 0 {
+				fmt.Printf(":%d", f.Column)
+			}
+		}
+		fmt.Println()
+	}
+}
+
 func debugBacktrace(res *api.DebugResponse) {
 	if len(res.Data) == 0 {
 		fatal("no stack")
@@ -0,0 +1,276 @@
+// Copyright (c) 2026 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"gate.computer/gate/runtime/abi"
+	"gate.computer/gate/snapshot"
+	"gate.computer/gate/snapshot/wasm"
+	"gate.computer/wag/binding"
+	"gate.computer/wag/compile"
+	objectdebug "gate.computer/wag/object/debug"
+	"gate.computer/wag/section"
+)
+
+// inspectMemoryAlignment separates globals from linear memory.
+const inspectMemoryAlignment = 4096
+
+var inspectJSON bool
+
+type inspection struct {
+	MemorySize     int              `json:"memorySize"`
+	MaxMemorySize  int              `json:"maxMemorySize"`
+	MemoryDataSize int              `json:"memoryDataSize"`
+	Globals        []inspectGlobal  `json:"globals,omitempty"`
+	Snapshot       *inspectSnapshot `json:"snapshot,omitempty"`
+	Buffers        *inspectBuffers  `json:"buffers,omitempty"`
+	Stack          []inspectFrame   `json:"stack,omitempty"`
+}
+
+type inspectGlobal struct {
+	Type    string `json:"type"`
+	Mutable bool   `json:"mutable"`
+	Value   uint64 `json:"value,string"`
+}
+
+type inspectSnapshot struct {
+	Final         bool     `json:"final"`
+	Trap          string   `json:"trap"`
+	Result        int32    `json:"result"`
+	MonotonicTime uint64   `json:"monotonicTime,string"`
+	Breakpoints   []uint64 `json:"breakpoints,omitempty"`
+}
+
+type inspectBuffers struct {
+	Input    int              `json:"input"`
+	Output   int              `json:"output"`
+	Services []inspectService `json:"services,omitempty"`
+}
+
+type inspectService struct {
+	Name string `json:"name"`
+	Size int    `json:"size"`
+}
+
+type inspectFrame struct {
+	Function int      `json:"function"`
+	Name     string   `json:"name,omitempty"`
+	Offset   int      `json:"offset"`
+	Locals   []uint64 `json:"locals,omitempty"`
+}
+
+// inspectBuffer implements compile.GlobalsMemory.
+type inspectBuffer struct {
+	b []byte
+}
+
+func (buf *inspectBuffer) Bytes() []byte {
+	return buf.b
+}
+
+func (buf *inspectBuffer) ResizeBytes(n int) []byte {
+	if n > cap(buf.b) {
+		buf.b = append(buf.b[:cap(buf.b)], make([]byte, n-cap(buf.b))...)
+	}
+	buf.b = buf.b[:n]
+	return buf.b
+}
+
+// inspectModule decodes the gate-specific custom sections of a snapshot
+// module.  Regular modules have only memory and globals.
+func inspectModule(r io.Reader) *inspection {
+	var (
+		x         = new(inspection)
+		snap      *snapshot.Snapshot
+		buffers   *snapshot.Buffers
+		stackData []byte
+		names     section.NameSection
+	)
+
+	config := compile.Config{
+		CustomSectionLoader: section.CustomLoader(map[string]section.CustomContentLoader{
+			"name": names.Load,
+
+			wasm.SectionExport: func(string, section.Reader, uint32) error {
+				return section.Unwrapped // Load as standard section.
+			},
+
+			wasm.SectionSnapshot: func(_ string, r section.Reader, length uint32) error {
+				s, n, err := wasm.ReadSnapshotSection(r)
+				if err != nil {
+					return err
+				}
+				snap = s
+				_, err = io.CopyN(io.Discard, r, int64(length)-int64(n))
+				return err
+			},
+
+			wasm.SectionBuffer: func(_ string, r section.Reader, length uint32) error {
+				bs, n, data, err := wasm.ReadBufferSectionHeader(r, length)
+				if err != nil {
+					return err
+				}
+				buffers = bs
+				if _, err := io.ReadFull(r, data); err != nil {
+					return err
+				}
+				_, err = io.CopyN(io.Discard, r, int64(length)-int64(n)-int64(len(data)))
+				return err
+			},
+
+			wasm.SectionStack: func(_ string, r section.Reader, length uint32) error {
+				stackData = make([]byte, length)
+				_, err := io.ReadFull(r, stackData)
+				return err
+			},
+		}),
+	}
+
+	loader := compile.NewLoader(bufio.NewReader(r))
+
+	mod := must(compile.LoadInitialSections(&compile.ModuleConfig{Config: config}, loader))
+	z.Check(binding.BindImports(&mod, new(abi.ImportResolver)))
+
+	var codeMap objectdebug.InsnMap
+	z.Check(compile.LoadCodeSection(&compile.CodeConfig{Mapper: &codeMap, Config: config}, loader, mod, abi.Library()))
+
+	data := new(inspectBuffer)
+	z.Check(compile.LoadDataSection(&compile.DataConfig{
+		GlobalsMemory:   data,
+		MemoryAlignment: inspectMemoryAlignment,
+		Config:          config,
+	}, loader, mod))
+
+	if err := compile.LoadCustomSections(&config, loader); err != nil && err != io.EOF {
+		z.Check(err)
+	}
+
+	globalsEnd := (mod.GlobalsSize() + inspectMemoryAlignment - 1) &^ (inspectMemoryAlignment - 1)
+
+	x.MemorySize = mod.InitialMemorySize()
+	x.MaxMemorySize = mod.MemorySizeLimit()
+	if n := len(data.b) - globalsEnd; n > 0 {
+		x.MemoryDataSize = n
+	}
+
+	for i, t := range mod.GlobalTypes() {
+		g := inspectGlobal{
+			Type:    t.Type().String(),
+			Mutable: t.Mutable(),
+		}
+		if off := globalsEnd - (i+1)*8; off >= 0 && off+8 <= len(data.b) {
+			g.Value = binary.LittleEndian.Uint64(data.b[off:])
+		}
+		x.Globals = append(x.Globals, g)
+	}
+
+	if snap != nil {
+		x.Snapshot = &inspectSnapshot{
+			Final:         snap.Final,
+			Trap:          snap.Trap.String(),
+			Result:        snap.Result,
+			MonotonicTime: snap.MonotonicTime,
+			Breakpoints:   snap.Breakpoints,
+		}
+	}
+
+	if buffers != nil {
+		x.Buffers = &inspectBuffers{
+			Input:  len(buffers.Input),
+			Output: len(buffers.Output),
+		}
+		for _, s := range buffers.Services {
+			x.Buffers.Services = append(x.Buffers.Services, inspectService{
+				Name: s.Name,
+				Size: len(s.Buffer),
+			})
+		}
+	}
+
+	if len(stackData) > 0 {
+		for _, f := range traceStack(stackData, codeMap, mod.FuncTypes()) {
+			frame := inspectFrame{
+				Function: f.FuncIndex,
+				Offset:   f.RetOffset,
+				Locals:   f.Locals,
+			}
+			if f.FuncIndex < len(names.FuncNames) {
+				frame.Name = names.FuncNames[f.FuncIndex].FuncName
+			}
+			x.Stack = append(x.Stack, frame)
+		}
+	}
+
+	return x
+}
+
+func inspect(filename string, jsonOutput bool) {
+	f := must(os.Open(filename))
+	defer f.Close()
+
+	x := inspectModule(f)
+
+	if jsonOutput {
+		b := must(json.MarshalIndent(x, "", "\t"))
+		fmt.Printf("%s\n", b)
+		return
+	}
+
+	fmt.Printf("Memory:         %d bytes (limit %d bytes)\n", x.MemorySize, x.MaxMemorySize)
+	fmt.Printf("Memory data:    %d bytes\n", x.MemoryDataSize)
+
+	if len(x.Globals) > 0 {
+		fmt.Println("Globals:")
+		for i, g := range x.Globals {
+			mut := ""
+			if g.Mutable {
+				mut = " mut"
+			}
+			fmt.Printf("  %d: %s%s 0x%x\n", i, g.Type, mut, g.Value)
+		}
+	}
+
+	if s := x.Snapshot; s != nil {
+		fmt.Printf("Trap:           %s\n", s.Trap)
+		fmt.Printf("Final:          %v\n", s.Final)
+		fmt.Printf("Result:         %d\n", s.Result)
+		fmt.Printf("Monotonic time: %d\n", s.MonotonicTime)
+		if len(s.Breakpoints) > 0 {
+			fmt.Printf("Breakpoints:")
+			sep := "    "
+			for _, offset := range s.Breakpoints {
+				fmt.Printf("%s0x%x", sep, offset)
+				sep = " "
+			}
+			fmt.Println()
+		}
+	}
+
+	if b := x.Buffers; b != nil {
+		fmt.Printf("Input buffer:   %d bytes\n", b.Input)
+		fmt.Printf("Output buffer:  %d bytes\n", b.Output)
+		for _, s := range b.Services {
+			fmt.Printf("Service:        %s (%d bytes)\n", s.Name, s.Size)
+		}
+	}
+
+	if len(x.Stack) > 0 {
+		fmt.Println("Stack:")
+		for i, f := range x.Stack {
+			name := f.Name
+			if name == "" {
+				name = fmt.Sprintf("func-%d", f.Function)
+			}
+			fmt.Printf("  #%-2d 0x%06x in %s\n", i, f.Offset, name)
+		}
+	}
+}
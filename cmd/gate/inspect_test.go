@@ -0,0 +1,75 @@
+// Copyright (c) 2026 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"compress/gzip"
+	"os"
+	"runtime"
+	"testing"
+)
+
+// TestInspectSnapshot uses a snapshot of testdata/suspend.cpp which was taken
+// while the loop function was running.
+func TestInspectSnapshot(t *testing.T) {
+	f, err := os.Open("../../testdata/snapshot." + runtime.GOARCH + ".wasm.gz")
+	if err != nil {
+		t.Skip(err)
+	}
+	defer f.Close()
+
+	r, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	x := inspectModule(r)
+
+	if s := x.Snapshot; s == nil {
+		t.Error("no snapshot")
+	} else {
+		if s.Final {
+			t.Error("final")
+		}
+		if s.Trap != "SUSPENDED" {
+			t.Error("trap:", s.Trap)
+		}
+		if s.MonotonicTime == 0 {
+			t.Error("monotonic time is zero")
+		}
+	}
+
+	if b := x.Buffers; b == nil {
+		t.Error("no buffers")
+	} else {
+		if b.Input != 16 || b.Output != 0 {
+			t.Error("buffer sizes:", b.Input, b.Output)
+		}
+
+		var names []string
+		for _, s := range b.Services {
+			names = append(names, s.Name)
+		}
+		if len(names) != 3 || names[0] != "origin" || names[1] != "test" || names[2] != "_nonexistent" {
+			t.Error("services:", names)
+		}
+	}
+
+	if len(x.Globals) != 1 {
+		t.Error("globals:", x.Globals)
+	} else if g := x.Globals[0]; g.Type != "i32" || !g.Mutable || g.Value == 0 {
+		t.Error("stack pointer:", g)
+	}
+
+	var loop bool
+	for _, frame := range x.Stack {
+		if frame.Name == "loop" {
+			loop = true
+		}
+	}
+	if !loop {
+		t.Error("stack:", x.Stack)
+	}
+}
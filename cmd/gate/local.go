@@ -5,6 +5,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"io"
@@ -14,12 +15,12 @@ import (
 	"os/signal"
 	"runtime"
 	runtimedebug "runtime/debug"
-	"sort"
 	"strings"
 	"syscall"
 
 	"gate.computer/gate/server/api"
 	"gate.computer/gate/web"
+	"gate.computer/gate/web/migrate"
 	"gate.computer/internal/bus"
 	"github.com/godbus/dbus/v5"
 	"golang.org/x/term"
@@ -40,6 +41,8 @@ func daemonCall(method string, args ...any) *dbus.Call {
 var (
 	persistInstance bool
 	debugMore       bool
+	recordFile      string
+	replayFile      string
 )
 
 func parseLocalCallFlags() {
@@ -47,6 +50,8 @@ func parseLocalCallFlags() {
 	debug := flag.Bool("d", c.DebugLog == ShortcutDebugLog, "write debug log to stderr")
 	flag.BoolVar(&persistInstance, "p", persistInstance, "keep instance after it stops")
 	flag.BoolVar(&debugMore, "D", debugMore, "write debug log, keep instance and dump stack")
+	flag.StringVar(&recordFile, "record", recordFile, "record packets transferred by the program to a file")
+	flag.StringVar(&replayFile, "replay", replayFile, "replay recorded packets instead of connecting I/O")
 	flag.Parse()
 
 	if *debug || debugMore {
@@ -79,18 +84,24 @@ var localCommands = map[string]command{
 			debug := openDebugFile()
 			debugFD := dbus.UnixFD(debug.Fd())
 
+			record := openRecordFile()
+			recordFD := dbus.UnixFD(record.Fd())
+
+			replay := openReplayFile()
+			replayFD := dbus.UnixFD(replay.Fd())
+
 			var (
 				moduleFile *os.File
 				call       *dbus.Call
 			)
 			if !(strings.Contains(module, "/") || strings.Contains(module, ".")) {
-				call = daemonCall("Call", module, c.Function, c.InstanceTags, c.Scope, !persistInstance, suspendFD, rFD, wFD, debugFD, c.DebugLog != "")
+				call = daemonCall("Call", module, c.Function, c.InstanceTags, c.Scope, !persistInstance, suspendFD, rFD, wFD, debugFD, c.DebugLog != "", recordFD, recordFile != "", replayFD, replayFile != "")
 			} else {
 				moduleFile = must(os.Open(module))
 				moduleFD := dbus.UnixFD(moduleFile.Fd())
-				call = daemonCall("CallFile", moduleFD, c.Pin, c.ModuleTags, c.Function, c.InstanceTags, c.Scope, !persistInstance, suspendFD, rFD, wFD, debugFD, c.DebugLog != "")
+				call = daemonCall("CallFile", moduleFD, c.Pin, c.ModuleTags, c.Function, c.InstanceTags, c.Scope, !persistInstance, suspendFD, rFD, wFD, debugFD, c.DebugLog != "", recordFD, recordFile != "", replayFD, replayFile != "")
 			}
-			closeFiles(suspend, r, w, debug, moduleFile)
+			closeFiles(suspend, r, w, debug, record, replay, moduleFile)
 
 			var (
 				instanceID string
@@ -134,7 +145,7 @@ var localCommands = map[string]command{
 	},
 
 	"debug": {
-		usage: "instance [command [offset...]]",
+		usage: "instance [command [argument...]]",
 		do: func() {
 			debug(func(instID string, req *api.DebugRequest) *api.DebugResponse {
 				reqBuf := must(proto.Marshal(req))
@@ -217,15 +228,27 @@ var localCommands = map[string]command{
 	},
 
 	"instances": {
+		parse: parseInstanceListFlags,
 		do: func() {
-			call := daemonCall("ListInstances")
-			var ids []string
-			z.Check(call.Store(&ids))
-			sort.Strings(ids)
+			opt := &api.InstanceListOptions{
+				TagsAll: listTagsAll,
+				TagsAny: listTagsAny,
+				States:  listStates,
+				Cursor:  listCursor,
+				Limit:   int32(listLimit),
+			}
+
+			call := daemonCall("FindInstances", must(proto.Marshal(opt)))
+			var resBuf []byte
+			z.Check(call.Store(&resBuf))
+
+			res := new(api.Instances)
+			z.Check(proto.Unmarshal(resBuf, res))
 
-			for _, id := range ids {
-				fmt.Printf("%-36s %s\n", id, daemonCallGetInstanceInfo(id))
+			for _, info := range res.Instances {
+				fmt.Printf("%-36s %s %s\n", info.Instance, statusString(info.Status), info.Tags)
 			}
+			printNextCursor(res.NextCursor)
 		},
 	},
 
@@ -293,19 +316,26 @@ var localCommands = map[string]command{
 	},
 
 	"modules": {
+		parse: parseModuleListFlags,
 		do: func() {
-			call := daemonCall("ListModules")
-			var ids []string
-			z.Check(call.Store(&ids))
-			sort.Strings(ids)
+			opt := &api.ModuleListOptions{
+				TagsAll: listTagsAll,
+				TagsAny: listTagsAny,
+				Cursor:  listCursor,
+				Limit:   int32(listLimit),
+			}
+
+			call := daemonCall("FindModules", must(proto.Marshal(opt)))
+			var resBuf []byte
+			z.Check(call.Store(&resBuf))
 
-			for _, id := range ids {
-				call := daemonCall("GetModuleInfo", id)
-				var tags []string
-				z.Check(call.Store(&tags))
+			res := new(api.Modules)
+			z.Check(proto.Unmarshal(resBuf, res))
 
-				fmt.Println(id, tags)
+			for _, m := range res.Modules {
+				fmt.Println(m.Module, m.Tags)
 			}
+			printNextCursor(res.NextCursor)
 		},
 	},
 
@@ -320,6 +350,33 @@ var localCommands = map[string]command{
 		},
 	},
 
+	"inspect": {
+		usage: "file",
+		parse: func() {
+			flag.BoolVar(&inspectJSON, "json", inspectJSON, "output JSON")
+			flag.Parse()
+		},
+		do: func() {
+			inspect(flag.Arg(0), inspectJSON)
+		},
+	},
+
+	"migrate": {
+		usage: "source-address destination-address instance",
+		do: func() {
+			endpoint := func(addr string) *migrate.Endpoint {
+				return &migrate.Endpoint{
+					URL: addressURL(addr),
+					Authorization: func() (string, error) {
+						return makeAddressAuthorization(addr), nil
+					},
+				}
+			}
+
+			z.Check(migrate.Instance(context.Background(), endpoint(flag.Arg(0)), endpoint(flag.Arg(1)), flag.Arg(2)))
+		},
+	},
+
 	"pull": {
 		usage: "address module",
 		do: func() {
@@ -455,7 +512,7 @@ var localCommands = map[string]command{
 	"status": {
 		usage: "instance",
 		do: func() {
-			fmt.Println(daemonCallGetInstanceInfo(flag.Arg(0)))
+			printInstanceInfo(daemonCallGetInstanceDetails(flag.Arg(0)))
 		},
 	},
 
@@ -535,15 +592,41 @@ func exportLocalModule(moduleID, filename string) {
 	})
 }
 
-func daemonCallGetInstanceInfo(id string) string {
-	call := daemonCall("GetInstanceInfo", id)
-	var (
-		status = new(api.Status)
-		tags   []string
-	)
-	z.Check(call.Store(&status.State, &status.Cause, &status.Result, &tags))
-
-	return fmt.Sprintf("%s %s", statusString(status), tags)
+func daemonCallGetInstanceDetails(id string) *web.InstanceInfo {
+	call := daemonCall("GetInstanceDetails", id)
+	var buf []byte
+	z.Check(call.Store(&buf))
+
+	info := new(api.InstanceInfo)
+	z.Check(proto.Unmarshal(buf, info))
+
+	u := info.GetUsage()
+	t := &web.InstanceInfo{
+		Instance:   info.Instance,
+		Module:     info.Module,
+		Transient:  info.Transient,
+		Debugging:  info.Debugging,
+		Tags:       info.Tags,
+		Hibernated: info.Hibernated,
+		Usage: web.InstanceUsage{
+			MemorySize:      u.GetMemorySize(),
+			MaxMemorySize:   u.GetMaxMemorySize(),
+			StackUsage:      u.GetStackUsage(),
+			CPUTime:         u.GetCpuTime().AsDuration().String(),
+			PacketsSent:     u.GetPacketsSent(),
+			BytesSent:       u.GetBytesSent(),
+			PacketsReceived: u.GetPacketsReceived(),
+			BytesReceived:   u.GetBytesReceived(),
+		},
+	}
+	t.Status = webStatus(info.Status)
+	if info.Created != nil {
+		t.Created = info.Created.AsTime()
+	}
+	if info.Resumed != nil {
+		t.Resumed = info.Resumed.AsTime()
+	}
+	return t
 }
 
 func daemonCallWaitInstance(id string) string {
@@ -629,6 +712,20 @@ func openDebugFile() *os.File {
 	}
 }
 
+func openRecordFile() *os.File {
+	if recordFile == "" {
+		return must(os.OpenFile(os.DevNull, os.O_WRONLY, 0))
+	}
+	return must(os.Create(recordFile))
+}
+
+func openReplayFile() *os.File {
+	if replayFile == "" {
+		return must(os.Open(os.DevNull))
+	}
+	return must(os.Open(replayFile))
+}
+
 func closeFiles(files ...*os.File) {
 	for _, f := range files {
 		if f == os.Stderr {
@@ -646,6 +743,10 @@ func closeFiles(files ...*os.File) {
 }
 
 func statusString(s *api.Status) string {
+	return webStatus(s).String()
+}
+
+func webStatus(s *api.Status) web.Status {
 	t := web.Status{
 		State:  s.GetState().String(),
 		Cause:  s.GetCause().String(),
@@ -655,5 +756,5 @@ func statusString(s *api.Status) string {
 	if s.GetCause() == 0 {
 		t.Cause = ""
 	}
-	return t.String()
+	return t
 }
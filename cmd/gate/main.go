@@ -13,8 +13,11 @@ import (
 	"os"
 	"sort"
 	"strings"
+	"time"
 
 	"gate.computer/gate/scope"
+	"gate.computer/gate/server/api"
+	"gate.computer/gate/web"
 	"gate.computer/internal"
 	"gate.computer/internal/cmdconf"
 	"golang.org/x/term"
@@ -76,6 +79,8 @@ Common commands:
   wait      wait until an instance is suspended, halted, terminated or killed
 
 Local commands (no address before command):
+  inspect   print information about a wasm module file
+  migrate   move an instance from a remote server to another
   pull      copy a wasm module from a remote server to local storage
   push      copy a wasm module from local storage to a remote server
   version   print client version
@@ -119,6 +124,87 @@ func parseLaunchFlags() {
 	flag.Parse()
 }
 
+var (
+	listTagsAll []string
+	listTagsAny []string
+	listStates  []api.State
+	listCursor  string
+	listLimit   int
+)
+
+func registerListFlags() {
+	flag.Func("tag", "list only items with tag (may be specified multiple times)", func(tag string) error {
+		listTagsAll = append(listTagsAll, tag)
+		return nil
+	})
+	flag.Func("any-tag", "list only items with at least one such tag (may be specified multiple times)", func(tag string) error {
+		listTagsAny = append(listTagsAny, tag)
+		return nil
+	})
+	flag.StringVar(&listCursor, "cursor", listCursor, "continue listing after cursor")
+	flag.IntVar(&listLimit, "limit", listLimit, "maximum number of items to list")
+}
+
+func parseModuleListFlags() {
+	registerListFlags()
+	flag.Parse()
+}
+
+func parseInstanceListFlags() {
+	registerListFlags()
+	flag.Func("state", "list only instances in state (comma-separated; may be specified multiple times)", func(states string) error {
+		for _, s := range strings.Split(states, ",") {
+			state, err := parseState(strings.TrimSpace(s))
+			if err != nil {
+				return err
+			}
+			listStates = append(listStates, state)
+		}
+		return nil
+	})
+	flag.Parse()
+}
+
+func parseState(s string) (api.State, error) {
+	for _, state := range []api.State{
+		api.StateRunning,
+		api.StateSuspended,
+		api.StateHalted,
+		api.StateTerminated,
+		api.StateKilled,
+	} {
+		if strings.EqualFold(s, state.String()) {
+			return state, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown state: %q", s)
+}
+
+func printNextCursor(cursor string) {
+	if cursor != "" {
+		fmt.Fprintf(os.Stderr, "More items available: -cursor=%s\n", cursor)
+	}
+}
+
+func printInstanceInfo(info *web.InstanceInfo) {
+	fmt.Printf("%s %s\n", info.Status, info.Tags)
+
+	u := info.Usage
+	fmt.Printf("Memory:         %d of %d bytes\n", u.MemorySize, u.MaxMemorySize)
+	fmt.Printf("Stack:          %d bytes\n", u.StackUsage)
+	if d, err := time.ParseDuration(u.CPUTime); err == nil {
+		fmt.Printf("CPU time:       %s\n", d)
+	}
+	fmt.Printf("Sent:           %d packets, %d bytes\n", u.PacketsSent, u.BytesSent)
+	fmt.Printf("Received:       %d packets, %d bytes\n", u.PacketsReceived, u.BytesReceived)
+	if !info.Created.IsZero() {
+		fmt.Printf("Created:        %s\n", info.Created.Local().Format(time.RFC3339))
+	}
+	if !info.Resumed.IsZero() {
+		fmt.Printf("Resumed:        %s\n", info.Resumed.Local().Format(time.RFC3339))
+	}
+}
+
 type command struct {
 	usage    string
 	detail   string
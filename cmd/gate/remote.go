@@ -17,6 +17,7 @@ import (
 	"os"
 	"path"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -27,6 +28,8 @@ import (
 	"google.golang.org/protobuf/encoding/protojson"
 )
 
+var resumeModule string
+
 var remoteCommands = map[string]command{
 	"call": {
 		usage:    "module [function]",
@@ -84,7 +87,7 @@ var remoteCommands = map[string]command{
 	},
 
 	"debug": {
-		usage: "instance [command [offset...]]",
+		usage: "instance [command [argument...]]",
 		do: func() {
 			debug(func(instID string, debug *pb.DebugRequest) *pb.DebugResponse {
 				debugJSON := must(protojson.Marshal(debug))
@@ -160,9 +163,15 @@ var remoteCommands = map[string]command{
 	},
 
 	"instances": {
+		parse: parseInstanceListFlags,
 		do: func() {
+			params := listParams()
+			for _, state := range listStates {
+				params.Add(web.ParamState, state.String())
+			}
+
 			req := &http.Request{Method: http.MethodPost}
-			_, resp := doHTTP(req, web.PathInstances, nil)
+			_, resp := doHTTP(req, web.PathInstances, params)
 
 			var is web.Instances
 			z.Check(json.NewDecoder(resp.Body).Decode(&is))
@@ -174,6 +183,7 @@ var remoteCommands = map[string]command{
 			for _, inst := range is.Instances {
 				fmt.Printf("%-36s %s %s\n", inst.Instance, inst.Status, inst.Tags)
 			}
+			printNextCursor(is.NextCursor)
 		},
 	},
 
@@ -282,9 +292,10 @@ var remoteCommands = map[string]command{
 	},
 
 	"modules": {
+		parse: parseModuleListFlags,
 		do: func() {
 			req := &http.Request{Method: http.MethodPost}
-			_, resp := doHTTP(req, web.PathKnownModules, nil)
+			_, resp := doHTTP(req, web.PathKnownModules, listParams())
 
 			var refs web.Modules
 			z.Check(json.NewDecoder(resp.Body).Decode(&refs))
@@ -296,6 +307,7 @@ var remoteCommands = map[string]command{
 			for _, m := range refs.Modules {
 				fmt.Println(m.Module, m.Tags)
 			}
+			printNextCursor(refs.NextCursor)
 		},
 	},
 
@@ -342,11 +354,18 @@ var remoteCommands = map[string]command{
 
 	"resume": {
 		usage: "instance",
+		parse: func() {
+			flag.StringVar(&resumeModule, "module", resumeModule, "restore instance from snapshot module")
+			flag.Parse()
+		},
 		do: func() {
 			req := &http.Request{Method: http.MethodPost}
 			params := url.Values{
 				web.ParamAction: []string{web.ActionResume},
 			}
+			if resumeModule != "" {
+				params.Set(web.ParamModule, resumeModule)
+			}
 			if c.DebugLog != "" {
 				params.Set(web.ParamLog, "*")
 			}
@@ -365,6 +384,9 @@ var remoteCommands = map[string]command{
 			z.Check(json.NewDecoder(resp.Body).Decode(&info))
 
 			fmt.Println(info.Tags)
+			if l := info.Lineage; l != nil {
+				fmt.Printf("snapshot of instance %s (module %s) at %s\n", l.Instance, l.Parent, l.Created.Format(time.RFC3339))
+			}
 		},
 	},
 
@@ -407,7 +429,7 @@ var remoteCommands = map[string]command{
 			info := new(web.InstanceInfo)
 			z.Check(json.NewDecoder(resp.Body).Decode(info))
 
-			fmt.Printf("%s %s\n", info.Status, info.Tags)
+			printInstanceInfo(info)
 		},
 	},
 
@@ -610,11 +632,22 @@ func doHTTP(req *http.Request, uri string, params url.Values) (status web.Status
 	return
 }
 
-func makeURL(uri string, params url.Values, prelocate bool) *url.URL {
-	addr := c.address
-	if !strings.Contains(addr, "://") {
-		addr = "https://" + addr
+func listParams() url.Values {
+	params := url.Values{
+		web.ParamTag:    listTagsAll,
+		web.ParamAnyTag: listTagsAny,
+	}
+	if listCursor != "" {
+		params.Set(web.ParamCursor, listCursor)
 	}
+	if listLimit > 0 {
+		params.Set(web.ParamLimit, strconv.Itoa(listLimit))
+	}
+	return params
+}
+
+func makeURL(uri string, params url.Values, prelocate bool) *url.URL {
+	addr := addressURL(c.address)
 
 	var u *url.URL
 
@@ -635,6 +668,14 @@ func makeURL(uri string, params url.Values, prelocate bool) *url.URL {
 	return u
 }
 
+// addressURL adds default scheme to address if necessary.
+func addressURL(addr string) string {
+	if !strings.Contains(addr, "://") {
+		addr = "https://" + addr
+	}
+	return addr
+}
+
 func makeWebsocketURL(uri string, params url.Values) string {
 	u := makeURL(uri, params, true)
 
@@ -651,11 +692,15 @@ func makeWebsocketURL(uri string, params url.Values) string {
 }
 
 func makeAuthorization() string {
+	return makeAddressAuthorization(c.address)
+}
+
+func makeAddressAuthorization(address string) string {
 	if c.IdentityFile == "" {
 		return ""
 	}
 
-	aud := must(url.Parse(c.address))
+	aud := must(url.Parse(address))
 	if aud.Scheme == "" {
 		aud.Scheme = "https"
 	}
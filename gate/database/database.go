@@ -30,6 +30,7 @@ type Adapter struct {
 	InitInventory    func(Context, Endpoint) (model.Inventory, error)
 	InitSourceCache  func(Context, Endpoint) (model.SourceCache, error)
 	InitNonceChecker func(Context, Endpoint) (model.NonceChecker, error)
+	InitKeyValue     func(Context, Endpoint) (model.KeyValueStore, error)
 }
 
 func (a *Adapter) String() string {
@@ -84,6 +85,16 @@ func NewNonceCheckerConfigs() map[string]Config {
 	return c
 }
 
+func NewKeyValueConfigs() map[string]Config {
+	c := make(map[string]Config, len(adapters))
+	for _, a := range adapters {
+		if a.InitKeyValue != nil {
+			c[a.Name] = a.NewConfig()
+		}
+	}
+	return c
+}
+
 var ErrNoConfig = errors.New("no database configuration")
 
 func resolveConfig(configs map[string]Config) (string, Config, error) {
@@ -198,3 +209,12 @@ func (db *DB) InitNonceChecker(ctx Context) (model.NonceChecker, error) {
 
 	return db.Adapter.InitNonceChecker(ctx, x)
 }
+
+func (db *DB) InitKeyValue(ctx Context) (model.KeyValueStore, error) {
+	x, err := db.get()
+	if err != nil {
+		return nil, err
+	}
+
+	return db.Adapter.InitKeyValue(ctx, x)
+}
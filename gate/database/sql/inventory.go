@@ -9,6 +9,8 @@ import (
 	"errors"
 
 	"gate.computer/gate/principal"
+	"gate.computer/gate/server/model"
+	internal "gate.computer/internal/principal"
 	"google.golang.org/protobuf/proto"
 
 	. "import.name/type/context"
@@ -79,6 +81,14 @@ func (x *Endpoint) RemoveInstance(ctx Context, pri principal.ID, instance string
 	return err
 }
 
+func (x *Endpoint) ListModules(ctx Context) ([]model.InventoryKey, error) {
+	return x.listInventory(ctx, "SELECT principal, module FROM module ORDER BY principal, module")
+}
+
+func (x *Endpoint) ListInstances(ctx Context) ([]model.InventoryKey, error) {
+	return x.listInventory(ctx, "SELECT principal, instance FROM instance ORDER BY principal, instance")
+}
+
 func (x *Endpoint) getInventory(ctx Context, msg proto.Message, query string, pri principal.ID, resource string) (bool, error) {
 	var buf []byte
 
@@ -101,3 +111,29 @@ func (x *Endpoint) modifyInventory(ctx Context, query string, pri principal.ID,
 	_, err = x.db.ExecContext(ctx, query, pri.String(), resource, buf)
 	return err
 }
+
+func (x *Endpoint) listInventory(ctx Context, query string) ([]model.InventoryKey, error) {
+	rows, err := x.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []model.InventoryKey
+
+	for rows.Next() {
+		var pri, key string
+		if err := rows.Scan(&pri, &key); err != nil {
+			return nil, err
+		}
+
+		id, err := internal.ParseID(pri)
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, model.InventoryKey{Principal: id, Key: key})
+	}
+
+	return keys, rows.Err()
+}
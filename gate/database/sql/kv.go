@@ -0,0 +1,189 @@
+// Copyright (c) 2026 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sql
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+
+	"gate.computer/gate/principal"
+	"gate.computer/gate/server/model"
+
+	. "import.name/type/context"
+)
+
+const KeyValueSchema = `
+CREATE TABLE IF NOT EXISTS kv (
+	principal TEXT NOT NULL,
+	module TEXT NOT NULL,
+	key BLOB NOT NULL,
+	value BLOB NOT NULL,
+
+	PRIMARY KEY (principal, module, key)
+) WITHOUT ROWID, STRICT;
+
+CREATE TABLE IF NOT EXISTS kv_usage (
+	principal TEXT NOT NULL,
+	size BIGINT NOT NULL,
+
+	PRIMARY KEY (principal)
+) WITHOUT ROWID, STRICT;
+`
+
+func (x *Endpoint) InitKeyValue(ctx Context) error {
+	_, err := x.db.ExecContext(ctx, x.adjustSchema(KeyValueSchema))
+	return err
+}
+
+func (x *Endpoint) GetValue(ctx Context, pri principal.ID, module string, key []byte) ([]byte, bool, error) {
+	var value []byte
+
+	q := "SELECT value FROM kv WHERE principal = $1 AND module = $2 AND key = $3"
+	if err := x.db.QueryRowContext(ctx, q, pri.String(), module, key).Scan(&value); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+
+	return value, true, nil
+}
+
+// PutValue checks the quota against the principal's usage row, which is
+// locked for the duration of the transaction so that concurrent updates by
+// the same principal cannot exceed it.
+func (x *Endpoint) PutValue(ctx Context, pri principal.ID, module string, key, value []byte, maxTotalSize int) error {
+	tx, err := x.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	oldSize, _, err := lockValueSize(ctx, tx, pri, module, key)
+	if err != nil {
+		return err
+	}
+
+	delta := int64(len(key)) + int64(len(value)) - oldSize
+
+	q := "UPDATE kv_usage SET size = size + $2 WHERE principal = $1 AND ($2 <= 0 OR size + $2 <= $3)"
+	result, err := tx.ExecContext(ctx, q, pri.String(), delta, maxTotalSize)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return model.ErrKeyValueQuota
+	}
+
+	q = "INSERT INTO kv (principal, module, key, value) VALUES ($1, $2, $3, $4) ON CONFLICT (principal, module, key) DO UPDATE SET value = excluded.value"
+	if _, err := tx.ExecContext(ctx, q, pri.String(), module, key, value); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (x *Endpoint) DeleteValue(ctx Context, pri principal.ID, module string, key []byte) (bool, error) {
+	tx, err := x.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	oldSize, found, err := lockValueSize(ctx, tx, pri, module, key)
+	if err != nil || !found {
+		return false, err
+	}
+
+	q := "DELETE FROM kv WHERE principal = $1 AND module = $2 AND key = $3"
+	if _, err := tx.ExecContext(ctx, q, pri.String(), module, key); err != nil {
+		return false, err
+	}
+
+	q = "UPDATE kv_usage SET size = size - $2 WHERE principal = $1"
+	if _, err := tx.ExecContext(ctx, q, pri.String(), oldSize); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+// lockValueSize locks the principal's usage row (creating it if necessary)
+// and returns the size of an existing entry.  The entry is read after
+// locking so that it cannot be modified concurrently.
+func lockValueSize(ctx Context, tx *sql.Tx, pri principal.ID, module string, key []byte) (size int64, found bool, err error) {
+	q := "INSERT INTO kv_usage (principal, size) VALUES ($1, 0) ON CONFLICT (principal) DO NOTHING"
+	if _, err := tx.ExecContext(ctx, q, pri.String()); err != nil {
+		return 0, false, err
+	}
+
+	q = "UPDATE kv_usage SET size = size WHERE principal = $1"
+	if _, err := tx.ExecContext(ctx, q, pri.String()); err != nil {
+		return 0, false, err
+	}
+
+	q = "SELECT LENGTH(key) + LENGTH(value) FROM kv WHERE principal = $1 AND module = $2 AND key = $3"
+	if err := tx.QueryRowContext(ctx, q, pri.String(), module, key).Scan(&size); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+
+	return size, true, nil
+}
+
+func (x *Endpoint) ListKeys(ctx Context, pri principal.ID, module string, prefix, after []byte, limit int) ([][]byte, error) {
+	start := prefix
+	if len(after) > 0 && bytes.Compare(after, start) >= 0 {
+		start = append(bytes.Clone(after), 0) // Smallest key greater than after.
+	}
+	if start == nil {
+		start = []byte{} // Not NULL.
+	}
+
+	var rows *sql.Rows
+	var err error
+
+	if end := prefixEnd(prefix); end != nil {
+		q := "SELECT key FROM kv WHERE principal = $1 AND module = $2 AND key >= $3 AND key < $4 ORDER BY key LIMIT $5"
+		rows, err = x.db.QueryContext(ctx, q, pri.String(), module, start, end, limit)
+	} else {
+		q := "SELECT key FROM kv WHERE principal = $1 AND module = $2 AND key >= $3 ORDER BY key LIMIT $4"
+		rows, err = x.db.QueryContext(ctx, q, pri.String(), module, start, limit)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys [][]byte
+
+	for rows.Next() {
+		var key []byte
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+// prefixEnd returns the smallest key which is greater than all keys with the
+// prefix, or nil if there is no such key.
+func prefixEnd(prefix []byte) []byte {
+	end := bytes.Clone(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	return nil
+}
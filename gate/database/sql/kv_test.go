@@ -0,0 +1,147 @@
+// Copyright (c) 2026 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sql
+
+import (
+	"context"
+	"errors"
+	"path"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"gate.computer/gate/principal"
+	"gate.computer/gate/server/model"
+	"github.com/stretchr/testify/assert"
+
+	_ "modernc.org/sqlite"
+)
+
+func newTestEndpoint(t *testing.T) *Endpoint {
+	t.Helper()
+
+	x, err := Open(Config{
+		Driver: "sqlite",
+		DSN:    "file:" + path.Join(t.TempDir(), "test.sqlite") + "?_pragma=busy_timeout(10000)",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { x.Close() })
+	return x
+}
+
+func TestKeyValue(t *testing.T) {
+	ctx := context.Background()
+	x := newTestEndpoint(t)
+	if err := x.InitKeyValue(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	pri := principal.ContextID(principal.ContextWithLocalID(ctx))
+	const quota = 20
+
+	if _, found, err := x.GetValue(ctx, *pri, "", []byte("a")); err != nil || found {
+		t.Fatal(found, err)
+	}
+
+	for _, k := range []string{"a", "b1", "b2", "c"} {
+		if err := x.PutValue(ctx, *pri, "", []byte(k), []byte("xy"), quota); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := x.PutValue(ctx, *pri, "mod", []byte("b3"), []byte("z"), quota); err != nil {
+		t.Fatal(err)
+	}
+
+	value, found, err := x.GetValue(ctx, *pri, "", []byte("b1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, found)
+	assert.Equal(t, []byte("xy"), value)
+
+	// Total size is 17 bytes; replacing a value counts only the new value.
+	if err := x.PutValue(ctx, *pri, "", []byte("a"), []byte("xyzw"), quota); err != nil {
+		t.Fatal(err)
+	}
+	if err := x.PutValue(ctx, *pri, "", []byte("d"), []byte("xy"), quota); !errors.Is(err, model.ErrKeyValueQuota) {
+		t.Error(err)
+	}
+
+	keys, err := x.ListKeys(ctx, *pri, "", []byte("b"), nil, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, [][]byte{[]byte("b1"), []byte("b2")}, keys)
+
+	keys, err = x.ListKeys(ctx, *pri, "", nil, []byte("a"), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, [][]byte{[]byte("b1"), []byte("b2")}, keys)
+
+	keys, err = x.ListKeys(ctx, *pri, "mod", nil, nil, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, [][]byte{[]byte("b3")}, keys)
+
+	deleted, err := x.DeleteValue(ctx, *pri, "", []byte("b1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, deleted)
+
+	deleted, err = x.DeleteValue(ctx, *pri, "", []byte("b1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.False(t, deleted)
+
+	// Deletion released 4 bytes.
+	if err := x.PutValue(ctx, *pri, "", []byte("d"), []byte("xy"), quota); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestKeyValueConcurrentQuota(t *testing.T) {
+	ctx := context.Background()
+	x := newTestEndpoint(t)
+	if err := x.InitKeyValue(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	pri := principal.ContextID(principal.ContextWithLocalID(ctx))
+	const quota = 10
+
+	var (
+		wg sync.WaitGroup
+		ok atomic.Int32
+	)
+	for i := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			switch err := x.PutValue(ctx, *pri, "", []byte{byte('a' + i)}, []byte("xy"), quota); {
+			case err == nil:
+				ok.Add(1)
+			case !errors.Is(err, model.ErrKeyValueQuota):
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(quota/3), ok.Load())
+}
+
+func TestPrefixEnd(t *testing.T) {
+	assert.Equal(t, []byte("b"), prefixEnd([]byte("a")))
+	assert.Equal(t, []byte("b"), prefixEnd([]byte("a\xff")))
+	assert.Nil(t, prefixEnd([]byte("\xff\xff")))
+	assert.Nil(t, prefixEnd(nil))
+}
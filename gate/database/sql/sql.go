@@ -112,4 +112,12 @@ var Adapter = database.Register(&database.Adapter{
 		}
 		return x, nil
 	},
+
+	InitKeyValue: func(ctx Context, endpoint database.Endpoint) (model.KeyValueStore, error) {
+		x := endpoint.(*Endpoint)
+		if err := x.InitKeyValue(ctx); err != nil {
+			return nil, err
+		}
+		return x, nil
+	},
 })
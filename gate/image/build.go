@@ -266,6 +266,7 @@ func (b *Build) FinishProgram(sectionMap SectionMap, mod compile.Module, startFu
 	}
 
 	man := &pb.ProgramManifest{
+		Version:                 ManifestVersion,
 		LibraryChecksum:         abi.LibraryChecksum(),
 		TextRevision:            TextRevision,
 		TextAddr:                b.textAddr,
@@ -332,15 +333,18 @@ func (b *Build) FinishInstance(prog *Program, maxMemorySize, entryFuncIndex int)
 
 	inst := &Instance{
 		man: &pb.InstanceManifest{
-			TextAddr:      b.textAddr,
-			StackSize:     uint32(b.inst.stackSize),
-			StackUsage:    uint32(b.stackUsage),
-			GlobalsSize:   uint32(b.globalsSize),
-			MemorySize:    uint32(b.memorySize),
-			MaxMemorySize: uint32(maxMemorySize),
-			StartFunc:     prog.man.StartFunc,
-			EntryFunc:     programEntryFunc(prog.man, entryFuncIndex),
-			Snapshot:      snapshot.Clone(prog.man.Snapshot),
+			Version:         ManifestVersion,
+			LibraryChecksum: prog.man.LibraryChecksum,
+			TextRevision:    prog.man.TextRevision,
+			TextAddr:        b.textAddr,
+			StackSize:       uint32(b.inst.stackSize),
+			StackUsage:      uint32(b.stackUsage),
+			GlobalsSize:     uint32(b.globalsSize),
+			MemorySize:      uint32(b.memorySize),
+			MaxMemorySize:   uint32(maxMemorySize),
+			StartFunc:       prog.man.StartFunc,
+			EntryFunc:       programEntryFunc(prog.man, entryFuncIndex),
+			Snapshot:        snapshot.Clone(prog.man.Snapshot),
 		},
 		file:     b.inst.file,
 		coherent: true,
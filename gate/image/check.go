@@ -0,0 +1,211 @@
+// Copyright (c) 2026 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package image
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"syscall"
+
+	"gate.computer/internal/file"
+	pb "gate.computer/internal/pb/image"
+	"golang.org/x/sys/unix"
+	"google.golang.org/protobuf/proto"
+)
+
+// ErrCorrupt is wrapped by errors of stored files which cannot be used.
+var ErrCorrupt = errors.New("image: corrupt file")
+
+// Problem with a stored program or instance file.
+type Problem struct {
+	Instance bool // Program file if false.
+	Name     string
+	Err      error // Wraps ErrCorrupt or ErrIncompatible if file is unusable.
+}
+
+func (p *Problem) Error() string {
+	kind := "program"
+	if p.Instance {
+		kind = "instance"
+	}
+	return fmt.Sprintf("%s %s: %v", kind, p.Name, p.Err)
+}
+
+func (p *Problem) Unwrap() error {
+	return p.Err
+}
+
+// Check stored program and instance files.  Manifests are verified against
+// file sizes and layout, programs which must be recompiled are reported with
+// errors wrapping ErrRecompile, and instances which cannot be resumed with
+// errors wrapping ErrIncompatible.  Incremental snapshots of corrupt programs
+// are reported as corrupt.  The storage should not be in use.
+func (fs *Filesystem) Check() ([]*Problem, error) {
+	progs, err := fs.Programs()
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(progs)
+
+	insts, err := fs.Instances()
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(insts)
+
+	var (
+		problems []*Problem
+		parents  = make(map[string]string)
+		corrupt  = make(map[string]bool)
+	)
+
+	for _, name := range progs {
+		if err := fs.checkProgram(name, progs, parents); err != nil {
+			problems = append(problems, &Problem{false, name, err})
+			if errors.Is(err, ErrCorrupt) {
+				corrupt[name] = true
+			}
+		}
+	}
+
+	// Incremental snapshots of corrupt programs are unusable.
+	for changed := true; changed; {
+		changed = false
+		for _, name := range progs {
+			if parent := parents[name]; corrupt[parent] && !corrupt[name] {
+				problems = append(problems, &Problem{false, name, corruptError(fmt.Sprintf("parent program %s is corrupt", parent))})
+				corrupt[name] = true
+				changed = true
+			}
+		}
+	}
+
+	for _, name := range insts {
+		if err := fs.checkInstance(name); err != nil {
+			problems = append(problems, &Problem{true, name, err})
+		}
+	}
+
+	return problems, nil
+}
+
+// checkProgram records the parent of an incremental snapshot in parents.
+func (fs *Filesystem) checkProgram(name string, names []string, parents map[string]string) error {
+	man := new(pb.ProgramManifest)
+	if err := checkManifestFile(fs.progDir, name, man, progManifestOffset, progMaxOffset, programFileTag); err != nil {
+		return err
+	}
+
+	if man.Parent != "" {
+		parents[name] = man.Parent
+	}
+
+	if err := checkManifestVersion("program", name, man.Version); err != nil {
+		return err
+	}
+
+	if alignPageOffset32(man.TextSize)+alignPageOffset32(man.StackUsage) > progGlobalsOffset-progTextOffset {
+		return corruptError("text and stack exceed their region")
+	}
+
+	dataSize := alignPageOffset32(man.GlobalsSize) + alignPageOffset32(man.MemoryDataSize)
+	for _, r := range man.MemoryDelta {
+		if r.Start < 0 {
+			return corruptError("negative memory delta offset")
+		}
+		dataSize = max(dataSize, alignPageOffset32(man.GlobalsSize)+r.Start+int64(r.Size))
+	}
+	if dataSize > progModuleOffset-progGlobalsOffset {
+		return corruptError("globals and memory exceed their region")
+	}
+
+	if progModuleOffset+align8(man.ModuleSize)+int64(man.CallSitesSize)+int64(man.FuncAddrsSize) > progManifestOffset {
+		return corruptError("module and object map exceed their region")
+	}
+
+	if man.Parent != "" {
+		if _, found := slices.BinarySearch(names, man.Parent); !found {
+			return corruptError(fmt.Sprintf("parent program %s not found", man.Parent))
+		}
+	}
+
+	return checkProgramText(name, man)
+}
+
+func (fs *Filesystem) checkInstance(name string) error {
+	man := new(pb.InstanceManifest)
+	if err := checkManifestFile(fs.instDir, name, man, instManifestOffset, instMaxOffset, instanceFileTag); err != nil {
+		return err
+	}
+
+	if err := checkManifestVersion("instance", name, man.Version); err != nil {
+		return err
+	}
+
+	if man.StackUsage > man.StackSize {
+		return corruptError("stack usage exceeds stack size")
+	}
+	if man.MemorySize > man.MaxMemorySize {
+		return corruptError("memory size exceeds maximum")
+	}
+	if int64(man.StackSize)+alignPageOffset32(man.GlobalsSize)+int64(man.MaxMemorySize) > instManifestOffset-instStackOffset {
+		return corruptError("stack, globals and memory exceed their region")
+	}
+
+	inst := &Instance{man: man, name: name}
+	return inst.CheckCompatibility()
+}
+
+// checkManifestFile reads the manifest of a file which must not be truncated.
+func checkManifestFile(dir *file.File, name string, man proto.Message, offset, size int64, tag uint32) error {
+	f, err := openat(dir.FD(), name, syscall.O_RDONLY, 0)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var st unix.Stat_t
+	if err := unix.Fstat(f.FD(), &st); err != nil {
+		return err
+	}
+	if st.Size < size {
+		return corruptError(fmt.Sprintf("file size %d is less than %d", st.Size, size))
+	}
+
+	if err := unmarshalManifest(f, man, offset, tag); err != nil {
+		return fmt.Errorf("%w: %w", ErrCorrupt, err)
+	}
+	return nil
+}
+
+func corruptError(s string) error {
+	return fmt.Errorf("%w: %s", ErrCorrupt, s)
+}
+
+// QuarantineProgram moves a stored program file aside so that it is not
+// listed or loaded.  The file is kept in the program directory with a
+// hidden name.
+func (fs *Filesystem) QuarantineProgram(name string) error {
+	return quarantine(fs.progDir, name)
+}
+
+// QuarantineInstance moves a stored instance file aside so that it is not
+// listed or loaded.  The file is kept in the instance directory with a
+// hidden name.
+func (fs *Filesystem) QuarantineInstance(name string) error {
+	return quarantine(fs.instDir, name)
+}
+
+func quarantine(dir *file.File, name string) error {
+	if err := unix.Renameat(dir.FD(), name, dir.FD(), quarantineNamePrefix+name); err != nil {
+		if err == syscall.ENOENT {
+			return os.ErrNotExist
+		}
+		return fmt.Errorf("renameat %q: %w", name, err)
+	}
+	return fdatasync(dir.FD())
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2026 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package image
+
+import (
+	"errors"
+	"os"
+	"path"
+	"slices"
+	"testing"
+)
+
+func TestFilesystemCheckQuarantine(t *testing.T) {
+	root := t.TempDir()
+
+	fs, err := NewFilesystem(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fs.Close()
+
+	if err := os.WriteFile(path.Join(root, "program", "truncated"), []byte("garbage"), 0o400); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path.Join(root, "instance", "truncated"), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	problems, err := fs.Check()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(problems) != 2 {
+		t.Fatal(problems)
+	}
+	for i, p := range problems {
+		if p.Instance != (i == 1) || p.Name != "truncated" || !errors.Is(p, ErrCorrupt) {
+			t.Error(p)
+		}
+	}
+
+	if err := fs.QuarantineProgram("truncated"); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.QuarantineInstance("truncated"); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.QuarantineInstance("truncated"); !errors.Is(err, os.ErrNotExist) {
+		t.Error(err)
+	}
+
+	problems, err = fs.Check()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(problems) != 0 {
+		t.Error(problems)
+	}
+
+	if _, err := os.Stat(path.Join(root, "program", quarantineNamePrefix+"truncated")); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestFilesystemCheckCorruptParent(t *testing.T) {
+	root := t.TempDir()
+
+	fs, err := NewFilesystem(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fs.Close()
+
+	if err := os.WriteFile(path.Join(root, "program", "parent"), []byte("garbage"), 0o400); err != nil {
+		t.Fatal(err)
+	}
+	storeTestProgram(t, fs, "snapshot1", "parent")
+	storeTestProgram(t, fs, "snapshot0", "snapshot1") // Checked before its parent.
+	storeTestProgram(t, fs, "unrelated", "")
+
+	problems, err := fs.Check()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, p := range problems {
+		if p.Instance || !errors.Is(p, ErrCorrupt) {
+			t.Error(p)
+		}
+		names = append(names, p.Name)
+	}
+	slices.Sort(names)
+	if !slices.Equal(names, []string{"parent", "snapshot0", "snapshot1"}) {
+		t.Error(names)
+	}
+}
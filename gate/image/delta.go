@@ -0,0 +1,197 @@
+// Copyright (c) 2026 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package image
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"syscall"
+
+	"gate.computer/gate/snapshot"
+	"gate.computer/internal/executable"
+	"gate.computer/internal/file"
+	pb "gate.computer/internal/pb/image"
+	"golang.org/x/sys/unix"
+)
+
+// maxDeltaRangeSize limits the size of a pb.ByteRange in memory delta.
+const maxDeltaRangeSize = 1 << 30
+
+// IncrementalSnapshot is like Snapshot, but the memory image of the new
+// program contains only the pages which differ from the base program.  The
+// new program refers to the base program by name, so the base program must
+// stay stored as long as the new program is stored.  A complete snapshot is
+// created if the base program hasn't been stored.
+//
+// The WebAssembly module of the new program is complete.
+func IncrementalSnapshot(oldProg *Program, inst *Instance, buffers *snapshot.Buffers, suspended bool, base *Program) (newProg *Program, err error) {
+	if base == nil || base.name == "" {
+		return Snapshot(oldProg, inst, buffers, suspended)
+	}
+
+	err = z.Recover(func() {
+		s := mustNewState(oldProg, inst, buffers, suspended)
+		defer s.mustClose()
+		s.base = base
+		m := mustNewModuleState(s)
+		newProg = mustSerializeState(s, m)
+	})
+	return
+}
+
+// MissingParentError is returned when the parent program of a stored
+// incremental snapshot is not found.  It wraps ErrCorrupt.
+type MissingParentError struct {
+	Program string
+	Parent  string
+}
+
+func (e *MissingParentError) Error() string {
+	return fmt.Sprintf("%v: parent program %s of %s not found", ErrCorrupt, e.Parent, e.Program)
+}
+
+func (e *MissingParentError) Unwrap() error { return ErrCorrupt }
+
+// Parent program name of an incremental snapshot.
+func (prog *Program) Parent() string { return prog.man.Parent }
+
+// MemoryDeltaSize is the number of memory bytes stored by an incremental
+// snapshot.  It is zero for other programs.
+func (prog *Program) MemoryDeltaSize() (n int64) {
+	for _, r := range prog.man.MemoryDelta {
+		n += int64(r.Size)
+	}
+	return
+}
+
+func (prog *Program) memoryOffset() int64 {
+	return progGlobalsOffset + alignPageOffset32(prog.man.GlobalsSize)
+}
+
+// mustMemoryDelta compares memory contents against the base program page by
+// page.
+func mustMemoryDelta(base *Program, memory []byte) (delta []*pb.ByteRange) {
+	var (
+		page = make([]byte, executable.PageSize)
+		r    *pb.ByteRange
+	)
+
+	for offset := 0; offset < len(memory); offset += len(page) {
+		b := memory[offset:min(offset+len(page), len(memory))]
+
+		z.Check(base.readMemoryPage(page[:len(b)], int64(offset)))
+		if bytes.Equal(page[:len(b)], b) {
+			r = nil
+			continue
+		}
+
+		if r == nil || r.Size+uint32(len(page)) > maxDeltaRangeSize {
+			r = &pb.ByteRange{Start: int64(offset)}
+			delta = append(delta, r)
+		}
+		r.Size += uint32(len(page))
+	}
+
+	return
+}
+
+// readMemoryPage at page-aligned offset.  Unchanged pages of an incremental
+// snapshot are read from the parent.
+func (prog *Program) readMemoryPage(b []byte, offset int64) error {
+	if offset >= alignPageOffset32(prog.man.MemoryDataSize) {
+		clear(b)
+		return nil
+	}
+
+	if prog.parent != nil && !deltaContains(prog.man.MemoryDelta, offset) {
+		return prog.parent.readMemoryPage(b, offset)
+	}
+
+	_, err := prog.file.ReadAt(b, prog.memoryOffset()+offset)
+	return err
+}
+
+func deltaContains(delta []*pb.ByteRange, offset int64) bool {
+	i := sort.Search(len(delta), func(i int) bool {
+		return delta[i].Start+int64(delta[i].Size) > offset
+	})
+	return i < len(delta) && delta[i].Start <= offset
+}
+
+// copyMemory contents to instance file.  Parent contents are copied first,
+// and then overwritten by the changed pages.
+func (prog *Program) copyMemory(instFile *file.File, instOffset int64, size int, write bool) error {
+	size = min(size, alignPageSize32(prog.man.MemoryDataSize))
+
+	if prog.parent == nil {
+		return copyToInstance(prog.file, prog.memoryOffset(), instFile, instOffset, size, write)
+	}
+
+	if err := prog.parent.copyMemory(instFile, instOffset, size, write); err != nil {
+		return err
+	}
+
+	for _, r := range prog.man.MemoryDelta {
+		if r.Start >= int64(size) {
+			break
+		}
+
+		n := min(int64(r.Size), int64(size)-r.Start)
+		if err := copyToInstance(prog.file, prog.memoryOffset()+r.Start, instFile, instOffset+r.Start, int(n), write); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// copyToInstance copies a page-aligned range from program file to instance
+// file.  Write indicates if the instance file supports writing.
+func copyToInstance(progFile *file.File, progOffset int64, instFile *file.File, instOffset int64, length int, write bool) error {
+	if length == 0 {
+		return nil
+	}
+
+	if write {
+		return copyFileRange(progFile, &progOffset, instFile, &instOffset, length)
+	}
+
+	dest, err := mmap(instFile.FD(), instOffset, length, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+	if err != nil {
+		return err
+	}
+	defer mustMunmap(dest)
+
+	_, err = progFile.ReadAt(dest, progOffset)
+	return err
+}
+
+// dup duplicates program file descriptors, so that the copy can be closed
+// independently.
+func (prog *Program) dup() (*Program, error) {
+	fd, err := unix.FcntlInt(prog.file.Fd(), unix.F_DUPFD_CLOEXEC, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	clone := &Program{
+		Map:     prog.Map,
+		storage: prog.storage,
+		man:     prog.man,
+		file:    file.New(fd),
+		name:    prog.name,
+	}
+
+	if prog.parent != nil {
+		clone.parent, err = prog.parent.dup()
+		if err != nil {
+			clone.Close()
+			return nil, err
+		}
+	}
+
+	return clone, nil
+}
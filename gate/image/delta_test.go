@@ -0,0 +1,156 @@
+// Copyright (c) 2026 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package image
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"gate.computer/gate/runtime/abi"
+	"gate.computer/internal/executable"
+	pb "gate.computer/internal/pb/image"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryDelta(t *testing.T) {
+	pageSize := executable.PageSize
+
+	f, err := Memory.newProgramFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	base := &Program{
+		storage: Memory,
+		man: &pb.ProgramManifest{
+			GlobalsSize:    16,
+			MemoryDataSize: uint32(3 * pageSize),
+		},
+		file: f,
+		name: "base",
+	}
+	defer base.Close()
+
+	baseMemory := bytes.Repeat([]byte{1}, 3*pageSize)
+	if _, err := f.WriteAt(baseMemory, base.memoryOffset()); err != nil {
+		t.Fatal(err)
+	}
+
+	memory := bytes.Repeat([]byte{1}, 5*pageSize)
+	memory[pageSize+10] = 2
+	memory[2*pageSize] = 3
+	clear(memory[3*pageSize : 4*pageSize]) // Beyond base memory.
+	memory[4*pageSize] = 4
+
+	delta := mustMemoryDelta(base, memory)
+	assert.Equal(t, 2, len(delta))
+	assert.Equal(t, int64(pageSize), delta[0].Start)
+	assert.Equal(t, uint32(2*pageSize), delta[0].Size)
+	assert.Equal(t, int64(4*pageSize), delta[1].Start)
+	assert.Equal(t, uint32(pageSize), delta[1].Size)
+
+	assert.False(t, deltaContains(delta, 0))
+	assert.True(t, deltaContains(delta, int64(2*pageSize)))
+	assert.True(t, deltaContains(delta, int64(4*pageSize)))
+	assert.False(t, deltaContains(delta, int64(3*pageSize)))
+	assert.False(t, deltaContains(delta, int64(5*pageSize)))
+
+	childFile, err := Memory.newProgramFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	child := &Program{
+		storage: Memory,
+		man: &pb.ProgramManifest{
+			GlobalsSize:    16,
+			MemoryDataSize: uint32(len(memory)),
+			Parent:         base.name,
+			MemoryDelta:    delta,
+		},
+		file: childFile,
+	}
+	child.parent, err = base.dup()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer child.Close()
+
+	for _, r := range delta {
+		if _, err := childFile.WriteAt(memory[r.Start:r.Start+int64(r.Size)], child.memoryOffset()+r.Start); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	assert.Equal(t, int64(3*pageSize), child.MemoryDeltaSize())
+	assert.Equal(t, 0, len(mustMemoryDelta(child, memory)))
+
+	instFile, err := Memory.newInstanceFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer instFile.Close()
+
+	if err := child.copyMemory(instFile, 0, len(memory), Memory.instanceFileWriteSupported()); err != nil {
+		t.Fatal(err)
+	}
+
+	b := make([]byte, len(memory))
+	if _, err := instFile.ReadAt(b, 0); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, memory, b)
+}
+
+func TestMissingParent(t *testing.T) {
+	fs, err := NewFilesystem(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fs.Close()
+
+	storeTestProgram(t, fs, "child", "base")
+
+	prog, err := fs.LoadProgram("child")
+	if err == nil {
+		prog.Close()
+		t.Fatal("no error")
+	}
+
+	var missing *MissingParentError
+	if !errors.As(err, &missing) || missing.Program != "child" || missing.Parent != "base" {
+		t.Error(err)
+	}
+	if !errors.Is(err, ErrCorrupt) {
+		t.Error(err)
+	}
+}
+
+// storeTestProgram with an empty memory image.
+func storeTestProgram(t *testing.T, fs *Filesystem, name, parent string) {
+	t.Helper()
+
+	f, err := fs.newProgramFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prog := &Program{
+		storage: fs,
+		man: &pb.ProgramManifest{
+			Version:         ManifestVersion,
+			LibraryChecksum: abi.LibraryChecksum(),
+			TextRevision:    TextRevision,
+			Parent:          parent,
+		},
+		file: f,
+	}
+	defer prog.Close()
+
+	if err := prog.Store(name); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -6,13 +6,14 @@ package image
 
 import (
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path"
+	"strings"
 	"syscall"
 
-	"gate.computer/gate/runtime/abi"
 	"gate.computer/internal/file"
 	pb "gate.computer/internal/pb/image"
 	"gate.computer/wag/object"
@@ -21,9 +22,11 @@ import (
 )
 
 const (
-	programFileTag     = 0x4a5274bd
-	instanceFileTag    = 0xb405dd05
-	manifestHeaderSize = 8
+	programFileTag       = 0x4a5274bd
+	instanceFileTag      = 0xb405dd05
+	manifestHeaderSize   = 8
+	upgradeNamePrefix    = ".upgrade-"
+	quarantineNamePrefix = ".quarantine-"
 )
 
 // Filesystem implements Storage.  It supports program and instance
@@ -119,12 +122,41 @@ func (fs *Filesystem) storeProgram(prog *Program, name string) error {
 	if err := fdatasync(prog.file.FD()); err != nil {
 		return err
 	}
-	if err := linkTempFile(prog.file.Fd(), fs.progDir.Fd(), name); err != nil && !os.IsExist(err) {
-		return err
+	if err := linkTempFile(prog.file.Fd(), fs.progDir.Fd(), name); err != nil {
+		if !os.IsExist(err) {
+			return err
+		}
+		if err := fs.replaceStaleProgram(prog, name); err != nil {
+			return err
+		}
 	}
 	return fdatasync(fs.progDir.FD())
 }
 
+// replaceStaleProgram replaces an existing program file if it must be
+// recompiled.  A fresh file is left alone.
+func (fs *Filesystem) replaceStaleProgram(prog *Program, name string) error {
+	f, err := openat(int(fs.progDir.Fd()), name, syscall.O_RDONLY, 0)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	man := new(pb.ProgramManifest)
+	if err := unmarshalManifest(f, man, progManifestOffset, programFileTag); err == nil {
+		if err := checkProgramText(name, man); !errors.Is(err, ErrRecompile) {
+			return nil
+		}
+	}
+
+	tmpName := upgradeNamePrefix + name
+	syscall.Unlinkat(int(fs.progDir.Fd()), tmpName) // Left over from crash.
+	if err := linkTempFile(prog.file.Fd(), fs.progDir.Fd(), tmpName); err != nil {
+		return err
+	}
+	return unix.Renameat(int(fs.progDir.Fd()), tmpName, int(fs.progDir.Fd()), name)
+}
+
 func (fs *Filesystem) Programs() ([]string, error) {
 	return fs.listNames(fs.progDir.Fd())
 }
@@ -156,8 +188,11 @@ func (fs *Filesystem) loadProgram(storage Storage, name string) (*Program, error
 		return nil, err
 	}
 
-	if prog.man.LibraryChecksum != abi.LibraryChecksum() {
-		return nil, nil
+	if err := checkManifestVersion("program", name, prog.man.Version); err != nil {
+		return nil, err
+	}
+	if err := checkProgramText(name, prog.man); err != nil {
+		return nil, err
 	}
 
 	var (
@@ -177,11 +212,62 @@ func (fs *Filesystem) loadProgram(storage Storage, name string) (*Program, error
 		return nil, err
 	}
 
+	if prog.man.Parent != "" {
+		parent, err := storage.LoadProgram(prog.man.Parent)
+		if err != nil {
+			return nil, fmt.Errorf("parent program %s of %s: %w", prog.man.Parent, name, err)
+		}
+		if parent == nil {
+			return nil, &MissingParentError{name, prog.man.Parent}
+		}
+		prog.parent = parent
+	}
+
 	prog.file = f
+	prog.name = name
 	f = nil
 	return prog, nil
 }
 
+// LoadProgramModule opens the WebAssembly module of a stored program without
+// checking its compatibility.
+func (fs *Filesystem) LoadProgramModule(name string) (io.ReadCloser, int64, error) {
+	f, err := openat(int(fs.progDir.Fd()), name, syscall.O_RDONLY, 0)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	man := new(pb.ProgramManifest)
+	if err := unmarshalManifest(f, man, progManifestOffset, programFileTag); err != nil {
+		f.Close()
+		return nil, 0, err
+	}
+
+	r := io.NewSectionReader(f, progModuleOffset, man.ModuleSize)
+	return moduleReadCloser{r, f}, man.ModuleSize, nil
+}
+
+type moduleReadCloser struct {
+	*io.SectionReader
+	f *file.File
+}
+
+func (r moduleReadCloser) Close() error {
+	return r.f.Close()
+}
+
+// RemoveProgram unlinks a stored program file.  Open programs remain usable.
+// Removing a nonexistent program is not an error.
+func (fs *Filesystem) RemoveProgram(name string) error {
+	if err := syscall.Unlinkat(fs.progDir.FD(), name); err != nil {
+		if err == syscall.ENOENT {
+			return nil
+		}
+		return fmt.Errorf("unlinkat program %q: %w", name, err)
+	}
+	return fdatasync(fs.progDir.FD())
+}
+
 func (fs *Filesystem) newInstanceFile() (*file.File, error) {
 	var ok bool
 
@@ -261,6 +347,10 @@ func (fs *Filesystem) LoadInstance(name string) (*Instance, error) {
 		return nil, err
 	}
 
+	if err := checkManifestVersion("instance", name, inst.man.Version); err != nil {
+		return nil, err
+	}
+
 	ok = true
 	return inst, nil
 }
@@ -279,7 +369,7 @@ func (fs *Filesystem) listNames(dirFD uintptr) ([]string, error) {
 
 	names := make([]string, 0, len(infos))
 	for _, info := range infos {
-		if info.Mode().IsRegular() {
+		if info.Mode().IsRegular() && !strings.HasPrefix(info.Name(), ".") { // Skip temporary and quarantined files.
 			names = append(names, info.Name())
 		}
 	}
@@ -24,6 +24,7 @@ import (
 	"gate.computer/wag/object/abi"
 	"gate.computer/wag/object/stack"
 	"gate.computer/wag/wa"
+	"google.golang.org/protobuf/proto"
 )
 
 var ErrInvalidState = errors.New("instance state is invalid")
@@ -110,37 +111,35 @@ func NewInstance(prog *Program, maxMemorySize, maxStackSize, entryFuncIndex int)
 		memoryMapSize  = alignPageSize32(prog.man.MemoryDataSize)
 		off1           = progGlobalsOffset - int64(stackMapSize)
 		off2           = int64(instStackSize - stackMapSize)
-		copyLen        = stackMapSize + globalsMapSize + memoryMapSize
+		copyLen        = stackMapSize + globalsMapSize
+		write          = prog.storage.instanceFileWriteSupported()
 	)
-	if copyLen > 0 {
-		if prog.storage.instanceFileWriteSupported() {
-			if err := copyFileRange(prog.file, &off1, instFile, &off2, copyLen); err != nil {
-				return nil, err
-			}
-		} else {
-			dest, err := mmap(instFile.FD(), off2, copyLen, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
-			if err != nil {
-				return nil, err
-			}
-			defer mustMunmap(dest)
-
-			if _, err := prog.file.ReadAt(dest[:copyLen], off1); err != nil {
-				return nil, err
-			}
+	if prog.parent == nil {
+		copyLen += memoryMapSize // Contiguous.
+	}
+	if err := copyToInstance(prog.file, off1, instFile, off2, copyLen, write); err != nil {
+		return nil, err
+	}
+	if prog.parent != nil {
+		if err := prog.copyMemory(instFile, off2+int64(copyLen), memoryMapSize, write); err != nil {
+			return nil, err
 		}
 	}
 
 	inst := &Instance{
 		man: &pb.InstanceManifest{
-			TextAddr:      instTextAddr,
-			StackSize:     uint32(instStackSize),
-			StackUsage:    uint32(instStackUsage),
-			GlobalsSize:   prog.man.GlobalsSize,
-			MemorySize:    prog.man.MemorySize,
-			MaxMemorySize: uint32(maxMemorySize),
-			StartFunc:     prog.man.StartFunc,
-			EntryFunc:     programEntryFunc(prog.man, entryFuncIndex),
-			Snapshot:      snapshot.Clone(prog.man.Snapshot),
+			Version:         ManifestVersion,
+			LibraryChecksum: prog.man.LibraryChecksum,
+			TextRevision:    prog.man.TextRevision,
+			TextAddr:        instTextAddr,
+			StackSize:       uint32(instStackSize),
+			StackUsage:      uint32(instStackUsage),
+			GlobalsSize:     prog.man.GlobalsSize,
+			MemorySize:      prog.man.MemorySize,
+			MaxMemorySize:   uint32(maxMemorySize),
+			StartFunc:       prog.man.StartFunc,
+			EntryFunc:       programEntryFunc(prog.man, entryFuncIndex),
+			Snapshot:        snapshot.Clone(prog.man.Snapshot),
 		},
 		manDirty: true,
 		coherent: true,
@@ -150,6 +149,54 @@ func NewInstance(prog *Program, maxMemorySize, maxStackSize, entryFuncIndex int)
 	return inst, nil
 }
 
+// Clone the stack, globals and memory into a new instance file allocated from
+// the program's storage.  The clone is not stored.
+func (inst *Instance) Clone(prog *Program) (*Instance, error) {
+	if !inst.coherent {
+		return nil, ErrInvalidState
+	}
+
+	instFile, err := prog.storage.newInstanceFile()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if instFile != nil {
+			instFile.Close()
+		}
+	}()
+
+	var (
+		off1    = instStackOffset
+		off2    = instStackOffset
+		copyLen = int(inst.memoryOffset()) + alignPageSize32(inst.man.MemorySize)
+	)
+	if prog.storage.instanceFileWriteSupported() {
+		if err := copyFileRange(inst.file, &off1, instFile, &off2, copyLen); err != nil {
+			return nil, err
+		}
+	} else {
+		dest, err := mmap(instFile.FD(), off2, copyLen, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+		if err != nil {
+			return nil, err
+		}
+		defer mustMunmap(dest)
+
+		if _, err := inst.file.ReadAt(dest[:copyLen], off1); err != nil {
+			return nil, err
+		}
+	}
+
+	clone := &Instance{
+		man:      proto.Clone(inst.man).(*pb.InstanceManifest),
+		manDirty: true,
+		coherent: true,
+		file:     instFile,
+	}
+	instFile = nil
+	return clone, nil
+}
+
 func (inst *Instance) SetEntryFunc(prog *Program, index int) error {
 	if !inst.coherent {
 		return ErrInvalidState
@@ -349,14 +396,13 @@ func (inst *Instance) checkMutation() (stackVars, error) {
 	return vars, nil
 }
 
-func (inst *Instance) Globals(prog *Program) ([]uint64, error) {
-	var (
-		instGlobalsEnd    = int64(inst.man.StackSize) + alignPageOffset32(inst.man.GlobalsSize)
-		instGlobalsOffset = instGlobalsEnd - int64(inst.man.GlobalsSize)
-	)
+func (inst *Instance) globalsOffset() int64 {
+	return inst.globalsPageOffset() + alignPageOffset32(inst.man.GlobalsSize) - int64(inst.man.GlobalsSize)
+}
 
+func (inst *Instance) Globals(prog *Program) ([]uint64, error) {
 	b := make([]byte, inst.man.GlobalsSize)
-	if _, err := inst.file.ReadAt(b, instGlobalsOffset); err != nil {
+	if _, err := inst.file.ReadAt(b, inst.globalsOffset()); err != nil {
 		return nil, err
 	}
 
@@ -368,6 +414,49 @@ func (inst *Instance) Globals(prog *Program) ([]uint64, error) {
 	return values, nil
 }
 
+// SetGlobal value.  The global must be mutable.
+func (inst *Instance) SetGlobal(prog *Program, index int, value uint64) error {
+	if !inst.coherent {
+		return ErrInvalidState
+	}
+	if index < 0 || index >= len(prog.man.GlobalTypes) {
+		return errors.New("global index out of range")
+	}
+	if !wa.GlobalType(prog.man.GlobalTypes[index]).Mutable() {
+		return errors.New("global is immutable")
+	}
+
+	b := make([]byte, 8)
+	binary.LittleEndian.PutUint64(b, value)
+
+	offset := inst.globalsOffset() + int64(inst.man.GlobalsSize) - int64(index+1)*8
+	_, err := inst.file.WriteAt(b, offset)
+	return err
+}
+
+// ReadMemory fills b with linear memory contents.
+func (inst *Instance) ReadMemory(b []byte, addr uint32) error {
+	if uint64(addr)+uint64(len(b)) > uint64(inst.man.MemorySize) {
+		return errors.New("memory range out of bounds")
+	}
+
+	_, err := inst.file.ReadAt(b, inst.memoryOffset()+int64(addr))
+	return err
+}
+
+// WriteMemory replaces linear memory contents.
+func (inst *Instance) WriteMemory(addr uint32, b []byte) error {
+	if !inst.coherent {
+		return ErrInvalidState
+	}
+	if uint64(addr)+uint64(len(b)) > uint64(inst.man.MemorySize) {
+		return errors.New("memory range out of bounds")
+	}
+
+	_, err := inst.file.WriteAt(b, inst.memoryOffset()+int64(addr))
+	return err
+}
+
 // ReplaceCallStack with a "suspended" function call with given arguments.
 // Pending start function, entry function, and existing suspended state are
 // discarded.  Arguments are not checked against function signature.
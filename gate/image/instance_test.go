@@ -9,9 +9,40 @@ import (
 	"unsafe"
 
 	internal "gate.computer/internal/executable"
+	pb "gate.computer/internal/pb/image"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestStackVars(t *testing.T) {
 	assert.Equal(t, unsafe.Sizeof(stackVars{}), uintptr(internal.StackVarsSize))
 }
+
+func TestInstanceMemory(t *testing.T) {
+	f, err := Memory.newInstanceFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inst := &Instance{
+		man: &pb.InstanceManifest{
+			StackSize:   65536,
+			GlobalsSize: 16,
+			MemorySize:  65536,
+		},
+		coherent: true,
+		file:     f,
+	}
+	defer inst.Close()
+
+	assert.NoError(t, inst.WriteMemory(100, []byte("hello")))
+
+	b := make([]byte, 7)
+	assert.NoError(t, inst.ReadMemory(b, 99))
+	assert.Equal(t, []byte("\x00hello\x00"), b)
+
+	assert.Error(t, inst.WriteMemory(65535, []byte("xy")))
+	assert.Error(t, inst.ReadMemory(b, 65530))
+
+	inst.coherent = false
+	assert.Equal(t, ErrInvalidState, inst.WriteMemory(0, []byte{1}))
+}
@@ -37,6 +37,12 @@ func (mem) Programs() (_ []string, _ error)                   { return }
 func (mem) LoadProgram(string) (_ *Program, _ error)          { return }
 func (mem) loadProgram(Storage, string) (_ *Program, _ error) { return }
 
+func (mem) LoadProgramModule(string) (io.ReadCloser, int64, error) {
+	return nil, 0, os.ErrNotExist
+}
+
+func (mem) RemoveProgram(string) error { return nil }
+
 func (mem) newInstanceFile() (*file.File, error) {
 	var ok bool
 
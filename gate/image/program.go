@@ -15,6 +15,7 @@ import (
 	"gate.computer/internal/file"
 	pb "gate.computer/internal/pb/image"
 	"gate.computer/wag/object"
+	"gate.computer/wag/wa"
 )
 
 type Program struct {
@@ -23,6 +24,8 @@ type Program struct {
 	storage Storage
 	man     *pb.ProgramManifest
 	file    *file.File
+	name    string   // Non-empty if stored.
+	parent  *Program // Non-nil if incremental snapshot.
 }
 
 func (prog *Program) PageSize() int     { return executable.PageSize }
@@ -33,6 +36,14 @@ func (prog *Program) Random() bool      { return prog.man.Random }
 // Breakpoints are in ascending order and unique.
 func (prog *Program) Breakpoints() []uint64 { return prog.man.Snapshot.Breakpoints }
 
+func (prog *Program) GlobalTypes() []wa.GlobalType {
+	types := make([]wa.GlobalType, len(prog.man.GlobalTypes))
+	for i, b := range prog.man.GlobalTypes {
+		types[i] = wa.GlobalType(b)
+	}
+	return types
+}
+
 // ResolveEntryFunc index or the implicit _start function index.  The started
 // argument is disregarded if the program is a snapshot.
 func (prog *Program) ResolveEntryFunc(exportName string, started bool) (int, error) {
@@ -107,17 +118,27 @@ func (prog *Program) LoadBuffers() (*snapshot.Buffers, error) {
 
 // Store the program.  The name must not contain path separators.
 func (prog *Program) Store(name string) error {
-	return prog.storage.storeProgram(prog, name)
+	if err := prog.storage.storeProgram(prog, name); err != nil {
+		return err
+	}
+	prog.name = name
+	return nil
 }
 
 func (prog *Program) Close() error {
 	err := prog.file.Close()
 	prog.file = nil
+	if prog.parent != nil {
+		prog.parent.Close()
+		prog.parent = nil
+	}
 	return err
 }
 
 type ProgramStorage interface {
 	Programs() (names []string, err error)
+	LoadProgramModule(name string) (r io.ReadCloser, length int64, err error)
+	RemoveProgram(name string) error
 
 	newProgramFile() (*file.File, error)
 	protectProgramFile(*file.File) error
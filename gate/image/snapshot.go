@@ -18,10 +18,7 @@ import (
 	"gate.computer/wag/wa/opcode"
 )
 
-const (
-	wasmModuleHeaderSize = 8
-	snapshotVersion      = 0
-)
+const wasmModuleHeaderSize = 8
 
 // Snapshot creates a new program from an instance.  The instance must not be
 // running.
@@ -40,6 +37,7 @@ type state struct {
 	prog    *Program
 	inst    *Instance
 	buffers *snapshot.Buffers
+	base    *Program // Set for incremental snapshot.
 
 	initStack bool
 	textAddr  uint64
@@ -247,10 +245,16 @@ func mustNewModuleState(s *state) *moduleState {
 }
 
 func mustSerializeState(s *state, m *moduleState) *Program {
+	var delta []*pb.ByteRange
+	if s.base != nil {
+		delta = mustMemoryDelta(s.base, s.memory)
+	}
+
 	prog := &Program{
 		Map:     s.prog.Map,
 		storage: s.prog.storage,
 		man: &pb.ProgramManifest{
+			Version:                 ManifestVersion,
 			LibraryChecksum:         s.prog.man.LibraryChecksum,
 			TextRevision:            s.prog.man.TextRevision,
 			TextAddr:                s.textAddr,
@@ -275,8 +279,12 @@ func mustSerializeState(s *state, m *moduleState) *Program {
 			FuncAddrsSize:           s.prog.man.FuncAddrsSize,
 			Random:                  s.prog.man.Random,
 			Snapshot:                snapshot.Clone(s.prog.man.Snapshot),
+			MemoryDelta:             delta,
 		},
 	}
+	if s.base != nil {
+		prog.man.Parent = s.base.name
+	}
 
 	f := must(prog.storage.newProgramFile())
 	defer func() {
@@ -285,15 +293,19 @@ func mustSerializeState(s *state, m *moduleState) *Program {
 		}
 	}()
 
-	mustWriteState(f, s)
+	mustWriteState(f, s, delta)
 	mustWriteModuleState(f, s, m)
 
+	if s.base != nil {
+		prog.parent = must(s.base.dup())
+	}
+
 	prog.file = f
 	f = nil
 	return prog
 }
 
-func mustWriteState(f *file.File, s *state) {
+func mustWriteState(f *file.File, s *state, delta []*pb.ByteRange) {
 	// Program text
 
 	copySize := alignPageSize32(s.prog.man.TextSize)
@@ -302,9 +314,12 @@ func mustWriteState(f *file.File, s *state) {
 
 	z.Check(copyFileRange(s.prog.file, &copyFrom, f, &copyDest, copySize))
 
-	// Instance stack, globals and memory
+	// Instance stack, globals and memory (unless incremental)
 
-	copySize = alignPageSize32(s.inst.man.GlobalsSize) + alignPageSize32(s.inst.man.MemorySize)
+	copySize = alignPageSize32(s.inst.man.GlobalsSize)
+	if s.base == nil {
+		copySize += alignPageSize32(s.inst.man.MemorySize)
+	}
 	copyDest = progGlobalsOffset
 	copyFrom = s.inst.globalsPageOffset()
 
@@ -316,6 +331,15 @@ func mustWriteState(f *file.File, s *state) {
 	}
 
 	z.Check(copyFileRange(s.inst.file, &copyFrom, f, &copyDest, copySize))
+
+	// Changed memory pages
+
+	for _, r := range delta {
+		copyDest = progGlobalsOffset + alignPageOffset32(s.inst.man.GlobalsSize) + r.Start
+		copyFrom = s.inst.memoryOffset() + r.Start
+
+		z.Check(copyFileRange(s.inst.file, &copyFrom, f, &copyDest, int(r.Size)))
+	}
 }
 
 func mustWriteModuleState(f *file.File, s *state, m *moduleState) {
@@ -483,7 +507,7 @@ func makeSnapshotSection(snap *snapshot.Snapshot) []byte {
 	b[i] = byte(len(wasm.SectionSnapshot))
 	i++
 	i += copy(b[i:], wasm.SectionSnapshot)
-	b[i] = snapshotVersion
+	b[i] = wasm.SnapshotVersion
 	i++
 	if snap.GetFinal() {
 		b[i] = 1
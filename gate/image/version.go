@@ -0,0 +1,62 @@
+// Copyright (c) 2026 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package image
+
+import (
+	"errors"
+	"fmt"
+
+	"gate.computer/gate/runtime/abi"
+	pb "gate.computer/internal/pb/image"
+)
+
+// ManifestVersion is the format version of program and instance manifests
+// written by this implementation.  Manifests without version predate
+// versioning; they are compatible with version 1.
+const ManifestVersion = 1
+
+// ErrIncompatible is returned when a stored program or instance cannot be
+// used with this implementation.
+var ErrIncompatible = errors.New("image: incompatible with runtime")
+
+// ErrRecompile is returned by LoadProgram when a stored program was compiled
+// for a different runtime library or machine code revision.  The program can
+// be upgraded by building it again from its module (see LoadProgramModule).
+// It wraps ErrIncompatible.
+var ErrRecompile = fmt.Errorf("%w: program must be recompiled", ErrIncompatible)
+
+func checkManifestVersion(kind, name string, version uint32) error {
+	if version > ManifestVersion {
+		return fmt.Errorf("%w: %s %s has manifest version %d (supported: %d)", ErrIncompatible, kind, name, version, ManifestVersion)
+	}
+	return nil
+}
+
+func checkProgramText(name string, man *pb.ProgramManifest) error {
+	if man.LibraryChecksum != abi.LibraryChecksum() {
+		return fmt.Errorf("%w: program %s was built with runtime library %016x (current: %016x)", ErrRecompile, name, man.LibraryChecksum, abi.LibraryChecksum())
+	}
+	if man.TextRevision != TextRevision {
+		return fmt.Errorf("%w: program %s has text revision %d (current: %d)", ErrRecompile, name, man.TextRevision, TextRevision)
+	}
+	return nil
+}
+
+// CheckCompatibility returns an error wrapping ErrIncompatible if the
+// instance has been suspended with a call stack which refers to machine code
+// of a different runtime library build or text revision.  Such an instance
+// cannot be resumed.
+func (inst *Instance) CheckCompatibility() error {
+	if inst.man.StackUsage == 0 || inst.man.Version == 0 {
+		return nil // No stack, or unknown origin.
+	}
+	if inst.man.LibraryChecksum != abi.LibraryChecksum() {
+		return fmt.Errorf("%w: instance %s was suspended with runtime library %016x (current: %016x)", ErrIncompatible, inst.name, inst.man.LibraryChecksum, abi.LibraryChecksum())
+	}
+	if inst.man.TextRevision != TextRevision {
+		return fmt.Errorf("%w: instance %s was suspended with text revision %d (current: %d)", ErrIncompatible, inst.name, inst.man.TextRevision, TextRevision)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2026 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package image
+
+import (
+	"errors"
+	"testing"
+
+	"gate.computer/gate/runtime/abi"
+	pb "gate.computer/internal/pb/image"
+)
+
+func TestManifestVersion(t *testing.T) {
+	for _, v := range []uint32{0, ManifestVersion} {
+		if err := checkManifestVersion("program", "test", v); err != nil {
+			t.Errorf("version %d: %v", v, err)
+		}
+	}
+
+	if err := checkManifestVersion("program", "test", ManifestVersion+1); !errors.Is(err, ErrIncompatible) {
+		t.Error(err)
+	}
+}
+
+func TestProgramText(t *testing.T) {
+	man := &pb.ProgramManifest{
+		LibraryChecksum: abi.LibraryChecksum(),
+		TextRevision:    TextRevision,
+	}
+	if err := checkProgramText("test", man); err != nil {
+		t.Error(err)
+	}
+
+	man.LibraryChecksum++
+	if err := checkProgramText("test", man); !errors.Is(err, ErrRecompile) || !errors.Is(err, ErrIncompatible) {
+		t.Error(err)
+	}
+}
+
+func TestInstanceCompatibility(t *testing.T) {
+	inst := &Instance{
+		man: &pb.InstanceManifest{
+			Version:         ManifestVersion,
+			LibraryChecksum: abi.LibraryChecksum() + 1,
+		},
+	}
+	if err := inst.CheckCompatibility(); err != nil {
+		t.Error("instance without stack:", err)
+	}
+
+	inst.man.StackUsage = 16
+	if err := inst.CheckCompatibility(); !errors.Is(err, ErrIncompatible) {
+		t.Error(err)
+	}
+
+	inst.man.LibraryChecksum = abi.LibraryChecksum()
+	if err := inst.CheckCompatibility(); err != nil {
+		t.Error(err)
+	}
+
+	inst.man.TextRevision = TextRevision + 1
+	if err := inst.CheckCompatibility(); !errors.Is(err, ErrIncompatible) {
+		t.Error(err)
+	}
+}
@@ -2,6 +2,7 @@ package gate_test
 
 import (
 	"fmt"
+	"maps"
 	"sync"
 	"time"
 
@@ -12,23 +13,38 @@ import (
 	. "import.name/type/context"
 )
 
+type testInventoryKey struct {
+	pri principal.ID
+	key string
+}
+
 type testInventory struct {
 	mu        sync.Mutex
-	modules   map[string][]byte
-	instances map[string][]byte
+	modules   map[testInventoryKey][]byte
+	instances map[testInventoryKey][]byte
 }
 
 func newTestInventory() *testInventory {
 	return &testInventory{
-		modules:   make(map[string][]byte),
-		instances: make(map[string][]byte),
+		modules:   make(map[testInventoryKey][]byte),
+		instances: make(map[testInventoryKey][]byte),
+	}
+}
+
+// clone the records, e.g. to simulate a crash.
+func (db *testInventory) clone() *testInventory {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return &testInventory{
+		modules:   maps.Clone(db.modules),
+		instances: maps.Clone(db.instances),
 	}
 }
 
 func (db *testInventory) GetModule(ctx Context, pri principal.ID, key string, msg proto.Message) (found bool, err error) {
 	db.mu.Lock()
 	defer db.mu.Unlock()
-	if buf, found := db.modules[fmt.Sprint(pri, key)]; found {
+	if buf, found := db.modules[testInventoryKey{pri, key}]; found {
 		return true, proto.Unmarshal(buf, msg)
 	}
 	return false, nil
@@ -41,7 +57,7 @@ func (db *testInventory) PutModule(ctx Context, pri principal.ID, key string, ms
 	if err != nil {
 		return err
 	}
-	db.modules[fmt.Sprint(pri, key)] = buf
+	db.modules[testInventoryKey{pri, key}] = buf
 	return nil
 }
 
@@ -52,14 +68,14 @@ func (db *testInventory) UpdateModule(ctx Context, pri principal.ID, key string,
 func (db *testInventory) RemoveModule(ctx Context, pri principal.ID, key string) error {
 	db.mu.Lock()
 	defer db.mu.Unlock()
-	delete(db.modules, fmt.Sprint(pri, key))
+	delete(db.modules, testInventoryKey{pri, key})
 	return nil
 }
 
 func (db *testInventory) GetInstance(ctx Context, pri principal.ID, key string, msg proto.Message) (found bool, err error) {
 	db.mu.Lock()
 	defer db.mu.Unlock()
-	if buf, found := db.instances[fmt.Sprint(pri, key)]; found {
+	if buf, found := db.instances[testInventoryKey{pri, key}]; found {
 		return true, proto.Unmarshal(buf, msg)
 	}
 	return false, nil
@@ -72,23 +88,42 @@ func (db *testInventory) PutInstance(ctx Context, pri principal.ID, key string,
 	if err != nil {
 		return err
 	}
-	db.instances[fmt.Sprint(pri, key)] = buf
+	db.instances[testInventoryKey{pri, key}] = buf
 	return nil
 }
 
 func (db *testInventory) UpdateInstance(ctx Context, pri principal.ID, key string, msg proto.Message) error {
-	db.mu.Lock()
-	defer db.mu.Unlock()
 	return db.PutInstance(ctx, pri, key, msg)
 }
 
 func (db *testInventory) RemoveInstance(ctx Context, pri principal.ID, key string) error {
 	db.mu.Lock()
 	defer db.mu.Unlock()
-	delete(db.instances, fmt.Sprint(pri, key))
+	delete(db.instances, testInventoryKey{pri, key})
 	return nil
 }
 
+func (db *testInventory) ListModules(ctx Context) ([]model.InventoryKey, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return listTestInventory(db.modules), nil
+}
+
+func (db *testInventory) ListInstances(ctx Context) ([]model.InventoryKey, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return listTestInventory(db.instances), nil
+}
+
+func listTestInventory(m map[testInventoryKey][]byte) []model.InventoryKey {
+	var keys []model.InventoryKey
+	for k := range m {
+		pri := k.pri
+		keys = append(keys, model.InventoryKey{Principal: &pri, Key: k.key})
+	}
+	return keys
+}
+
 type testSourceCache struct {
 	mu      sync.Mutex
 	sources map[string]string
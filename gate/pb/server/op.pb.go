@@ -6,7 +6,7 @@
 // versions:
 // 	protoc-gen-go v1.36.4
 // 	protoc        (unknown)
-// source: gate/pb/server/op.proto
+// source: op.proto
 
 package server
 
@@ -54,6 +54,8 @@ const (
 	Op_INSTANCE_UPDATE   Op = 23
 	Op_INSTANCE_DEBUG    Op = 24
 	Op_LAUNCH_HOST       Op = 25
+	Op_INSTANCE_WATCH    Op = 26
+	Op_INSTANCE_CLONE    Op = 27
 )
 
 // Enum value maps for Op.
@@ -85,6 +87,8 @@ var (
 		23: "INSTANCE_UPDATE",
 		24: "INSTANCE_DEBUG",
 		25: "LAUNCH_HOST",
+		26: "INSTANCE_WATCH",
+		27: "INSTANCE_CLONE",
 	}
 	Op_value = map[string]int32{
 		"UNSPECIFIED":       0,
@@ -113,6 +117,8 @@ var (
 		"INSTANCE_UPDATE":   23,
 		"INSTANCE_DEBUG":    24,
 		"LAUNCH_HOST":       25,
+		"INSTANCE_WATCH":    26,
+		"INSTANCE_CLONE":    27,
 	}
 )
 
@@ -127,11 +133,11 @@ func (x Op) String() string {
 }
 
 func (Op) Descriptor() protoreflect.EnumDescriptor {
-	return file_gate_pb_server_op_proto_enumTypes[0].Descriptor()
+	return file_op_proto_enumTypes[0].Descriptor()
 }
 
 func (Op) Type() protoreflect.EnumType {
-	return &file_gate_pb_server_op_proto_enumTypes[0]
+	return &file_op_proto_enumTypes[0]
 }
 
 func (x Op) Number() protoreflect.EnumNumber {
@@ -140,68 +146,70 @@ func (x Op) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use Op.Descriptor instead.
 func (Op) EnumDescriptor() ([]byte, []int) {
-	return file_gate_pb_server_op_proto_rawDescGZIP(), []int{0}
+	return file_op_proto_rawDescGZIP(), []int{0}
 }
 
-var File_gate_pb_server_op_proto protoreflect.FileDescriptor
-
-var file_gate_pb_server_op_proto_rawDesc = string([]byte{
-	0x0a, 0x17, 0x67, 0x61, 0x74, 0x65, 0x2f, 0x70, 0x62, 0x2f, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72,
-	0x2f, 0x6f, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x10, 0x67, 0x61, 0x74, 0x65, 0x2e,
-	0x67, 0x61, 0x74, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2a, 0xf3, 0x03, 0x0a, 0x02,
-	0x4f, 0x70, 0x12, 0x0f, 0x0a, 0x0b, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45,
-	0x44, 0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x4d, 0x4f, 0x44, 0x55, 0x4c, 0x45, 0x5f, 0x4c, 0x49,
-	0x53, 0x54, 0x10, 0x01, 0x12, 0x0f, 0x0a, 0x0b, 0x4d, 0x4f, 0x44, 0x55, 0x4c, 0x45, 0x5f, 0x49,
-	0x4e, 0x46, 0x4f, 0x10, 0x02, 0x12, 0x13, 0x0a, 0x0f, 0x4d, 0x4f, 0x44, 0x55, 0x4c, 0x45, 0x5f,
-	0x44, 0x4f, 0x57, 0x4e, 0x4c, 0x4f, 0x41, 0x44, 0x10, 0x03, 0x12, 0x11, 0x0a, 0x0d, 0x4d, 0x4f,
-	0x44, 0x55, 0x4c, 0x45, 0x5f, 0x55, 0x50, 0x4c, 0x4f, 0x41, 0x44, 0x10, 0x04, 0x12, 0x11, 0x0a,
-	0x0d, 0x4d, 0x4f, 0x44, 0x55, 0x4c, 0x45, 0x5f, 0x53, 0x4f, 0x55, 0x52, 0x43, 0x45, 0x10, 0x05,
-	0x12, 0x0e, 0x0a, 0x0a, 0x4d, 0x4f, 0x44, 0x55, 0x4c, 0x45, 0x5f, 0x50, 0x49, 0x4e, 0x10, 0x06,
-	0x12, 0x10, 0x0a, 0x0c, 0x4d, 0x4f, 0x44, 0x55, 0x4c, 0x45, 0x5f, 0x55, 0x4e, 0x50, 0x49, 0x4e,
-	0x10, 0x07, 0x12, 0x0f, 0x0a, 0x0b, 0x43, 0x41, 0x4c, 0x4c, 0x5f, 0x45, 0x58, 0x54, 0x41, 0x4e,
-	0x54, 0x10, 0x08, 0x12, 0x0f, 0x0a, 0x0b, 0x43, 0x41, 0x4c, 0x4c, 0x5f, 0x55, 0x50, 0x4c, 0x4f,
-	0x41, 0x44, 0x10, 0x09, 0x12, 0x0f, 0x0a, 0x0b, 0x43, 0x41, 0x4c, 0x4c, 0x5f, 0x53, 0x4f, 0x55,
-	0x52, 0x43, 0x45, 0x10, 0x0a, 0x12, 0x11, 0x0a, 0x0d, 0x4c, 0x41, 0x55, 0x4e, 0x43, 0x48, 0x5f,
-	0x45, 0x58, 0x54, 0x41, 0x4e, 0x54, 0x10, 0x0b, 0x12, 0x11, 0x0a, 0x0d, 0x4c, 0x41, 0x55, 0x4e,
-	0x43, 0x48, 0x5f, 0x55, 0x50, 0x4c, 0x4f, 0x41, 0x44, 0x10, 0x0c, 0x12, 0x11, 0x0a, 0x0d, 0x4c,
-	0x41, 0x55, 0x4e, 0x43, 0x48, 0x5f, 0x53, 0x4f, 0x55, 0x52, 0x43, 0x45, 0x10, 0x0d, 0x12, 0x11,
-	0x0a, 0x0d, 0x49, 0x4e, 0x53, 0x54, 0x41, 0x4e, 0x43, 0x45, 0x5f, 0x4c, 0x49, 0x53, 0x54, 0x10,
-	0x0e, 0x12, 0x11, 0x0a, 0x0d, 0x49, 0x4e, 0x53, 0x54, 0x41, 0x4e, 0x43, 0x45, 0x5f, 0x49, 0x4e,
-	0x46, 0x4f, 0x10, 0x0f, 0x12, 0x14, 0x0a, 0x10, 0x49, 0x4e, 0x53, 0x54, 0x41, 0x4e, 0x43, 0x45,
-	0x5f, 0x43, 0x4f, 0x4e, 0x4e, 0x45, 0x43, 0x54, 0x10, 0x10, 0x12, 0x11, 0x0a, 0x0d, 0x49, 0x4e,
-	0x53, 0x54, 0x41, 0x4e, 0x43, 0x45, 0x5f, 0x57, 0x41, 0x49, 0x54, 0x10, 0x11, 0x12, 0x11, 0x0a,
-	0x0d, 0x49, 0x4e, 0x53, 0x54, 0x41, 0x4e, 0x43, 0x45, 0x5f, 0x4b, 0x49, 0x4c, 0x4c, 0x10, 0x12,
-	0x12, 0x14, 0x0a, 0x10, 0x49, 0x4e, 0x53, 0x54, 0x41, 0x4e, 0x43, 0x45, 0x5f, 0x53, 0x55, 0x53,
-	0x50, 0x45, 0x4e, 0x44, 0x10, 0x13, 0x12, 0x13, 0x0a, 0x0f, 0x49, 0x4e, 0x53, 0x54, 0x41, 0x4e,
-	0x43, 0x45, 0x5f, 0x52, 0x45, 0x53, 0x55, 0x4d, 0x45, 0x10, 0x14, 0x12, 0x15, 0x0a, 0x11, 0x49,
-	0x4e, 0x53, 0x54, 0x41, 0x4e, 0x43, 0x45, 0x5f, 0x53, 0x4e, 0x41, 0x50, 0x53, 0x48, 0x4f, 0x54,
-	0x10, 0x15, 0x12, 0x13, 0x0a, 0x0f, 0x49, 0x4e, 0x53, 0x54, 0x41, 0x4e, 0x43, 0x45, 0x5f, 0x44,
-	0x45, 0x4c, 0x45, 0x54, 0x45, 0x10, 0x16, 0x12, 0x13, 0x0a, 0x0f, 0x49, 0x4e, 0x53, 0x54, 0x41,
-	0x4e, 0x43, 0x45, 0x5f, 0x55, 0x50, 0x44, 0x41, 0x54, 0x45, 0x10, 0x17, 0x12, 0x12, 0x0a, 0x0e,
-	0x49, 0x4e, 0x53, 0x54, 0x41, 0x4e, 0x43, 0x45, 0x5f, 0x44, 0x45, 0x42, 0x55, 0x47, 0x10, 0x18,
-	0x12, 0x0f, 0x0a, 0x0b, 0x4c, 0x41, 0x55, 0x4e, 0x43, 0x48, 0x5f, 0x48, 0x4f, 0x53, 0x54, 0x10,
-	0x19, 0x42, 0x1e, 0x5a, 0x1c, 0x67, 0x61, 0x74, 0x65, 0x2e, 0x63, 0x6f, 0x6d, 0x70, 0x75, 0x74,
-	0x65, 0x72, 0x2f, 0x67, 0x61, 0x74, 0x65, 0x2f, 0x70, 0x62, 0x2f, 0x73, 0x65, 0x72, 0x76, 0x65,
-	0x72, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+var File_op_proto protoreflect.FileDescriptor
+
+var file_op_proto_rawDesc = string([]byte{
+	0x0a, 0x08, 0x6f, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x10, 0x67, 0x61, 0x74, 0x65,
+	0x2e, 0x67, 0x61, 0x74, 0x65, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2a, 0x9b, 0x04, 0x0a,
+	0x02, 0x4f, 0x70, 0x12, 0x0f, 0x0a, 0x0b, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49,
+	0x45, 0x44, 0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x4d, 0x4f, 0x44, 0x55, 0x4c, 0x45, 0x5f, 0x4c,
+	0x49, 0x53, 0x54, 0x10, 0x01, 0x12, 0x0f, 0x0a, 0x0b, 0x4d, 0x4f, 0x44, 0x55, 0x4c, 0x45, 0x5f,
+	0x49, 0x4e, 0x46, 0x4f, 0x10, 0x02, 0x12, 0x13, 0x0a, 0x0f, 0x4d, 0x4f, 0x44, 0x55, 0x4c, 0x45,
+	0x5f, 0x44, 0x4f, 0x57, 0x4e, 0x4c, 0x4f, 0x41, 0x44, 0x10, 0x03, 0x12, 0x11, 0x0a, 0x0d, 0x4d,
+	0x4f, 0x44, 0x55, 0x4c, 0x45, 0x5f, 0x55, 0x50, 0x4c, 0x4f, 0x41, 0x44, 0x10, 0x04, 0x12, 0x11,
+	0x0a, 0x0d, 0x4d, 0x4f, 0x44, 0x55, 0x4c, 0x45, 0x5f, 0x53, 0x4f, 0x55, 0x52, 0x43, 0x45, 0x10,
+	0x05, 0x12, 0x0e, 0x0a, 0x0a, 0x4d, 0x4f, 0x44, 0x55, 0x4c, 0x45, 0x5f, 0x50, 0x49, 0x4e, 0x10,
+	0x06, 0x12, 0x10, 0x0a, 0x0c, 0x4d, 0x4f, 0x44, 0x55, 0x4c, 0x45, 0x5f, 0x55, 0x4e, 0x50, 0x49,
+	0x4e, 0x10, 0x07, 0x12, 0x0f, 0x0a, 0x0b, 0x43, 0x41, 0x4c, 0x4c, 0x5f, 0x45, 0x58, 0x54, 0x41,
+	0x4e, 0x54, 0x10, 0x08, 0x12, 0x0f, 0x0a, 0x0b, 0x43, 0x41, 0x4c, 0x4c, 0x5f, 0x55, 0x50, 0x4c,
+	0x4f, 0x41, 0x44, 0x10, 0x09, 0x12, 0x0f, 0x0a, 0x0b, 0x43, 0x41, 0x4c, 0x4c, 0x5f, 0x53, 0x4f,
+	0x55, 0x52, 0x43, 0x45, 0x10, 0x0a, 0x12, 0x11, 0x0a, 0x0d, 0x4c, 0x41, 0x55, 0x4e, 0x43, 0x48,
+	0x5f, 0x45, 0x58, 0x54, 0x41, 0x4e, 0x54, 0x10, 0x0b, 0x12, 0x11, 0x0a, 0x0d, 0x4c, 0x41, 0x55,
+	0x4e, 0x43, 0x48, 0x5f, 0x55, 0x50, 0x4c, 0x4f, 0x41, 0x44, 0x10, 0x0c, 0x12, 0x11, 0x0a, 0x0d,
+	0x4c, 0x41, 0x55, 0x4e, 0x43, 0x48, 0x5f, 0x53, 0x4f, 0x55, 0x52, 0x43, 0x45, 0x10, 0x0d, 0x12,
+	0x11, 0x0a, 0x0d, 0x49, 0x4e, 0x53, 0x54, 0x41, 0x4e, 0x43, 0x45, 0x5f, 0x4c, 0x49, 0x53, 0x54,
+	0x10, 0x0e, 0x12, 0x11, 0x0a, 0x0d, 0x49, 0x4e, 0x53, 0x54, 0x41, 0x4e, 0x43, 0x45, 0x5f, 0x49,
+	0x4e, 0x46, 0x4f, 0x10, 0x0f, 0x12, 0x14, 0x0a, 0x10, 0x49, 0x4e, 0x53, 0x54, 0x41, 0x4e, 0x43,
+	0x45, 0x5f, 0x43, 0x4f, 0x4e, 0x4e, 0x45, 0x43, 0x54, 0x10, 0x10, 0x12, 0x11, 0x0a, 0x0d, 0x49,
+	0x4e, 0x53, 0x54, 0x41, 0x4e, 0x43, 0x45, 0x5f, 0x57, 0x41, 0x49, 0x54, 0x10, 0x11, 0x12, 0x11,
+	0x0a, 0x0d, 0x49, 0x4e, 0x53, 0x54, 0x41, 0x4e, 0x43, 0x45, 0x5f, 0x4b, 0x49, 0x4c, 0x4c, 0x10,
+	0x12, 0x12, 0x14, 0x0a, 0x10, 0x49, 0x4e, 0x53, 0x54, 0x41, 0x4e, 0x43, 0x45, 0x5f, 0x53, 0x55,
+	0x53, 0x50, 0x45, 0x4e, 0x44, 0x10, 0x13, 0x12, 0x13, 0x0a, 0x0f, 0x49, 0x4e, 0x53, 0x54, 0x41,
+	0x4e, 0x43, 0x45, 0x5f, 0x52, 0x45, 0x53, 0x55, 0x4d, 0x45, 0x10, 0x14, 0x12, 0x15, 0x0a, 0x11,
+	0x49, 0x4e, 0x53, 0x54, 0x41, 0x4e, 0x43, 0x45, 0x5f, 0x53, 0x4e, 0x41, 0x50, 0x53, 0x48, 0x4f,
+	0x54, 0x10, 0x15, 0x12, 0x13, 0x0a, 0x0f, 0x49, 0x4e, 0x53, 0x54, 0x41, 0x4e, 0x43, 0x45, 0x5f,
+	0x44, 0x45, 0x4c, 0x45, 0x54, 0x45, 0x10, 0x16, 0x12, 0x13, 0x0a, 0x0f, 0x49, 0x4e, 0x53, 0x54,
+	0x41, 0x4e, 0x43, 0x45, 0x5f, 0x55, 0x50, 0x44, 0x41, 0x54, 0x45, 0x10, 0x17, 0x12, 0x12, 0x0a,
+	0x0e, 0x49, 0x4e, 0x53, 0x54, 0x41, 0x4e, 0x43, 0x45, 0x5f, 0x44, 0x45, 0x42, 0x55, 0x47, 0x10,
+	0x18, 0x12, 0x0f, 0x0a, 0x0b, 0x4c, 0x41, 0x55, 0x4e, 0x43, 0x48, 0x5f, 0x48, 0x4f, 0x53, 0x54,
+	0x10, 0x19, 0x12, 0x12, 0x0a, 0x0e, 0x49, 0x4e, 0x53, 0x54, 0x41, 0x4e, 0x43, 0x45, 0x5f, 0x57,
+	0x41, 0x54, 0x43, 0x48, 0x10, 0x1a, 0x12, 0x12, 0x0a, 0x0e, 0x49, 0x4e, 0x53, 0x54, 0x41, 0x4e,
+	0x43, 0x45, 0x5f, 0x43, 0x4c, 0x4f, 0x4e, 0x45, 0x10, 0x1b, 0x42, 0x1e, 0x5a, 0x1c, 0x67, 0x61,
+	0x74, 0x65, 0x2e, 0x63, 0x6f, 0x6d, 0x70, 0x75, 0x74, 0x65, 0x72, 0x2f, 0x67, 0x61, 0x74, 0x65,
+	0x2f, 0x70, 0x62, 0x2f, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
 })
 
 var (
-	file_gate_pb_server_op_proto_rawDescOnce sync.Once
-	file_gate_pb_server_op_proto_rawDescData []byte
+	file_op_proto_rawDescOnce sync.Once
+	file_op_proto_rawDescData []byte
 )
 
-func file_gate_pb_server_op_proto_rawDescGZIP() []byte {
-	file_gate_pb_server_op_proto_rawDescOnce.Do(func() {
-		file_gate_pb_server_op_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_gate_pb_server_op_proto_rawDesc), len(file_gate_pb_server_op_proto_rawDesc)))
+func file_op_proto_rawDescGZIP() []byte {
+	file_op_proto_rawDescOnce.Do(func() {
+		file_op_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_op_proto_rawDesc), len(file_op_proto_rawDesc)))
 	})
-	return file_gate_pb_server_op_proto_rawDescData
+	return file_op_proto_rawDescData
 }
 
-var file_gate_pb_server_op_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_gate_pb_server_op_proto_goTypes = []any{
+var file_op_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_op_proto_goTypes = []any{
 	(Op)(0), // 0: gate.gate.server.Op
 }
-var file_gate_pb_server_op_proto_depIdxs = []int32{
+var file_op_proto_depIdxs = []int32{
 	0, // [0:0] is the sub-list for method output_type
 	0, // [0:0] is the sub-list for method input_type
 	0, // [0:0] is the sub-list for extension type_name
@@ -209,26 +217,26 @@ var file_gate_pb_server_op_proto_depIdxs = []int32{
 	0, // [0:0] is the sub-list for field type_name
 }
 
-func init() { file_gate_pb_server_op_proto_init() }
-func file_gate_pb_server_op_proto_init() {
-	if File_gate_pb_server_op_proto != nil {
+func init() { file_op_proto_init() }
+func file_op_proto_init() {
+	if File_op_proto != nil {
 		return
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
-			RawDescriptor: unsafe.Slice(unsafe.StringData(file_gate_pb_server_op_proto_rawDesc), len(file_gate_pb_server_op_proto_rawDesc)),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_op_proto_rawDesc), len(file_op_proto_rawDesc)),
 			NumEnums:      1,
 			NumMessages:   0,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
-		GoTypes:           file_gate_pb_server_op_proto_goTypes,
-		DependencyIndexes: file_gate_pb_server_op_proto_depIdxs,
-		EnumInfos:         file_gate_pb_server_op_proto_enumTypes,
+		GoTypes:           file_op_proto_goTypes,
+		DependencyIndexes: file_op_proto_depIdxs,
+		EnumInfos:         file_op_proto_enumTypes,
 	}.Build()
-	File_gate_pb_server_op_proto = out.File
-	file_gate_pb_server_op_proto_goTypes = nil
-	file_gate_pb_server_op_proto_depIdxs = nil
+	File_op_proto = out.File
+	file_op_proto_goTypes = nil
+	file_op_proto_depIdxs = nil
 }
@@ -45,6 +45,7 @@ const (
 	ID_ABI_VIOLATION  ID = 28
 	ID_INTERNAL_ERROR ID = 29
 	ID_KILLED         ID = 30
+	ID_TIMEOUT        ID = 31
 )
 
 // Enum value maps for ID.
@@ -65,6 +66,7 @@ var (
 		28: "ABI_VIOLATION",
 		29: "INTERNAL_ERROR",
 		30: "KILLED",
+		31: "TIMEOUT",
 	}
 	ID_value = map[string]int32{
 		"EXIT":                              0,
@@ -82,6 +84,7 @@ var (
 		"ABI_VIOLATION":                     28,
 		"INTERNAL_ERROR":                    29,
 		"KILLED":                            30,
+		"TIMEOUT":                           31,
 	}
 )
 
@@ -117,7 +120,7 @@ var File_gate_pb_trap_trap_proto protoreflect.FileDescriptor
 var file_gate_pb_trap_trap_proto_rawDesc = string([]byte{
 	0x0a, 0x17, 0x67, 0x61, 0x74, 0x65, 0x2f, 0x70, 0x62, 0x2f, 0x74, 0x72, 0x61, 0x70, 0x2f, 0x74,
 	0x72, 0x61, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0e, 0x67, 0x61, 0x74, 0x65, 0x2e,
-	0x67, 0x61, 0x74, 0x65, 0x2e, 0x74, 0x72, 0x61, 0x70, 0x2a, 0xd0, 0x02, 0x0a, 0x02, 0x49, 0x44,
+	0x67, 0x61, 0x74, 0x65, 0x2e, 0x74, 0x72, 0x61, 0x70, 0x2a, 0xdd, 0x02, 0x0a, 0x02, 0x49, 0x44,
 	0x12, 0x08, 0x0a, 0x04, 0x45, 0x58, 0x49, 0x54, 0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x4e, 0x4f,
 	0x5f, 0x46, 0x55, 0x4e, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x10, 0x01, 0x12, 0x0d, 0x0a, 0x09, 0x53,
 	0x55, 0x53, 0x50, 0x45, 0x4e, 0x44, 0x45, 0x44, 0x10, 0x02, 0x12, 0x0f, 0x0a, 0x0b, 0x55, 0x4e,
@@ -138,10 +141,10 @@ var file_gate_pb_trap_trap_proto_rawDesc = string([]byte{
 	0x49, 0x43, 0x49, 0x45, 0x4e, 0x43, 0x59, 0x10, 0x1b, 0x12, 0x11, 0x0a, 0x0d, 0x41, 0x42, 0x49,
 	0x5f, 0x56, 0x49, 0x4f, 0x4c, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x10, 0x1c, 0x12, 0x12, 0x0a, 0x0e,
 	0x49, 0x4e, 0x54, 0x45, 0x52, 0x4e, 0x41, 0x4c, 0x5f, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x10, 0x1d,
-	0x12, 0x0a, 0x0a, 0x06, 0x4b, 0x49, 0x4c, 0x4c, 0x45, 0x44, 0x10, 0x1e, 0x42, 0x1c, 0x5a, 0x1a,
-	0x67, 0x61, 0x74, 0x65, 0x2e, 0x63, 0x6f, 0x6d, 0x70, 0x75, 0x74, 0x65, 0x72, 0x2f, 0x67, 0x61,
-	0x74, 0x65, 0x2f, 0x70, 0x62, 0x2f, 0x74, 0x72, 0x61, 0x70, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x33,
+	0x12, 0x0a, 0x0a, 0x06, 0x4b, 0x49, 0x4c, 0x4c, 0x45, 0x44, 0x10, 0x1e, 0x12, 0x0b, 0x0a, 0x07,
+	0x54, 0x49, 0x4d, 0x45, 0x4f, 0x55, 0x54, 0x10, 0x1f, 0x42, 0x1c, 0x5a, 0x1a, 0x67, 0x61, 0x74,
+	0x65, 0x2e, 0x63, 0x6f, 0x6d, 0x70, 0x75, 0x74, 0x65, 0x72, 0x2f, 0x67, 0x61, 0x74, 0x65, 0x2f,
+	0x70, 0x62, 0x2f, 0x74, 0x72, 0x61, 0x70, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 })
 
 var (
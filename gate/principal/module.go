@@ -0,0 +1,26 @@
+// Copyright (c) 2026 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package principal
+
+import (
+	"context"
+
+	. "import.name/type/context"
+)
+
+type contextModuleIDValueKey struct{}
+
+// ContextWithModuleID returns a context for an instance of a module.
+func ContextWithModuleID(ctx Context, id string) Context {
+	return context.WithValue(ctx, contextModuleIDValueKey{}, id)
+}
+
+// ContextModuleID returns the id of the module which the instance was
+// launched or resumed with, if any.  It changes when the instance is resumed
+// from a snapshot.
+func ContextModuleID(ctx Context) (id string, ok bool) {
+	id, ok = ctx.Value(contextModuleIDValueKey{}).(string)
+	return
+}
@@ -16,6 +16,7 @@ import (
 	"sync"
 	"sync/atomic"
 	"syscall"
+	"time"
 
 	"gate.computer/internal/container"
 	"gate.computer/internal/file"
@@ -30,6 +31,7 @@ const (
 	execOpCreate uint8 = iota
 	execOpKill
 	execOpSuspend
+	execOpLimitCPU
 )
 
 // Executor manages Process resources in an isolated environment.
@@ -38,6 +40,7 @@ type Executor struct {
 	ids           chan int16
 	execRequests  chan execRequest
 	killRequests  chan int16
+	limitRequests chan execLimit
 	doneSending   chan struct{}
 	doneReceiving chan struct{}
 
@@ -91,6 +94,7 @@ func NewExecutor(config *Config) (*Executor, error) {
 		ids:           make(chan int16, maxProcs),
 		execRequests:  make(chan execRequest), // No buffering.  Request must be released.
 		killRequests:  make(chan int16, 16),   // TODO: how much buffering?
+		limitRequests: make(chan execLimit),
 		doneSending:   make(chan struct{}),
 		doneReceiving: make(chan struct{}),
 		procs:         make(map[int16]*execProcess),
@@ -197,7 +201,7 @@ func (e *Executor) sender(log *slog.Logger) {
 		}
 	}()
 
-	buf := make([]byte, 4) // sizeof(ExecRequest)
+	buf := make([]byte, 8) // sizeof(ExecRequest)
 
 	// TODO: send multiple entries at once
 	for {
@@ -215,6 +219,7 @@ func (e *Executor) sender(log *slog.Logger) {
 			// This is like ExecRequest in runtime/executor/executor.cpp
 			binary.LittleEndian.PutUint16(buf[0:], uint16(req.pid))
 			buf[2] = execOpCreate
+			binary.LittleEndian.PutUint32(buf[4:], 0)
 
 			cmsg = syscall.UnixRights(req.fds()...)
 
@@ -238,6 +243,13 @@ func (e *Executor) sender(log *slog.Logger) {
 			// This is like ExecRequest in runtime/executor/executor.cpp
 			binary.LittleEndian.PutUint16(buf[0:], uint16(id))
 			buf[2] = op
+			binary.LittleEndian.PutUint32(buf[4:], 0)
+
+		case limit := <-e.limitRequests:
+			// This is like ExecRequest in runtime/executor/executor.cpp
+			binary.LittleEndian.PutUint16(buf[0:], uint16(limit.id))
+			buf[2] = execOpLimitCPU
+			binary.LittleEndian.PutUint32(buf[4:], limit.cpuSeconds)
 		}
 
 		_, _, err := e.conn.WriteMsgUnix(buf, cmsg, nil)
@@ -252,7 +264,7 @@ func (e *Executor) sender(log *slog.Logger) {
 func (e *Executor) receiver(log *slog.Logger) {
 	defer close(e.doneReceiving)
 
-	buf := make([]byte, 512*8) // N * sizeof(ExecStatus)
+	buf := make([]byte, 512*16) // N * sizeof(ExecStatus)
 	buffered := 0
 
 	for {
@@ -268,16 +280,18 @@ func (e *Executor) receiver(log *slog.Logger) {
 		b := buf[:buffered]
 
 		lock.Guard(&e.mu, func() {
-			for ; len(b) >= 8; b = b[8:] {
+			for ; len(b) >= 16; b = b[16:] {
 				// This is like ExecStatus in runtime/executor/executor.cpp
 				var (
-					id     = int16(binary.LittleEndian.Uint16(b[0:]))
-					status = int32(binary.LittleEndian.Uint32(b[4:]))
+					id      = int16(binary.LittleEndian.Uint16(b[0:]))
+					status  = int32(binary.LittleEndian.Uint32(b[4:]))
+					cpuTime = binary.LittleEndian.Uint64(b[8:])
 				)
 
 				p := e.procs[id]
 				delete(e.procs, id)
 				p.status = syscall.WaitStatus(status)
+				p.cpuTime = time.Duration(cpuTime) * time.Microsecond
 				close(p.dead)
 			}
 		})
@@ -307,6 +321,7 @@ type execProcess struct {
 	id       int32 // Atomic.
 	dead     chan struct{}
 	status   syscall.WaitStatus // Valid after dead is closed.
+	cpuTime  time.Duration      // Valid after dead is closed.
 }
 
 func (p *execProcess) init(e *Executor, id int16) {
@@ -319,6 +334,28 @@ func (p *execProcess) killRequested() bool {
 	return atomic.LoadInt32(&p.id) == execProcessIDKilled
 }
 
+func (p *execProcess) suspendRequested() bool {
+	return atomic.LoadInt32(&p.id) == execProcessIDSuspended
+}
+
+// limitCPU must not be called concurrently with finalize.
+func (p *execProcess) limitCPU(seconds uint32) {
+	if p.executor == nil {
+		return
+	}
+
+	n := atomic.LoadInt32(&p.id)
+	if n < 0 {
+		return
+	}
+
+	select {
+	case p.executor.limitRequests <- execLimit{int16(n), seconds}:
+	case <-p.executor.doneSending:
+	case <-p.executor.doneReceiving:
+	}
+}
+
 func (p *execProcess) kill()    { p.killSuspend(false, execProcessIDKilled) }
 func (p *execProcess) suspend() { p.killSuspend(true, execProcessIDSuspended) }
 
@@ -389,6 +426,11 @@ func (p *execProcess) finalize() (status syscall.WaitStatus, err error) {
 	}
 }
 
+type execLimit struct {
+	id         int16
+	cpuSeconds uint32
+}
+
 type execRequest struct {
 	pid    int16
 	proc   *execProcess
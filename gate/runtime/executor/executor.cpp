@@ -236,17 +236,28 @@ void suspend_process(pid_t pid, int pidfd, long clock_ticks)
 		die(ERR_EXEC_PRLIMIT_CPU);
 }
 
+void limit_process_cpu(pid_t pid, uint32_t secs)
+{
+	// Soft limit causes suspension, hard limit is a fallback.
+	const rlimit cpu = {secs, secs + 1};
+	if (prlimit(pid, RLIMIT_CPU, &cpu, nullptr) != 0)
+		die(ERR_EXEC_PRLIMIT_CPU);
+}
+
 enum class ExecOp : uint8_t {
 	Create,
 	Kill,
 	Suspend,
+	LimitCPU,
 };
 
-// See runtime/executor.go
+// See runtime/executor.go.  Changes to ExecRequest and ExecStatus layouts
+// require incrementing CompatMajor in internal/container/common.
 struct ExecRequest {
 	int16_t id;
 	ExecOp op;
 	uint8_t reserved[1];
+	uint32_t cpu_secs; // LimitCPU op.
 } PACKED;
 
 // See runtime/executor.go
@@ -254,6 +265,7 @@ struct ExecStatus {
 	int16_t id;
 	uint8_t reserved[2];
 	int32_t status;
+	uint64_t cpu_usecs; // User and system time.
 } PACKED;
 
 union ControlBuffer {
@@ -446,6 +458,19 @@ more:
 			}
 			break;
 
+		case ExecOp::LimitCPU:
+			debugf("executor: limiting [%d] cpu time to %u secs", id, m_reqs[i].cpu_secs);
+
+			if (cmsg)
+				die(ERR_EXEC_CMSG_OP_MISMATCH);
+
+			if (p.exists()) {
+				limit_process_cpu(p.id(), m_reqs[i].cpu_secs);
+			} else {
+				debugf("executor: [%d] does not exist", id);
+			}
+			break;
+
 		default:
 			die(ERR_EXEC_OP);
 		}
@@ -517,7 +542,8 @@ void Executor::wait_process(int16_t id)
 		die(ERR_EXEC_WAIT_PROCESS_BAD_STATE);
 
 	int status;
-	auto ret = waitpid(p.id(), &status, WNOHANG);
+	rusage usage;
+	auto ret = wait4(p.id(), &status, WNOHANG, &usage);
 	if (ret == 0)
 		return;
 	if (ret != p.id())
@@ -534,6 +560,8 @@ void Executor::wait_process(int16_t id)
 	auto& slot = m_send_buf[m_send_end];
 	slot.id = id;
 	slot.status = status;
+	slot.cpu_usecs = uint64_t(usage.ru_utime.tv_sec + usage.ru_stime.tv_sec) * 1000000 +
+			 uint64_t(usage.ru_utime.tv_usec + usage.ru_stime.tv_usec);
 	m_send_end = (m_send_end + 1) & (send_buflen - 1);
 
 	debugf("executor: send queue length %d", send_queue_length());
@@ -55,6 +55,14 @@ func ioLoop(ctx Context, services ServiceRegistry, subject *Process, buffers *sn
 }
 
 func ioLoop2(ctx Context, services ServiceRegistry, subject *Process, frozen *snapshot.Buffers) (retErr error) {
+	if r := subject.recorder; r != nil {
+		defer func() {
+			if err := r.flush(); err != nil && retErr == nil {
+				retErr = err
+			}
+		}()
+	}
+
 	var (
 		dead      = subject.execution.dead
 		suspended = subject.suspended
@@ -203,6 +211,14 @@ func ioLoop2(ctx Context, services ServiceRegistry, subject *Process, frozen *sn
 				return nil
 			}
 
+			subject.countSent(len(read.buf))
+
+			if r := subject.recorder; r != nil {
+				if err := r.record(recordSent, read.buf); err != nil {
+					return err
+				}
+			}
+
 			msg, ev, opErr := handlePacket(ctx, read.buf, discoverer)
 			if opErr != nil {
 				return opErr
@@ -216,6 +232,13 @@ func ioLoop2(ctx Context, services ServiceRegistry, subject *Process, frozen *sn
 
 		case doSubjectOutput <- nextEv:
 			pendingEvs = pendingEvs[1:]
+			subject.countReceived(len(nextEv))
+
+			if r := subject.recorder; r != nil {
+				if err := r.record(recordReceived, nextEv); err != nil {
+					return err
+				}
+			}
 
 		case <-dead:
 			dead = nil
@@ -14,6 +14,7 @@ import (
 	"math/bits"
 	"net"
 	"os"
+	"sync/atomic"
 	"syscall"
 	"time"
 	"unsafe"
@@ -77,6 +78,21 @@ type ProgramState interface {
 type ProcessPolicy struct {
 	TimeResolution time.Duration
 	DebugLog       io.Writer
+
+	// CPUTimeLimit causes the program to be suspended with the Timeout trap
+	// when reached.  It is rounded up to whole seconds.  Zero means
+	// unlimited.
+	CPUTimeLimit time.Duration
+
+	// Record the packets transferred between the program and its services.
+	// The recording can be loaded with ReadRecording.  It is ignored when
+	// replaying.
+	Record io.Writer
+
+	// Replay a recording instead of serving services.  The program must be a
+	// fresh instance of the recorded module.  The initial monotonic time and
+	// time resolution are taken from the recording.
+	Replay *Recording
 }
 
 type ProcessFactory interface {
@@ -127,6 +143,23 @@ type Process struct {
 	suspended chan struct{}
 	debugFile *os.File
 	debugging <-chan struct{}
+	cpuLimit  bool
+	recorder  *recorder
+	replay    *replayer
+	activity  atomic.Int64 // Unix nanoseconds.
+	packetsTx atomic.Uint64
+	bytesTx   atomic.Uint64
+	packetsRx atomic.Uint64
+	bytesRx   atomic.Uint64
+}
+
+// IOCounters of packets transferred between a program and its services.
+// Sent and received are from the program's point of view.
+type IOCounters struct {
+	PacketsSent     uint64
+	BytesSent       uint64
+	PacketsReceived uint64
+	BytesReceived   uint64
 }
 
 func newProcess(ctx Context, e *Executor, group file.Ref) (*Process, error) {
@@ -216,6 +249,12 @@ func (p *Process) Start(code ProgramCode, state ProgramState, policy ProcessPoli
 	}
 	timeMask := ^uint32(1<<uint(bits.Len32(uint32(policy.TimeResolution/time.Nanosecond))) - 1)
 
+	monotonicTime := state.MonotonicTime()
+	if policy.Replay != nil {
+		timeMask = policy.Replay.TimeMask
+		monotonicTime = policy.Replay.MonotonicTime
+	}
+
 	info := imageInfo{
 		MagicNumber1:   magicNumber1,
 		PageSize:       uint32(code.PageSize()),
@@ -232,7 +271,7 @@ func (p *Process) Start(code ProgramCode, state ProgramState, policy ProcessPoli
 		StartAddr:      state.StartAddr(),
 		EntryAddr:      state.EntryAddr(),
 		TimeMask:       timeMask,
-		MonotonicTime:  state.MonotonicTime(),
+		MonotonicTime:  monotonicTime,
 		MagicNumber2:   magicNumber2,
 	}
 	if info.StackUnused == info.StackSize {
@@ -281,6 +320,20 @@ func (p *Process) Start(code ProgramCode, state ProgramState, policy ProcessPoli
 		cmsg = syscall.UnixRights(int(debugWriter.Fd()), int(textFile.Fd()), int(stateFile.Fd()))
 	}
 
+	if policy.Replay != nil {
+		p.replay = newReplayer(policy.Replay)
+	} else if policy.Record != nil {
+		p.recorder, err = newRecorder(policy.Record, timeMask, monotonicTime)
+		if err != nil {
+			return err
+		}
+	}
+
+	if policy.CPUTimeLimit > 0 {
+		p.execution.limitCPU(uint32((policy.CPUTimeLimit + time.Second - 1) / time.Second))
+		p.cpuLimit = p.execution.executor != nil
+	}
+
 	if err := syscall.Sendmsg(p.writer.FD(), buf.Bytes(), cmsg, nil, 0); err != nil {
 		return err
 	}
@@ -291,9 +344,54 @@ func (p *Process) Start(code ProgramCode, state ProgramState, policy ProcessPoli
 		p.debugging = done
 	}
 
+	p.markActivity()
 	return nil
 }
 
+// LastActivity returns the time when a packet was last transferred between
+// the program and its services, or when the process was started.
+//
+// This can be called concurrently with Start, Serve, Suspend, Kill and
+// itself.
+func (p *Process) LastActivity() time.Time {
+	return time.Unix(0, p.activity.Load())
+}
+
+func (p *Process) markActivity() {
+	p.activity.Store(time.Now().UnixNano())
+}
+
+// IOCounters returns the amount of packets transferred so far.
+//
+// This can be called concurrently with Start, Serve, Suspend, Kill and
+// itself.
+func (p *Process) IOCounters() IOCounters {
+	return IOCounters{
+		PacketsSent:     p.packetsTx.Load(),
+		BytesSent:       p.bytesTx.Load(),
+		PacketsReceived: p.packetsRx.Load(),
+		BytesReceived:   p.bytesRx.Load(),
+	}
+}
+
+// CPUTime returns the CPU time consumed by the process.  It is known only
+// after Serve has returned; zero is returned for host processes.
+func (p *Process) CPUTime() time.Duration {
+	return p.execution.cpuTime
+}
+
+func (p *Process) countSent(n int) {
+	p.packetsTx.Add(1)
+	p.bytesTx.Add(uint64(n))
+	p.markActivity()
+}
+
+func (p *Process) countReceived(n int) {
+	p.packetsRx.Add(1)
+	p.bytesRx.Add(uint64(n))
+	p.markActivity()
+}
+
 // Serve the user program until the process terminates.  Canceling the context
 // suspends the program.
 //
@@ -301,8 +399,17 @@ func (p *Process) Start(code ProgramCode, state ProgramState, policy ProcessPoli
 //
 // A meaningful trap id is returned also when an error is returned.  The result
 // is meaningful when trap is Exit and the process is not a host process.
+//
+// If ProcessPolicy.Replay was specified, services are not used and buffers are
+// returned as is.  ErrReplayDivergence is returned if the program's behavior
+// differs from the recording.
 func (p *Process) Serve(ctx Context, services ServiceRegistry, buffers *snapshot.Buffers) (Result, trap.ID, *snapshot.Buffers, error) {
-	buffers, err := ioLoop(contextWithProcess(ctx, p), services, p, buffers)
+	var err error
+	if p.replay != nil {
+		err = replayLoop(ctx, p, p.replay)
+	} else {
+		buffers, err = ioLoop(contextWithProcess(ctx, p), services, p, buffers)
+	}
 	if err != nil {
 		trapID := trap.InternalError
 		if badprogram.Is(err) {
@@ -344,6 +451,10 @@ func (p *Process) Serve(ctx Context, services ServiceRegistry, buffers *snapshot
 			}
 		}
 
+		if trapID == trap.Suspended {
+			trapID = p.suspendTrap()
+		}
+
 		return result, trapID, buffers, nil
 
 	case status.Signaled():
@@ -353,7 +464,7 @@ func (p *Process) Serve(ctx Context, services ServiceRegistry, buffers *snapshot
 
 		case s == syscall.SIGXCPU:
 			// During initialization (ok) or by force (instance stack is dirty).
-			return Result{}, trap.Suspended, buffers, nil
+			return Result{}, p.suspendTrap(), buffers, nil
 
 		default:
 			return Result{}, trap.InternalError, buffers, fmt.Errorf("process termination signal: %s", s)
@@ -364,6 +475,14 @@ func (p *Process) Serve(ctx Context, services ServiceRegistry, buffers *snapshot
 	}
 }
 
+// suspendTrap distinguishes CPU time limit from requested suspension.
+func (p *Process) suspendTrap() trap.ID {
+	if p.cpuLimit && !p.execution.suspendRequested() {
+		return trap.Timeout
+	}
+	return trap.Suspended
+}
+
 // Suspend the program if it is still running.  If suspended, Serve call will
 // return with the Suspended trap.  (The program may get suspended also through
 // other means.)
@@ -0,0 +1,281 @@
+// Copyright (c) 2026 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package runtime
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"gate.computer/gate/packet"
+
+	. "import.name/type/context"
+)
+
+// ErrReplayDivergence is returned by Process.Serve if the program doesn't
+// send the same packets as during recording.
+var ErrReplayDivergence = errors.New("runtime: replay diverged from recording")
+
+var recordingMagic = [8]byte{'g', 'a', 't', 'e', 'r', 'e', 'c', 0}
+
+const recordingVersion = 1
+
+// recordingHeader describes the time source of the program.  The program reads
+// the clock without involving the host, so the values it observes can't be
+// recorded individually.  Instead, the initial time and resolution are
+// recorded along with the time of each packet, and replay delivers packets
+// at the same points of the program's timeline.
+type recordingHeader struct {
+	Magic         [8]byte
+	Version       uint32
+	TimeMask      uint32 // Applied to the program's clock.
+	MonotonicTime uint64 // Handed to the program on start.
+}
+
+// Record kinds.
+const (
+	recordReceived = 1 // Packet delivered to the program.
+	recordSent     = 2 // Packet sent by the program.
+)
+
+type recordHeader struct {
+	Kind uint32
+	Size uint32
+	Time uint64 // Nanoseconds since process start.
+}
+
+// recorder writes packet stream of a process.
+type recorder struct {
+	w     *bufio.Writer
+	start time.Time
+}
+
+func newRecorder(w io.Writer, timeMask uint32, monotonicTime uint64) (*recorder, error) {
+	r := &recorder{
+		w:     bufio.NewWriter(w),
+		start: time.Now(),
+	}
+
+	header := recordingHeader{
+		Magic:         recordingMagic,
+		Version:       recordingVersion,
+		TimeMask:      timeMask,
+		MonotonicTime: monotonicTime,
+	}
+	if err := binary.Write(r.w, binary.LittleEndian, &header); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *recorder) record(kind uint32, p []byte) error {
+	header := recordHeader{
+		Kind: kind,
+		Size: uint32(len(p)),
+		Time: uint64(time.Since(r.start)),
+	}
+	if err := binary.Write(r.w, binary.LittleEndian, &header); err != nil {
+		return err
+	}
+	_, err := r.w.Write(p)
+	return err
+}
+
+func (r *recorder) flush() error {
+	return r.w.Flush()
+}
+
+type receivedPacket struct {
+	after int           // Number of packets sent by the program before this one.
+	time  time.Duration // Since process start.
+	buf   packet.Buf
+}
+
+// Recording of a process's packet stream.  It can be replayed by specifying
+// it in ProcessPolicy.
+type Recording struct {
+	TimeMask      uint32 // Clock resolution of the recorded program.
+	MonotonicTime uint64 // Initial time of the recorded program.
+
+	received []receivedPacket
+	sent     []packet.Buf
+}
+
+// ReadRecording which was written by a process started with
+// ProcessPolicy.Record.
+func ReadRecording(r io.Reader) (*Recording, error) {
+	r = bufio.NewReader(r)
+
+	var header recordingHeader
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return nil, fmt.Errorf("recording header: %w", err)
+	}
+	if header.Magic != recordingMagic {
+		return nil, errors.New("not a recording")
+	}
+	if header.Version != recordingVersion {
+		return nil, fmt.Errorf("unsupported recording version: %d", header.Version)
+	}
+
+	rec := &Recording{
+		TimeMask:      header.TimeMask,
+		MonotonicTime: header.MonotonicTime,
+	}
+
+	for {
+		var x recordHeader
+		if err := binary.Read(r, binary.LittleEndian, &x); err != nil {
+			if err == io.EOF {
+				return rec, nil
+			}
+			return nil, fmt.Errorf("recording: %w", err)
+		}
+
+		if x.Size < packet.HeaderSize {
+			return nil, fmt.Errorf("recorded packet has invalid size: %d", x.Size)
+		}
+
+		buf := make(packet.Buf, x.Size)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, fmt.Errorf("recording: %w", err)
+		}
+
+		switch x.Kind {
+		case recordReceived:
+			rec.received = append(rec.received, receivedPacket{len(rec.sent), time.Duration(x.Time), buf})
+
+		case recordSent:
+			rec.sent = append(rec.sent, buf)
+
+		default:
+			return nil, fmt.Errorf("unknown record kind: %d", x.Kind)
+		}
+	}
+}
+
+// replayer delivers recorded packets relative to process start.
+type replayer struct {
+	rec   *Recording
+	start time.Time
+}
+
+func newReplayer(rec *Recording) *replayer {
+	return &replayer{
+		rec:   rec,
+		start: time.Now(),
+	}
+}
+
+// replayLoop is like ioLoop, but packets are delivered from recording, and the
+// program's packets are compared against it.  A packet is not delivered before
+// the time when it was received during recording.  The program is suspended
+// when the recording has been exhausted.
+func replayLoop(ctx Context, subject *Process, r *replayer) error {
+	rec := r.rec
+
+	var (
+		dead      = subject.execution.dead
+		suspended = subject.suspended
+		done      = ctx.Done()
+		stopping  = false
+	)
+
+	subjectInput := subjectReadLoop(subject.reader, nil)
+	defer func() {
+		subject.reader.Close()
+		subject.reader = nil
+
+		for range subjectInput {
+		}
+	}()
+
+	subjectOutput := subjectWriteLoop(subject.writer)
+	subject.writer = nil
+	defer close(subjectOutput)
+
+	var (
+		numSent     int
+		numReceived int
+		timer       *time.Timer
+		due         <-chan time.Time
+	)
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+
+	for {
+		if !stopping && numSent == len(rec.sent) && numReceived == len(rec.received) {
+			stopping = true
+			subject.execution.suspend()
+		}
+
+		var nextEv packet.Buf
+		if numReceived < len(rec.received) && rec.received[numReceived].after <= numSent {
+			x := rec.received[numReceived]
+			if d := x.time - time.Since(r.start); d <= 0 {
+				nextEv = x.buf
+			} else if due == nil {
+				timer = time.NewTimer(d)
+				due = timer.C
+			}
+		}
+
+		var doSubjectOutput chan<- packet.Buf
+		if nextEv != nil && dead != nil {
+			doSubjectOutput = subjectOutput
+		}
+
+		select {
+		case read, ok := <-subjectInput:
+			if !ok {
+				panic("gate runtime process read goroutine panicked")
+			}
+
+			if read.err != nil {
+				if read.err != io.EOF {
+					return read.err
+				}
+				return nil
+			}
+
+			if numSent == len(rec.sent) {
+				if stopping {
+					continue // Recording ended with suspension.
+				}
+				return fmt.Errorf("%w: unexpected packet", ErrReplayDivergence)
+			}
+			if !bytes.Equal(read.buf, rec.sent[numSent]) {
+				return fmt.Errorf("%w: packet #%d differs", ErrReplayDivergence, numSent)
+			}
+			numSent++
+			subject.countSent(len(read.buf))
+
+		case doSubjectOutput <- nextEv:
+			numReceived++
+			subject.countReceived(len(nextEv))
+
+		case <-due:
+			due = nil
+
+		case <-dead:
+			dead = nil
+
+		case <-suspended:
+			suspended = nil
+			subject.execution.suspend()
+
+		case <-done:
+			done = nil
+			subject.execution.suspend()
+		}
+	}
+}
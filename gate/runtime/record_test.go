@@ -0,0 +1,184 @@
+// Copyright (c) 2026 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"gate.computer/gate/packet"
+)
+
+func TestRecording(t *testing.T) {
+	var b bytes.Buffer
+
+	r, err := newRecorder(&b, 0xfffff000, 12345)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info := packet.MakeInfo(packet.CodeServices, 0)
+	call := packet.MakeCall(0, 8)
+	reply := packet.MakeCall(0, 16)
+
+	for _, x := range []struct {
+		kind uint32
+		p    packet.Buf
+	}{
+		{recordReceived, info},
+		{recordSent, call},
+		{recordSent, call},
+		{recordReceived, reply},
+	} {
+		x.p.SetSize()
+		if err := r.record(x.kind, x.p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := r.flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	rec, err := ReadRecording(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rec.TimeMask != 0xfffff000 {
+		t.Error(rec.TimeMask)
+	}
+	if rec.MonotonicTime != 12345 {
+		t.Error(rec.MonotonicTime)
+	}
+	if len(rec.sent) != 2 || !bytes.Equal(rec.sent[0], call) || !bytes.Equal(rec.sent[1], call) {
+		t.Error(rec.sent)
+	}
+	if len(rec.received) != 2 {
+		t.Fatal(rec.received)
+	}
+	if x := rec.received[0]; x.after != 0 || !bytes.Equal(x.buf, info) {
+		t.Error(x)
+	}
+	if x := rec.received[1]; x.after != 2 || !bytes.Equal(x.buf, reply) {
+		t.Error(x)
+	}
+
+	if _, err := ReadRecording(bytes.NewReader([]byte("not a recording at all"))); err == nil {
+		t.Error("invalid recording accepted")
+	}
+}
+
+// replaySubject is a process without executor.  The test acts as the program
+// by writing to output and reading from input.
+func replaySubject(t *testing.T) (p *Process, output *os.File, input *os.File) {
+	t.Helper()
+
+	subjectReader, output, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { output.Close() })
+
+	input, subjectWriter, err := pipe2(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { input.Close() })
+
+	p = &Process{
+		reader:    subjectReader,
+		writer:    subjectWriter,
+		suspended: make(chan struct{}, 1),
+	}
+	p.execution.dead = make(chan struct{})
+	return
+}
+
+func makeReplayPacket(code packet.Code, size int) packet.Buf {
+	p := packet.MakeCall(code, size)
+	p.SetSize()
+	return p
+}
+
+func TestReplay(t *testing.T) {
+	const delay = time.Second / 10
+
+	info := packet.MakeInfo(packet.CodeServices, 8)
+	info.SetSize()
+	call := makeReplayPacket(0, 8)
+	reply := makeReplayPacket(0, 16)
+
+	rec := &Recording{
+		received: []receivedPacket{
+			{0, 0, info},
+			{1, delay, reply},
+		},
+		sent: []packet.Buf{call},
+	}
+
+	for _, x := range []struct {
+		name    string
+		sent    packet.Buf
+		diverge bool
+	}{
+		{"Success", call, false},
+		{"Divergence", makeReplayPacket(1, 8), true},
+	} {
+		t.Run(x.name, func(t *testing.T) {
+			p, output, input := replaySubject(t)
+			r := newReplayer(rec)
+
+			done := make(chan error, 1)
+			go func() {
+				done <- replayLoop(context.Background(), p, r)
+			}()
+
+			buf := make([]byte, len(info))
+			if _, err := io.ReadFull(input, buf); err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(buf, info) {
+				t.Error(buf)
+			}
+
+			if _, err := output.Write(x.sent); err != nil {
+				t.Fatal(err)
+			}
+
+			if x.diverge {
+				if err := <-done; !errors.Is(err, ErrReplayDivergence) {
+					t.Error(err)
+				}
+				return
+			}
+
+			buf = make([]byte, len(reply))
+			if _, err := io.ReadFull(input, buf); err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(buf, reply) {
+				t.Error(buf)
+			}
+			if d := time.Since(r.start); d < delay {
+				t.Errorf("packet delivered after %v", d)
+			}
+
+			output.Close()
+			if err := <-done; err != nil {
+				t.Error(err)
+			}
+
+			c := p.IOCounters()
+			if c.PacketsSent != 1 || c.PacketsReceived != 2 {
+				t.Error(c)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@
 package server
 
 import (
+	"context"
 	"time"
 
 	"gate.computer/wag/wa"
@@ -17,6 +18,7 @@ const (
 	DefaultMaxProcs          = 4
 	DefaultTotalStorageSize  = 256 * 1024 * 1024
 	DefaultTotalResidentSize = 64 * 1024 * 1024
+	DefaultTotalKeyValueSize = 16 * 1024 * 1024
 	DefaultMaxModuleSize     = 32 * 1024 * 1024
 	DefaultMaxTextSize       = 16 * 1024 * 1024
 	DefaultMaxMemorySize     = 32 * 1024 * 1024
@@ -24,24 +26,45 @@ const (
 	DefaultTimeResolution    = time.Second / 100
 )
 
-// TODO: ResourcePolicy is not yet enforced by server
+// ResourcePolicy limits are enforced per account.  Exceeding them causes
+// FailResourceLimit errors.
 type ResourcePolicy struct {
 	MaxModules        int // Pinned module limit.
 	MaxProcs          int // Active instance limit.
-	TotalStorageSize  int // Sum of pinned module and metadata sizes.
-	TotalResidentSize int // Sum of all memory mapping and buffer sizes.
+	TotalStorageSize  int // Sum of pinned module sizes.
+	TotalResidentSize int // Sum of active instances' memory mapping sizes.
+	TotalKeyValueSize int // Sum of key-value storage key and value sizes.
+}
+
+type contextResourcePolicyValueKey struct{}
+
+// ContextResourcePolicy returns the resource policy of the principal whose
+// instance is being allocated.  It is available to InstancePolicy.Services
+// function.
+func ContextResourcePolicy(ctx Context) *ResourcePolicy {
+	p, _ := ctx.Value(contextResourcePolicyValueKey{}).(*ResourcePolicy)
+	return p
+}
+
+func contextWithResourcePolicy(ctx Context, p *ResourcePolicy) Context {
+	return context.WithValue(ctx, contextResourcePolicyValueKey{}, p)
 }
 
 type ProgramPolicy struct {
-	MaxModuleSize int // WebAssembly module size.
-	MaxTextSize   int // Native program code size.
-	MaxStackSize  int // Suspended stack size.
+	MaxModuleSize       int  // WebAssembly module size.
+	MaxTextSize         int  // Native program code size.
+	MaxStackSize        int  // Suspended stack size.
+	IncrementalSnapshot bool // Store only memory pages changed since previous snapshot.
 }
 
 type InstancePolicy struct {
 	MaxMemorySize  int           // Linear memory growth limit.
 	StackSize      int           // Including system/runtime overhead.
 	TimeResolution time.Duration // Granularity of time functions.
+	MaxWallTime    time.Duration // Per launch or resume; zero means unlimited.
+	MaxCPUTime     time.Duration // Per launch or resume; zero means unlimited.
+	IdleTimeout    time.Duration // Hibernate without packet traffic; zero means never.
+	CrashSnapshot  bool          // Store snapshot module if killed by a trap.
 
 	// Services function defines which services are discoverable by the
 	// instance.
@@ -130,16 +153,22 @@ var DefaultAccessConfig = AccessConfig{
 		DefaultMaxProcs,
 		DefaultTotalStorageSize,
 		DefaultTotalResidentSize,
+		DefaultTotalKeyValueSize,
 	},
 	ProgramPolicy{
 		DefaultMaxModuleSize,
 		DefaultMaxTextSize,
 		DefaultStackSize,
+		false,
 	},
 	InstancePolicy{
 		DefaultMaxMemorySize,
 		DefaultStackSize,
 		DefaultTimeResolution,
+		0,
+		0,
+		0,
+		false,
 		nil,
 	},
 }
@@ -158,6 +187,9 @@ func (config *AccessConfig) ConfigureResource(p *ResourcePolicy) {
 	if p.TotalResidentSize == 0 {
 		p.TotalResidentSize = DefaultTotalResidentSize
 	}
+	if p.TotalKeyValueSize == 0 {
+		p.TotalKeyValueSize = DefaultTotalKeyValueSize
+	}
 }
 
 func (config *AccessConfig) ConfigureProgram(p *ProgramPolicy) {
@@ -6,7 +6,9 @@ package server
 
 import (
 	"reflect"
+	"sync"
 
+	"gate.computer/gate/image"
 	"gate.computer/gate/server/event"
 	"gate.computer/gate/server/internal/error/failrequest"
 	pb "gate.computer/internal/pb/server"
@@ -21,9 +23,16 @@ type accountInstance struct {
 type account struct {
 	*principal.ID
 
+	procMu   sync.Mutex
+	procs    int   // Number of instances which have been allocated a process.
+	resident int64 // Sum of the processes' resident sizes.
+
+	watchers instanceWatchers
+
 	// Protected by server mutex:
-	programs  map[*program]*pb.Module
-	instances map[string]accountInstance
+	programs    map[*program]*pb.Module
+	instances   map[string]accountInstance
+	storageSize int64 // Sum of pinned module sizes.
 }
 
 func newAccount(pri *principal.ID) *account {
@@ -37,6 +46,7 @@ func newAccount(pri *principal.ID) *account {
 func (acc *account) shutdown(lock serverLock) map[string]accountInstance {
 	ps := acc.programs
 	acc.programs = nil
+	acc.storageSize = 0
 
 	for prog := range ps {
 		prog.unref(lock)
@@ -55,6 +65,7 @@ func (acc *account) ensureProgramRef(lock serverLock, prog *program, tags []stri
 		prog.ref(lock)
 		x = new(pb.Module)
 		modified = true
+		acc.storageSize += prog.image.ModuleSize()
 	}
 	if len(tags) != 0 && !reflect.DeepEqual(x.Tags, tags) {
 		x.Tags = append([]string(nil), tags...)
@@ -79,11 +90,61 @@ func (acc *account) unrefProgram(lock serverLock, prog *program) (found bool) {
 	_, found = acc.programs[prog]
 	if found {
 		delete(acc.programs, prog)
+		acc.storageSize -= prog.image.ModuleSize()
 		prog.unref(lock)
 	}
 	return
 }
 
+// mustCheckProgramRefLimits panics if adding the program reference would
+// exceed resource limits.  Existing references are not checked.
+func (acc *account) mustCheckProgramRefLimits(lock serverLock, prog *program, res *ResourcePolicy) {
+	if _, found := acc.programs[prog]; found {
+		return
+	}
+
+	if len(acc.programs) >= res.MaxModules {
+		z.Panic(failrequest.Error(event.FailResourceLimit, "pinned module limit exceeded"))
+	}
+	if acc.storageSize+prog.image.ModuleSize() > int64(res.TotalStorageSize) {
+		z.Panic(failrequest.Error(event.FailResourceLimit, "total storage size limit exceeded"))
+	}
+}
+
+// residentSize estimates the amount of memory mapped by a process which runs
+// the instance.  Linear memory is counted at its current size.
+func residentSize(prog *image.Program, inst *image.Instance) int64 {
+	return int64(prog.TextSize()) + int64(inst.StackSize()) + int64(inst.GlobalsSize()) + int64(inst.MemorySize())
+}
+
+// mustReserveProc for an instance which is about to be allocated a process.
+// It must be paired with releaseProc.
+func (acc *account) mustReserveProc(res *ResourcePolicy, residentSize int64) {
+	acc.procMu.Lock()
+	defer acc.procMu.Unlock()
+
+	if acc.procs >= res.MaxProcs {
+		z.Panic(failrequest.Error(event.FailResourceLimit, "active instance limit exceeded"))
+	}
+	if acc.resident+residentSize > int64(res.TotalResidentSize) {
+		z.Panic(failrequest.Error(event.FailResourceLimit, "total resident size limit exceeded"))
+	}
+
+	acc.procs++
+	acc.resident += residentSize
+}
+
+func (acc *account) releaseProc(residentSize int64) {
+	acc.procMu.Lock()
+	defer acc.procMu.Unlock()
+
+	acc.procs--
+	acc.resident -= residentSize
+	if acc.procs < 0 || acc.resident < 0 {
+		panic("negative account process usage")
+	}
+}
+
 func (acc *account) mustCheckUniqueInstanceID(lock serverLock, id string) {
 	if _, found := acc.instances[id]; !found {
 		return
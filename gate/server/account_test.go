@@ -0,0 +1,57 @@
+// Copyright (c) 2026 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"testing"
+
+	"gate.computer/gate/server/event"
+	"gate.computer/gate/server/internal/error/failrequest"
+	"gate.computer/internal/principal"
+	"github.com/stretchr/testify/assert"
+)
+
+func reserveProc(acc *account, res *ResourcePolicy, residentSize int64) (err error) {
+	defer func() {
+		if x := recover(); x != nil {
+			err = z.Error(x)
+		}
+	}()
+
+	acc.mustReserveProc(res, residentSize)
+	return nil
+}
+
+func TestAccountProcLimits(t *testing.T) {
+	acc := newAccount(principal.LocalID)
+	res := &ResourcePolicy{
+		MaxProcs:          2,
+		TotalResidentSize: 1000,
+	}
+
+	assert.NoError(t, reserveProc(acc, res, 600))
+
+	err := reserveProc(acc, res, 500)
+	if assert.NotNil(t, failrequest.AsError(err)) {
+		assert.Equal(t, failrequest.AsError(err).FailType(), event.FailResourceLimit)
+	}
+
+	assert.NoError(t, reserveProc(acc, res, 400))
+
+	err = reserveProc(acc, res, 0)
+	if assert.NotNil(t, failrequest.AsError(err)) {
+		assert.Equal(t, failrequest.AsError(err).FailType(), event.FailResourceLimit)
+	}
+
+	acc.releaseProc(600)
+	assert.NoError(t, reserveProc(acc, res, 500))
+
+	acc.releaseProc(500)
+	acc.releaseProc(400)
+	assert.Equal(t, acc.procs, 0)
+	assert.Equal(t, acc.resident, int64(0))
+
+	assert.Panics(t, func() { acc.releaseProc(0) })
+}
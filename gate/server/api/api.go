@@ -9,23 +9,29 @@ import (
 )
 
 type (
-	Cause          = pb.Cause
-	DebugConfig    = pb.DebugConfig
-	DebugOp        = pb.DebugOp
-	DebugRequest   = pb.DebugRequest
-	DebugResponse  = pb.DebugResponse
-	Features       = pb.Features
-	InstanceInfo   = pb.InstanceInfo
-	InstanceUpdate = pb.InstanceUpdate
-	Instances      = pb.Instances
-	InvokeOptions  = pb.InvokeOptions
-	LaunchOptions  = pb.LaunchOptions
-	ModuleInfo     = pb.ModuleInfo
-	ModuleOptions  = pb.ModuleOptions
-	Modules        = pb.Modules
-	ResumeOptions  = pb.ResumeOptions
-	State          = pb.State
-	Status         = pb.Status
+	Cause               = pb.Cause
+	DebugConfig         = pb.DebugConfig
+	DebugOp             = pb.DebugOp
+	DebugRequest        = pb.DebugRequest
+	DebugResponse       = pb.DebugResponse
+	Features            = pb.Features
+	InstanceInfo        = pb.InstanceInfo
+	InstanceListOptions = pb.InstanceListOptions
+	InstanceUsage       = pb.InstanceUsage
+	InstanceUpdate      = pb.InstanceUpdate
+	Instances           = pb.Instances
+	InvokeOptions       = pb.InvokeOptions
+	LaunchOptions       = pb.LaunchOptions
+	ModuleInfo          = pb.ModuleInfo
+	ModuleLineage       = pb.ModuleLineage
+	ModuleListOptions   = pb.ModuleListOptions
+	ModuleOptions       = pb.ModuleOptions
+	Modules             = pb.Modules
+	ResumeOptions       = pb.ResumeOptions
+	StackFrame          = pb.StackFrame
+	State               = pb.State
+	Status              = pb.Status
+	TimeBudget          = pb.TimeBudget
 )
 
 const (
@@ -49,6 +55,7 @@ const (
 	CauseABIDeficiency                 = pb.Cause_ABI_DEFICIENCY
 	CauseABIViolation                  = pb.Cause_ABI_VIOLATION
 	CauseInternal                      = pb.Cause_INTERNAL
+	CauseTimeout                       = pb.Cause_TIMEOUT
 )
 
 const (
@@ -59,4 +66,8 @@ const (
 	DebugOpReadGlobals      = pb.DebugOp_READ_GLOBALS
 	DebugOpReadMemory       = pb.DebugOp_READ_MEMORY
 	DebugOpReadStack        = pb.DebugOp_READ_STACK
+	DebugOpWriteMemory      = pb.DebugOp_WRITE_MEMORY
+	DebugOpWriteGlobal      = pb.DebugOp_WRITE_GLOBAL
+	DebugOpStep             = pb.DebugOp_STEP
+	DebugOpReadStacktrace   = pb.DebugOp_READ_STACKTRACE
 )
@@ -11,16 +11,17 @@ import (
 )
 
 type Server interface {
+	CloneInstance(Context, string, *LaunchOptions) (Instance, error)
 	DebugInstance(Context, string, *DebugRequest) (*DebugResponse, error)
 	DeleteInstance(Context, string) error
 	Features() *Features
 	InstanceConnection(Context, string) (Instance, func(Context, io.Reader, io.WriteCloser) *Status, error)
 	InstanceInfo(Context, string) (*InstanceInfo, error)
-	Instances(Context) (*Instances, error)
+	Instances(Context, *InstanceListOptions) (*Instances, error)
 	KillInstance(Context, string) (Instance, error)
 	ModuleContent(Context, string) (io.ReadCloser, int64, error)
 	ModuleInfo(Context, string) (*ModuleInfo, error)
-	Modules(Context) (*Modules, error)
+	Modules(Context, *ModuleListOptions) (*Modules, error)
 	NewInstance(Context, string, *LaunchOptions) (Instance, error)
 	PinModule(Context, string, *ModuleOptions) error
 	ResumeInstance(Context, string, *ResumeOptions) (Instance, error)
@@ -34,6 +35,7 @@ type Server interface {
 	UploadModule(Context, *ModuleUpload, *ModuleOptions) (string, error)
 	UploadModuleInstance(Context, *ModuleUpload, *ModuleOptions, *LaunchOptions) (string, Instance, error)
 	WaitInstance(Context, string) (*Status, error)
+	WatchInstances(Context) (<-chan *InstanceInfo, error)
 }
 
 type Instance interface {
@@ -40,6 +40,8 @@ const (
 	OpInstanceDelete   = pb.Op_INSTANCE_DELETE
 	OpInstanceUpdate   = pb.Op_INSTANCE_UPDATE
 	OpInstanceDebug    = pb.Op_INSTANCE_DEBUG
+	OpInstanceWatch    = pb.Op_INSTANCE_WATCH
+	OpInstanceClone    = pb.Op_INSTANCE_CLONE
 )
 
 func ContextOp(ctx Context) Op {
@@ -0,0 +1,290 @@
+// Copyright (c) 2026 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"log/slog"
+	"slices"
+	"time"
+
+	"gate.computer/gate/server/api"
+	"gate.computer/gate/server/event"
+	"gate.computer/gate/server/internal"
+	"gate.computer/gate/server/model"
+	pb "gate.computer/internal/pb/server"
+	"import.name/lock"
+
+	. "import.name/type/context"
+)
+
+// CollectConfig for removing stale instances and programs.  Zero TTL means
+// never.
+type CollectConfig struct {
+	Interval      time.Duration // Background collection is disabled if zero.
+	HaltedTTL     time.Duration // Time since an instance halted.
+	TerminatedTTL time.Duration // Time since an instance terminated or was killed.
+
+	// Programs which are not referenced by any account or instance are
+	// removed from the server and image storage.  Pinned modules are
+	// restored from inventory during initialization, so programs are
+	// collected only if the inventory implements model.InventoryLister.
+	// Programs of instance images in storage are retained even if the
+	// instances were not restored.
+	Programs bool
+
+	// DryRun collection only reports what would be removed.
+	DryRun bool
+}
+
+func (c *CollectConfig) ttl(state api.State) time.Duration {
+	switch state {
+	case api.StateHalted:
+		return c.HaltedTTL
+
+	case api.StateTerminated, api.StateKilled:
+		return c.TerminatedTTL
+
+	default:
+		return 0
+	}
+}
+
+// CollectReport lists removed (or removable, if dry-run) instances and
+// programs.
+type CollectReport struct {
+	DryRun    bool
+	Instances []CollectedInstance
+	Modules   []string
+}
+
+type CollectedInstance struct {
+	Principal string
+	Instance  string
+	Module    string
+	Status    *api.Status
+	Stopped   time.Time
+}
+
+// Collect expired instances and unreferenced programs once.  Default
+// configuration is used if config is nil.  Removals are reported as events.
+func (s *Server) Collect(ctx Context, config *CollectConfig) (_ *CollectReport, err error) {
+	if internal.DontPanic() {
+		defer func() { err = z.Error(recover()) }()
+	}
+
+	if config == nil {
+		config = &s.Config.Collector
+	}
+
+	report := &CollectReport{
+		DryRun: config.DryRun,
+	}
+
+	s.collectInstances(ctx, config, report)
+
+	if _, ok := s.Inventory.(model.InventoryLister); ok && config.Programs {
+		s.collectPrograms(ctx, config, report)
+	}
+
+	return report, nil
+}
+
+func (s *Server) collectInstances(ctx Context, config *CollectConfig, report *CollectReport) {
+	if config.HaltedTTL <= 0 && config.TerminatedTTL <= 0 {
+		return
+	}
+
+	type candidate struct {
+		inst   *Instance
+		module string
+	}
+
+	var candidates []candidate
+
+	lock.GuardTag(&s.mu, func(lock serverLock) {
+		for _, acc := range s.accounts {
+			for _, x := range acc.instances {
+				candidates = append(candidates, candidate{x.inst, x.prog.id})
+			}
+		}
+	})
+
+	now := time.Now()
+
+	for _, x := range candidates {
+		inst := x.inst
+
+		status, stopped, inventoried, expired := inst.collect(config, now)
+		if !expired {
+			continue
+		}
+
+		report.Instances = append(report.Instances, CollectedInstance{
+			Principal: inst.acc.ID.String(),
+			Instance:  inst.id,
+			Module:    x.module,
+			Status:    status,
+			Stopped:   stopped,
+		})
+
+		if config.DryRun {
+			continue
+		}
+
+		s.deleteNonexistentInstance(inst)
+
+		if inventoried {
+			if err := s.Inventory.RemoveInstance(ctx, *inst.acc.ID, inst.id); err != nil {
+				s.eventFail(ctx, event.TypeFailInternal, internalFail(x.module, "", inst.id, "inventory", err), err)
+			}
+		}
+
+		s.eventInstance(ctx, event.TypeInstanceCollect, &event.Instance{
+			Instance: inst.id,
+			Module:   x.module,
+			Status:   status,
+		}, nil)
+	}
+}
+
+// collectPrograms removes programs which are referenced only by the server.
+// Stored programs which are parents of incremental snapshots of referenced
+// programs are retained, as are programs of stored instance images which
+// were not restored.  Nothing is removed if the program of an instance image
+// is unknown.
+func (s *Server) collectPrograms(ctx Context, config *CollectConfig, report *CollectReport) {
+	stored, known := s.mustListStoredInstanceModules(ctx)
+	if !known {
+		slog.WarnContext(ctx, "server: programs not collected because instance image has no inventory record")
+		return
+	}
+
+	var removed []string
+
+	lock.GuardTag(&s.mu, func(lock serverLock) {
+		keep := make(map[string]bool)
+
+		retain := func(prog *program) {
+			for p := prog; p != nil && !keep[p.id]; p = s.programs[p.image.Parent()] {
+				keep[p.id] = true
+			}
+		}
+
+		for _, prog := range s.programs {
+			if prog.refCount > 1 {
+				retain(prog)
+			}
+		}
+		for id := range stored {
+			retain(s.programs[id])
+		}
+
+		for id, prog := range s.programs {
+			if keep[id] {
+				continue
+			}
+
+			if config.DryRun {
+				report.Modules = append(report.Modules, id)
+				continue
+			}
+
+			if err := s.removeProgramStorage(prog); err != nil {
+				s.eventFail(ctx, event.TypeFailInternal, internalFail(id, "", "", "image storage", err), err)
+				continue
+			}
+
+			delete(s.programs, id)
+			prog.unref(lock)
+			removed = append(removed, id)
+		}
+	})
+
+	slices.Sort(report.Modules)
+	slices.Sort(removed)
+
+	for _, id := range removed {
+		report.Modules = append(report.Modules, id)
+
+		s.eventModule(ctx, event.TypeModuleCollect, &event.Module{
+			Module: id,
+		})
+	}
+}
+
+// mustListStoredInstanceModules returns the programs of all instance images in
+// image storage according to their inventory records.  The known flag is
+// false if an instance image has no usable record.
+func (s *Server) mustListStoredInstanceModules(ctx Context) (modules map[string]bool, known bool) {
+	modules = make(map[string]bool)
+	known = true
+
+	for _, key := range must(s.ImageStorage.Instances()) {
+		pri, instID, _, err := parseInstanceStorageKey(key)
+		if err != nil {
+			known = false
+			continue
+		}
+
+		record := new(pb.Instance)
+		if !must(s.Inventory.GetInstance(ctx, *pri, instID, record)) || record.Module == "" {
+			known = false
+			continue
+		}
+
+		modules[record.Module] = true
+	}
+
+	return modules, known
+}
+
+// removeProgramStorage must be called with server mutex locked.  The program
+// is marked as collected so that an identical program which is registered
+// later is stored again.
+func (s *Server) removeProgramStorage(prog *program) error {
+	prog.storeMu.Lock()
+	defer prog.storeMu.Unlock()
+
+	if !prog.stored {
+		return nil
+	}
+
+	if err := s.ImageStorage.RemoveProgram(prog.id); err != nil {
+		return err
+	}
+
+	prog.stored = false
+	s.collected[prog.id] = struct{}{}
+	return nil
+}
+
+// collectLoop runs until the context is done.
+func (s *Server) collectLoop(ctx Context) {
+	ticker := time.NewTicker(s.Collector.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+
+		report, err := s.Collect(ctx, &s.Config.Collector)
+		if err != nil {
+			slog.ErrorContext(ctx, "server: collection failed", "error", err)
+			continue
+		}
+
+		if report.DryRun {
+			for _, x := range report.Instances {
+				slog.InfoContext(ctx, "server: instance is collectable", "principal", x.Principal, "instance", x.Instance, "module", x.Module, "state", x.Status.State, "stopped", x.Stopped)
+			}
+			for _, module := range report.Modules {
+				slog.InfoContext(ctx, "server: module is collectable", "module", module)
+			}
+		}
+	}
+}
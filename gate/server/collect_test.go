@@ -0,0 +1,57 @@
+// Copyright (c) 2026 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"testing"
+	"time"
+
+	"gate.computer/gate/server/api"
+	pb "gate.computer/internal/pb/server"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestInstanceCollectDryRun(t *testing.T) {
+	now := time.Now()
+
+	config := &CollectConfig{
+		HaltedTTL: time.Hour,
+		DryRun:    true,
+	}
+
+	for i, x := range []struct {
+		state   api.State
+		age     time.Duration
+		expired bool
+	}{
+		{api.StateHalted, 2 * time.Hour, true},
+		{api.StateHalted, time.Minute, false},
+		{api.StateTerminated, 2 * time.Hour, false},
+		{api.StateSuspended, 2 * time.Hour, false},
+		{api.StateRunning, 2 * time.Hour, false},
+	} {
+		stopped := now.Add(-x.age)
+
+		inst := &Instance{
+			id: "test",
+			model: &pb.Instance{
+				Exists:  true,
+				Status:  &api.Status{State: x.state},
+				Stopped: timestamppb.New(stopped),
+			},
+		}
+
+		status, at, _, expired := inst.collect(config, now)
+		if expired != x.expired {
+			t.Errorf("%d: expired = %v", i, expired)
+		}
+		if expired && (status.State != x.state || !at.Equal(stopped)) {
+			t.Errorf("%d: status = %v, stopped = %v", i, status, at)
+		}
+		if !inst.model.Exists {
+			t.Errorf("%d: instance removed during dry run", i)
+		}
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"gate.computer/gate/runtime"
 	"gate.computer/gate/server/api"
 	"gate.computer/gate/server/event"
+	"gate.computer/gate/server/internal/error/failrequest"
 	"gate.computer/gate/server/model"
 	"gate.computer/gate/source"
 	"gate.computer/internal/serverapi"
@@ -34,6 +35,13 @@ type InstanceServices interface {
 	runtime.ServiceRegistry
 }
 
+// InstanceWaker may be implemented by InstanceServices.  Services are kept
+// while an instance is hibernated due to idleness, and a receive from the Wake
+// channel resumes it.
+type InstanceWaker interface {
+	Wake() <-chan struct{}
+}
+
 func NewInstanceServices(c InstanceConnector, r runtime.ServiceRegistry) InstanceServices {
 	return &struct {
 		InstanceConnector
@@ -41,16 +49,54 @@ func NewInstanceServices(c InstanceConnector, r runtime.ServiceRegistry) Instanc
 	}{c, r}
 }
 
+// NewWakingInstanceServices is like NewInstanceServices, but the returned
+// value implements InstanceWaker.  The waker is closed with the connector.
+func NewWakingInstanceServices(c InstanceConnector, r runtime.ServiceRegistry, w interface {
+	InstanceWaker
+	io.Closer
+}) InstanceServices {
+	return &wakingServices{c, r, w}
+}
+
+type wakingServices struct {
+	InstanceConnector
+	runtime.ServiceRegistry
+	waker interface {
+		InstanceWaker
+		io.Closer
+	}
+}
+
+func (s *wakingServices) Wake() <-chan struct{} {
+	return s.waker.Wake()
+}
+
+func (s *wakingServices) Close() error {
+	err := s.InstanceConnector.Close()
+	if e := s.waker.Close(); err == nil {
+		err = e
+	}
+	return err
+}
+
 type Config struct {
 	UUID           string
 	ImageStorage   image.Storage
 	Inventory      model.Inventory
 	ProcessFactory runtime.ProcessFactory
 	AccessPolicy   Authorizer
+	RateLimit      RateLimit
+	Collector      CollectConfig
 	ModuleSources  map[string]source.Source
 	SourceCache    model.SourceCache
 	OpenDebugLog   func(string) io.WriteCloser
 
+	// OpenRecording and OpenReplay resolve the names specified in
+	// InvokeOptions.  Packet recording and replay are supported only when
+	// launching an instance.  See gate.computer/gate/runtime.Recording.
+	OpenRecording func(string) io.WriteCloser
+	OpenReplay    func(string) io.ReadCloser
+
 	// StartSpan within trace context, ending when endSpan is called.  See
 	// gate.computer/gate/trace/tracelink.
 	StartSpan func(_ Context, op api.Op) (_ Context, endSpan func(Context))
@@ -71,6 +117,41 @@ func (c *Config) openDebugLog(opt *api.InvokeOptions) io.WriteCloser {
 	return nil
 }
 
+func (c *Config) openRecording(opt *api.InvokeOptions) io.WriteCloser {
+	if c.OpenRecording != nil && opt != nil && opt.Record != "" {
+		return c.OpenRecording(opt.Record)
+	}
+	return nil
+}
+
+func (c *Config) mustReadReplay(opt *api.InvokeOptions) *runtime.Recording {
+	if opt.GetReplay() == "" {
+		return nil
+	}
+
+	var r io.ReadCloser
+	if c.OpenReplay != nil {
+		r = c.OpenReplay(opt.Replay)
+	}
+	if r == nil {
+		z.Panic(failrequest.Error(event.FailUnsupported, "recording cannot be replayed"))
+	}
+	defer r.Close()
+
+	rec, err := runtime.ReadRecording(r)
+	if err != nil {
+		z.Panic(failrequest.WrapError(event.FailPayloadError, "invalid recording", err))
+	}
+	return rec
+}
+
+// mustCheckInvokeOptions of an existing instance.
+func mustCheckInvokeOptions(opt *api.InvokeOptions) {
+	if opt.GetRecord() != "" || opt.GetReplay() != "" {
+		z.Panic(failrequest.Error(event.FailUnsupported, "packet recording and replay are supported only when launching an instance"))
+	}
+}
+
 func (c *Config) startOp(ctx Context, op api.Op) (Context, func(Context)) {
 	ctx = serverapi.ContextWithOp(ctx, op)
 	return c.StartSpan(ctx, op)
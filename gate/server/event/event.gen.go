@@ -9,6 +9,9 @@ const (
 	TypeFailNetwork          = pb.Type_FAIL_NETWORK
 	TypeFailProtocol         = pb.Type_FAIL_PROTOCOL
 	TypeFailRequest          = pb.Type_FAIL_REQUEST
+	TypeInstanceCheckpoint   = pb.Type_INSTANCE_CHECKPOINT
+	TypeInstanceClone        = pb.Type_INSTANCE_CLONE
+	TypeInstanceCollect      = pb.Type_INSTANCE_COLLECT
 	TypeInstanceConnect      = pb.Type_INSTANCE_CONNECT
 	TypeInstanceCreateHost   = pb.Type_INSTANCE_CREATE_HOST
 	TypeInstanceCreateKnown  = pb.Type_INSTANCE_CREATE_KNOWN
@@ -25,6 +28,8 @@ const (
 	TypeInstanceSuspend      = pb.Type_INSTANCE_SUSPEND
 	TypeInstanceUpdate       = pb.Type_INSTANCE_UPDATE
 	TypeInstanceWait         = pb.Type_INSTANCE_WAIT
+	TypeInstanceWatch        = pb.Type_INSTANCE_WATCH
+	TypeModuleCollect        = pb.Type_MODULE_COLLECT
 	TypeModuleDownload       = pb.Type_MODULE_DOWNLOAD
 	TypeModuleInfo           = pb.Type_MODULE_INFO
 	TypeModuleList           = pb.Type_MODULE_LIST
@@ -5,12 +5,16 @@
 package server
 
 import (
+	"encoding/binary"
 	"fmt"
 	"io"
 	"log/slog"
+	"math"
 	"path"
 	"reflect"
+	"slices"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"gate.computer/gate/image"
@@ -21,6 +25,7 @@ import (
 	"gate.computer/gate/server/event"
 	"gate.computer/gate/server/internal/error/failrequest"
 	"gate.computer/gate/server/internal/error/notfound"
+	"gate.computer/gate/service"
 	"gate.computer/gate/snapshot"
 	"gate.computer/gate/trap"
 	"gate.computer/internal/dedup"
@@ -29,8 +34,14 @@ import (
 	pb "gate.computer/internal/pb/server"
 	internal "gate.computer/internal/principal"
 	"gate.computer/wag/object"
+	objectdebug "gate.computer/wag/object/debug"
+	"gate.computer/wag/object/stack"
+	"gate.computer/wag/wa"
+	"gate.computer/wag/wa/opcode"
 	"github.com/google/uuid"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	"import.name/lock"
 
 	. "import.name/type/context"
@@ -63,26 +74,48 @@ func ValidateInstanceUUIDForm(s string) error {
 	return failrequest.Error(event.FailInstanceIDInvalid, "instance UUID must use lower-case hex encoding")
 }
 
-func instanceServingContext(ctx Context, id string) Context {
+func instanceServingContext(ctx Context, id, module string) Context {
 	ctx = principal.ContextWithInstanceUUID(ctx, uuid.Must(uuid.Parse(id)))
+	if module != "" {
+		ctx = principal.ContextWithModuleID(ctx, module)
+	}
 	ctx = programscope.ContextWithScope(ctx)
 	return ctx
 }
 
+const checkpointStorageSuffix = "~checkpoint"
+
 func instanceStorageKey(pri *internal.ID, instID string) string {
 	return fmt.Sprintf("%s.%s", pri.String(), instID)
 }
 
-func mustParseInstanceStorageKey(key string) (*internal.ID, string) {
+func checkpointStorageKey(pri *internal.ID, instID string) string {
+	return instanceStorageKey(pri, instID) + checkpointStorageSuffix
+}
+
+func mustParseInstanceStorageKey(key string) (pri *internal.ID, instID string, checkpoint bool) {
+	pri, instID, checkpoint, err := parseInstanceStorageKey(key)
+	z.Check(err)
+	return
+}
+
+func parseInstanceStorageKey(key string) (pri *internal.ID, instID string, checkpoint bool, err error) {
+	key, checkpoint = strings.CutSuffix(key, checkpointStorageSuffix)
+
 	i := strings.LastIndexByte(key, '.')
 	if i < 0 {
-		z.Panic(fmt.Errorf("invalid instance storage key: %q", key))
+		err = fmt.Errorf("invalid instance storage key: %q", key)
+		return
+	}
+
+	pri, err = internal.ParseID(key[:i])
+	if err != nil {
+		return
 	}
 
-	pri := must(internal.ParseID(key[:i]))
-	instID := key[i+1:]
-	z.Check(ValidateInstanceUUIDForm(instID))
-	return pri, instID
+	instID = key[i+1:]
+	err = ValidateInstanceUUIDForm(instID)
+	return
 }
 
 // trapStatus converts non-exit trap id to non-final instance state and cause.
@@ -91,7 +124,7 @@ func trapStatus(id trap.ID) (api.State, api.Cause) {
 	case trap.Suspended:
 		return api.StateSuspended, api.CauseNormal
 
-	case trap.CallStackExhausted, trap.ABIDeficiency, trap.Breakpoint:
+	case trap.CallStackExhausted, trap.ABIDeficiency, trap.Breakpoint, trap.Timeout:
 		return api.StateSuspended, api.Cause(id)
 
 	case trap.Killed:
@@ -102,6 +135,48 @@ func trapStatus(id trap.ID) (api.State, api.Cause) {
 	}
 }
 
+// crashCause reports if the cause is a trap which kills the instance.
+func crashCause(cause api.Cause) bool {
+	switch cause {
+	case api.CauseUnreachable, api.CauseMemoryAccessOutOfBounds, api.CauseIndirectCallIndexOutOfBounds, api.CauseIndirectCallSignatureMismatch, api.CauseIntegerDivideByZero, api.CauseIntegerOverflow:
+		return true
+
+	default:
+		return false
+	}
+}
+
+// crashSnapshot of an instance which was killed by a trap.  The instance image
+// is detached from the instance so that the snapshot can be created outside
+// of the instance lock.
+type crashSnapshot struct {
+	image   *image.Instance
+	buffers *snapshot.Buffers
+	cause   api.Cause
+	res     ResourcePolicy
+}
+
+// timeBudget of a single run.  Zero durations mean unlimited.
+type timeBudget struct {
+	wallTime time.Duration
+	cpuTime  time.Duration
+	kill     bool
+}
+
+// consume time spent by a process which was paused.  The remaining budget
+// doesn't become unlimited when it runs out.
+func (b *timeBudget) consume(wallTime, cpuTime time.Duration) {
+	b.wallTime = remainingTime(b.wallTime, wallTime)
+	b.cpuTime = remainingTime(b.cpuTime, cpuTime)
+}
+
+func remainingTime(budget, spent time.Duration) time.Duration {
+	if budget <= 0 {
+		return budget
+	}
+	return max(budget-spent, time.Nanosecond)
+}
+
 type instanceLock struct{}
 
 type Instance struct {
@@ -115,36 +190,94 @@ type Instance struct {
 	host         bool
 	image        *image.Instance
 	process      *runtime.Process
+	resident     int64 // Reserved from account along with process.
 	services     InstanceServices
 	debugLog     io.WriteCloser
+	record       io.WriteCloser     // Packets of the first process.
+	replay       *runtime.Recording // Served to the first process.
+	budget       timeBudget
+	res          ResourcePolicy // Captured at launch or resumption.
+	idleTimeout  time.Duration  // Hibernation; zero means never.
+	hibernating  bool           // Suspension due to idleness was requested.
+	woken        chan struct{}  // Non-nil while hibernated with services kept.
+	pausing      bool           // Suspension for checkpoint was requested.
+	paused       bool           // Between processes due to checkpoint.
+	pendingKill  bool           // Requested while paused.
+	pendingStop  bool           // Suspension requested while paused.
+	checkpoint   *image.Instance
+	inventoried  bool        // Instance has been recorded in inventory.
+	step         *debugStep  // Temporary breakpoints are set.
+	wakeupTimer  *time.Timer // Latest scheduled wakeup.
 	stopped      chan struct{}
 }
 
 // newInstance steals instance image, process, and services.
-func newInstance(id string, acc *account, transient, host bool, image *image.Instance, buffers *snapshot.Buffers, proc *runtime.Process, services InstanceServices, timeResolution time.Duration, tags []string, debugLog io.WriteCloser) *Instance {
+func newInstance(id string, acc *account, transient, host bool, image *image.Instance, buffers *snapshot.Buffers, proc *runtime.Process, resident int64, services InstanceServices, timeResolution time.Duration, budget timeBudget, res *ResourcePolicy, idleTimeout time.Duration, checkpointInterval *durationpb.Duration, crashSnapshot bool, tags []string, debugLog io.WriteCloser) *Instance {
 	return &Instance{
 		id:  id,
 		acc: acc,
 		model: &pb.Instance{
-			Transient:      transient,
-			Status:         new(api.Status),
-			Buffers:        buffers,
-			TimeResolution: durationpb.New(timeResolution),
-			Tags:           tags,
+			Transient:          transient,
+			Status:             new(api.Status),
+			Buffers:            buffers,
+			TimeResolution:     durationpb.New(timeResolution),
+			Tags:               tags,
+			CheckpointInterval: checkpointInterval,
+			CrashSnapshot:      crashSnapshot,
+			Usage:              new(api.InstanceUsage),
+			Created:            timestamppb.Now(),
 		},
-		host:     host,
-		image:    image,
-		process:  proc,
-		services: services,
-		debugLog: debugLog,
-		stopped:  make(chan struct{}),
+		host:        host,
+		image:       image,
+		process:     proc,
+		resident:    resident,
+		services:    services,
+		debugLog:    debugLog,
+		budget:      budget,
+		res:         *res,
+		idleTimeout: idleTimeout,
+		stopped:     make(chan struct{}),
 	}
 }
 
+// restoreInstance steals the image.  Status is derived from the image unless
+// the inventory record has it.
+func restoreInstance(id string, acc *account, image *image.Instance, model *pb.Instance) *Instance {
+	model.Exists = true
+	model.Transient = false
+
+	inst := &Instance{
+		id:          id,
+		acc:         acc,
+		model:       model,
+		image:       image,
+		inventoried: true,
+		stopped:     make(chan struct{}),
+	}
+	close(inst.stopped)
+
+	if model.Status == nil {
+		model.Status = new(api.Status)
+		lock.GuardTag(&inst.mu, inst.setStoppedStatus)
+	}
+	if model.Stopped == nil {
+		model.Stopped = timestamppb.Now()
+	}
+
+	return inst
+}
+
 func (inst *Instance) ID() string {
 	return inst.id
 }
 
+// notify watchers about a change.
+func (inst *Instance) notify() {
+	if inst.acc != nil {
+		inst.acc.watchers.notify(inst)
+	}
+}
+
 func (inst *Instance) store(lock instanceLock, prog *program) error {
 	return inst.image.Store(instanceStorageKey(inst.acc.ID, inst.id), prog.id, prog.image)
 }
@@ -158,68 +291,164 @@ func (inst *Instance) startOrAnnihilate(prog *program) (drive bool, err error) {
 			return false, err
 		}
 
-		trapID := inst.image.Trap()
+		inst.setStoppedStatus(lock)
+		inst.model.Exists = true
+		inst.model.Stopped = timestamppb.Now()
+		close(inst.stopped)
+		inst.notify()
+		return false, nil
+	}
 
-		if inst.image.Final() {
-			if trapID != trap.Exit {
-				inst.model.Status.State = api.StateKilled
-				if trapID != trap.Killed {
-					inst.model.Status.Cause = api.Cause(trapID)
-				}
-			} else {
-				inst.model.Status.State = api.StateTerminated
-				inst.model.Status.Result = inst.image.Result()
+	if err := inst.start(lock, prog); err != nil {
+		inst.stop(lock)
+		inst.image.Close()
+		inst.image = nil
+		return false, err
+	}
+
+	inst.model.Status.State = api.StateRunning
+	inst.model.Exists = true
+	inst.model.Resumed = timestamppb.Now()
+	inst.notify()
+	return true, nil
+}
+
+// setStoppedStatus according to the image.
+func (inst *Instance) setStoppedStatus(lock instanceLock) {
+	setImageStatus(inst.model.Status, inst.image)
+}
+
+func setImageStatus(status *api.Status, instImage *image.Instance) {
+	trapID := instImage.Trap()
+
+	if instImage.Final() {
+		if trapID != trap.Exit {
+			status.State = api.StateKilled
+			if trapID != trap.Killed {
+				status.Cause = api.Cause(trapID)
 			}
 		} else {
-			if trapID != trap.Exit {
-				inst.model.Status.State, inst.model.Status.Cause = trapStatus(trapID)
-			} else if inst.image.EntryAddr() == 0 {
-				inst.model.Status.State = api.StateHalted
-				inst.model.Status.Result = inst.image.Result()
-			} else {
-				inst.model.Status.State = api.StateSuspended
-			}
+			status.State = api.StateTerminated
+			status.Result = instImage.Result()
 		}
+	} else {
+		if trapID != trap.Exit {
+			status.State, status.Cause = trapStatus(trapID)
+		} else if instImage.EntryAddr() == 0 {
+			status.State = api.StateHalted
+			status.Result = instImage.Result()
+		} else {
+			status.State = api.StateSuspended
+		}
+	}
+}
 
-		inst.model.Exists = true
-		close(inst.stopped)
-		return false, nil
+// residentSize of a process which would run the instance.
+func (inst *Instance) residentSize(prog *program) int64 {
+	lock := inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	if inst.image == nil {
+		return 0
 	}
+	return residentSize(inst.progImage(lock, prog), inst.image)
+}
 
-	progImage := prog.image
+// progImage is the alternative program image if set.
+func (inst *Instance) progImage(lock instanceLock, prog *program) *image.Program {
 	if inst.altProgImage != nil {
-		progImage = inst.altProgImage
+		return inst.altProgImage
 	}
+	return prog.image
+}
+
+func (inst *Instance) start(lock instanceLock, prog *program) error {
+	progImage := inst.progImage(lock, prog)
 
 	policy := runtime.ProcessPolicy{
 		TimeResolution: inst.model.TimeResolution.AsDuration(),
 		DebugLog:       inst.debugLog,
+		CPUTimeLimit:   inst.budget.cpuTime,
+		Record:         inst.record,
+		Replay:         inst.replay,
 	}
+	inst.replay = nil
 
-	if err := inst.process.Start(progImage, inst.image, policy); err != nil {
-		inst.stop(lock)
-		inst.image.Close()
-		inst.image = nil
-		return false, err
+	return inst.process.Start(progImage, inst.image, policy)
+}
+
+// endRecording when the first process has finished.
+func (inst *Instance) endRecording(lock instanceLock) {
+	if inst.record != nil {
+		inst.record.Close()
+		inst.record = nil
 	}
+}
 
-	inst.model.Status.State = api.StateRunning
-	inst.model.Exists = true
-	return true, nil
+// accumulateUsage of the process which has finished serving.
+func (inst *Instance) accumulateUsage(lock instanceLock) {
+	if inst.process == nil {
+		return
+	}
+
+	u := inst.model.Usage
+	if u == nil {
+		u = new(api.InstanceUsage)
+		inst.model.Usage = u
+	}
+
+	c := inst.process.IOCounters()
+	u.CpuTime = durationpb.New(u.CpuTime.AsDuration() + inst.process.CPUTime())
+	u.PacketsSent += c.PacketsSent
+	u.BytesSent += c.BytesSent
+	u.PacketsReceived += c.PacketsReceived
+	u.BytesReceived += c.BytesReceived
+}
+
+// usage includes the I/O of the current process.
+func (inst *Instance) usage(lock instanceLock) *api.InstanceUsage {
+	u := proto.CloneOf(inst.model.Usage)
+	if u == nil {
+		u = new(api.InstanceUsage)
+	}
+
+	if inst.image != nil {
+		u.MemorySize = uint32(inst.image.MemorySize())
+		u.MaxMemorySize = uint32(inst.image.MaxMemorySize())
+		u.StackUsage = uint32(inst.image.StackUsage())
+	}
+
+	if inst.process != nil && inst.model.Status.State == api.StateRunning && !inst.paused {
+		c := inst.process.IOCounters()
+		u.PacketsSent += c.PacketsSent
+		u.BytesSent += c.BytesSent
+		u.PacketsReceived += c.PacketsReceived
+		u.BytesReceived += c.BytesReceived
+	}
+
+	return u
 }
 
 func (inst *Instance) stop(lock instanceLock) {
 	close(inst.stopped)
 
 	inst.process.Close()
+	if inst.acc != nil {
+		inst.acc.releaseProc(inst.resident)
+	}
+	inst.resident = 0
 
-	inst.services.Close()
-	inst.services = nil
+	if inst.woken == nil {
+		inst.services.Close()
+		inst.services = nil
+	}
 
 	if inst.debugLog != nil {
 		inst.debugLog.Close()
 		inst.debugLog = nil
 	}
+
+	inst.endRecording(lock)
 }
 
 func (inst *Instance) Status() *api.Status {
@@ -231,7 +460,7 @@ func (inst *Instance) Status() *api.Status {
 
 // info may return nil.
 func (inst *Instance) info(module string) *api.InstanceInfo {
-	inst.mu.Lock()
+	lock := inst.mu.Lock()
 	defer inst.mu.Unlock()
 
 	if !inst.model.Exists {
@@ -239,11 +468,15 @@ func (inst *Instance) info(module string) *api.InstanceInfo {
 	}
 
 	info := &api.InstanceInfo{
-		Instance:  inst.id,
-		Module:    module,
-		Status:    cloneStatus(inst.model.Status),
-		Transient: inst.model.Transient,
-		Tags:      inst.model.Tags,
+		Instance:   inst.id,
+		Module:     module,
+		Status:     cloneStatus(inst.model.Status),
+		Transient:  inst.model.Transient,
+		Tags:       inst.model.Tags,
+		Hibernated: inst.model.Hibernated,
+		Usage:      inst.usage(lock),
+		Created:    inst.model.Created,
+		Resumed:    inst.model.Resumed,
 	}
 	if inst.image != nil {
 		info.Debugging = len(inst.image.Breakpoints()) > 0
@@ -280,7 +513,12 @@ func (inst *Instance) kill() {
 		panic("host instance killing not implemented") // XXX
 	}
 
-	proc := inst.getProcess()
+	proc := lock.GuardTagged(&inst.mu, func(instanceLock) *runtime.Process {
+		if inst.paused {
+			inst.pendingKill = true
+		}
+		return inst.process
+	})
 	if proc == nil {
 		return
 	}
@@ -303,6 +541,11 @@ func (inst *Instance) suspend(setNonTransient bool) {
 		if setNonTransient && inst.model.Status.State == api.StateRunning {
 			inst.model.Transient = false
 		}
+		inst.hibernating = false
+		inst.pausing = false
+		if inst.paused {
+			inst.pendingStop = true
+		}
 		return inst.process
 	})
 	if proc == nil {
@@ -331,7 +574,11 @@ func (inst *Instance) mustCheckResumeWithLock(lock instanceLock, function string
 		z.Panic(notfound.ErrInstance)
 	}
 
-	switch inst.model.Status.State {
+	mustCheckResumeStatus(inst.model.Status, function)
+}
+
+func mustCheckResumeStatus(status *api.Status, function string) {
+	switch status.State {
 	case api.StateSuspended:
 		if function != "" {
 			z.Panic(failrequest.Error(event.FailInstanceStatus, "function specified for suspended instance"))
@@ -348,10 +595,10 @@ func (inst *Instance) mustCheckResumeWithLock(lock instanceLock, function string
 }
 
 // mustResume steals proc, services and debugLog.
-func (inst *Instance) mustResume(function string, proc *runtime.Process, services InstanceServices, timeResolution time.Duration, debugLog io.WriteCloser) {
+func (inst *Instance) mustResume(function string, proc *runtime.Process, resident int64, services InstanceServices, timeResolution time.Duration, budget timeBudget, res *ResourcePolicy, idleTimeout time.Duration, checkpointInterval *durationpb.Duration, debugLog io.WriteCloser) {
 	var ok bool
 	defer func() {
-		if !ok {
+		if !ok && debugLog != nil {
 			debugLog.Close()
 		}
 	}()
@@ -362,16 +609,299 @@ func (inst *Instance) mustResume(function string, proc *runtime.Process, service
 	// Check again in case of a race condition.
 	inst.mustCheckResumeWithLock(lock, function)
 
+	inst.endHibernation(lock)
+
 	inst.model.Status = &api.Status{State: api.StateRunning}
+	inst.model.Resumed = timestamppb.Now()
+	inst.model.Wakeup = nil
 	inst.process = proc
+	inst.resident = resident
 	inst.services = services
 	inst.model.TimeResolution = durationpb.New(timeResolution)
 	inst.debugLog = debugLog
+	inst.budget = budget
+	inst.res = *res
+	inst.idleTimeout = idleTimeout
+	if checkpointInterval != nil {
+		inst.model.CheckpointInterval = checkpointInterval
+	}
 	inst.stopped = make(chan struct{})
+	inst.notify()
 
 	ok = true
 }
 
+// wake steals proc if the instance is hibernated.  The retained services are
+// reused.
+func (inst *Instance) wake(proc *runtime.Process, resident int64) bool {
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	if inst.woken == nil || !inst.model.Exists || inst.model.Status.State != api.StateSuspended {
+		return false
+	}
+
+	close(inst.woken)
+	inst.woken = nil
+
+	inst.model.Hibernated = false
+	inst.model.Status = &api.Status{State: api.StateRunning}
+	inst.model.Resumed = timestamppb.Now()
+	inst.model.Wakeup = nil
+	inst.process = proc
+	inst.resident = resident
+	inst.stopped = make(chan struct{})
+	inst.notify()
+	return true
+}
+
+// hibernation returns the resource policy if the instance is hibernated.  The
+// services are not retained if the instance was hibernated before the server
+// was restarted.
+func (inst *Instance) hibernation() (res ResourcePolicy, hibernated, retained bool) {
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	return inst.res, inst.model.Hibernated, inst.woken != nil
+}
+
+// scheduledWakeup returns the time when a suspended instance should be
+// resumed, if its services requested it.
+func (inst *Instance) scheduledWakeup() (t time.Time, ok bool) {
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	if !inst.model.Exists || inst.model.Status.State != api.StateSuspended || inst.model.Wakeup == nil {
+		return time.Time{}, false
+	}
+	return inst.model.Wakeup.AsTime(), true
+}
+
+// setWakeupTimer stops the previous timer, if any.
+func (inst *Instance) setWakeupTimer(t *time.Timer) {
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	if inst.wakeupTimer != nil {
+		inst.wakeupTimer.Stop()
+	}
+	inst.wakeupTimer = t
+}
+
+// serviceWaker returns the wake channel of the retained services if the
+// instance is hibernated and the services implement InstanceWaker.  The woken
+// channel is closed when hibernation ends.
+func (inst *Instance) serviceWaker() (wake, woken <-chan struct{}) {
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	if inst.woken == nil {
+		return nil, nil
+	}
+	w, ok := inst.services.(InstanceWaker)
+	if !ok {
+		return nil, nil
+	}
+	return w.Wake(), inst.woken
+}
+
+// releaseHibernation closes the retained services.
+func (inst *Instance) releaseHibernation() {
+	lock := inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	inst.endHibernation(lock)
+}
+
+func (inst *Instance) endHibernation(lock instanceLock) {
+	inst.model.Hibernated = false
+
+	if inst.woken == nil {
+		return
+	}
+
+	close(inst.woken)
+	inst.woken = nil
+
+	inst.services.Close()
+	inst.services = nil
+}
+
+// requestHibernation unless the instance is transient (it would be deleted).
+func (inst *Instance) requestHibernation() bool {
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	if inst.model.Transient || inst.model.Status.State != api.StateRunning || inst.pausing {
+		return false
+	}
+
+	inst.hibernating = true
+	return true
+}
+
+// watchIdle requests hibernation and suspends the process when it has had no
+// packet traffic for the timeout duration.
+func (inst *Instance) watchIdle(proc *runtime.Process, timeout time.Duration) (stop func()) {
+	done := make(chan struct{})
+
+	go func() {
+		t := time.NewTimer(timeout)
+		defer t.Stop()
+
+		for {
+			select {
+			case <-t.C:
+			case <-done:
+				return
+			}
+
+			if idle := time.Since(proc.LastActivity()); idle < timeout {
+				t.Reset(timeout - idle)
+				continue
+			}
+
+			if inst.requestHibernation() {
+				proc.Suspend()
+				return
+			}
+			t.Reset(timeout)
+		}
+	}()
+
+	return func() { close(done) }
+}
+
+// requestCheckpoint unless the instance is transient (it would be deleted).
+func (inst *Instance) requestCheckpoint() bool {
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	if inst.model.Transient || inst.model.Status.State != api.StateRunning || inst.hibernating {
+		return false
+	}
+
+	inst.pausing = true
+	return true
+}
+
+// storeCheckpoint copies the image of a paused instance.  The returned
+// inventory record describes the checkpoint.
+func (inst *Instance) storeCheckpoint(prog *program) (record *pb.Instance, inventoried bool, err error) {
+	lock := inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	inst.dropCheckpoint(lock)
+
+	clone, err := inst.image.Clone(prog.image)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if err := clone.Store(checkpointStorageKey(inst.acc.ID, inst.id), prog.id, prog.image); err != nil {
+		clone.Close()
+		return nil, false, err
+	}
+
+	inst.checkpoint = clone
+	inventoried = inst.inventoried
+	inst.inventoried = true
+	return inst.inventoryRecord(lock, prog.id, &api.Status{State: api.StateSuspended}), inventoried, nil
+}
+
+// dropCheckpoint after the instance has been stored or deleted.
+func (inst *Instance) dropCheckpoint(lock instanceLock) {
+	if inst.checkpoint == nil {
+		return
+	}
+
+	inst.checkpoint.Unstore()
+	inst.checkpoint.Close()
+	inst.checkpoint = nil
+}
+
+// stoppedInventoryRecord returns a record unless the instance is transient or
+// nonexistent.  The instance is considered to be recorded in inventory after
+// this; the returned flag tells if it already was.
+func (inst *Instance) stoppedInventoryRecord(module string) (record *pb.Instance, inventoried bool) {
+	lock := inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	if inst.model.Transient || !inst.model.Exists {
+		return nil, false
+	}
+
+	inventoried = inst.inventoried
+	inst.inventoried = true
+	return inst.inventoryRecord(lock, module, cloneStatus(inst.model.Status)), inventoried
+}
+
+func (inst *Instance) inventoryRecord(lock instanceLock, module string, status *api.Status) *pb.Instance {
+	return &pb.Instance{
+		Exists:             true,
+		Status:             status,
+		Buffers:            inst.model.Buffers,
+		TimeResolution:     inst.model.TimeResolution,
+		Tags:               inst.model.Tags,
+		Module:             module,
+		CheckpointInterval: inst.model.CheckpointInterval,
+		CrashSnapshot:      inst.model.CrashSnapshot,
+		SnapshotBase:       inst.model.SnapshotBase,
+		Usage:              proto.CloneOf(inst.model.Usage),
+		Created:            inst.model.Created,
+		Resumed:            inst.model.Resumed,
+		Stopped:            inst.model.Stopped,
+		Wakeup:             inst.model.Wakeup,
+		Hibernated:         inst.model.Hibernated,
+	}
+}
+
+// unpause steals proc and continues running a paused instance.  The instance
+// is stopped if it cannot be restarted.
+func (inst *Instance) unpause(prog *program, proc *runtime.Process) error {
+	lock := inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	inst.process = proc
+
+	if err := inst.start(lock, prog); err != nil {
+		inst.endPause(lock, &api.Status{
+			State: api.StateKilled,
+			Cause: api.CauseInternal,
+			Error: api.PublicErrorString(err, ""),
+		})
+		return err
+	}
+
+	inst.paused = false
+	if inst.pendingKill {
+		proc.Kill()
+	} else if inst.pendingStop {
+		proc.Suspend()
+	}
+	inst.pendingKill = false
+	inst.pendingStop = false
+	return nil
+}
+
+// stopPaused instance which cannot be restarted.
+func (inst *Instance) stopPaused() {
+	lock := inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	inst.endPause(lock, &api.Status{State: api.StateSuspended})
+}
+
+func (inst *Instance) endPause(lock instanceLock, status *api.Status) {
+	inst.paused = false
+	inst.pendingKill = false
+	inst.pendingStop = false
+
+	inst.model.Status = status
+	inst.stop(lock)
+	inst.notify()
+}
+
 // Connect to a running instance.  Disconnection happens when context is
 // canceled, the instance stops running, or the program closes the connection.
 func (inst *Instance) Connect(ctx Context, r io.Reader, w io.WriteCloser) error {
@@ -402,7 +932,8 @@ func (inst *Instance) connect(ctx Context) func(Context, io.Reader, io.WriteClos
 	return s.Connect(ctx)
 }
 
-func (inst *Instance) mustSnapshot(prog *program) (*image.Program, *snapshot.Buffers) {
+// mustSnapshot creates an incremental snapshot if base program is specified.
+func (inst *Instance) mustSnapshot(prog, base *program) (*image.Program, *snapshot.Buffers) {
 	if inst.host {
 		z.Panic(badprogram.Error("host instance cannot be snapshotted"))
 	}
@@ -417,14 +948,176 @@ func (inst *Instance) mustSnapshot(prog *program) (*image.Program, *snapshot.Buf
 		z.Panic(failrequest.Error(event.FailInstanceStatus, "instance must not be running"))
 	}
 
-	buffers := inst.model.Buffers
-	progImage := must(image.Snapshot(prog.image, inst.image, buffers, inst.model.Status.State == api.StateSuspended))
+	var (
+		buffers   = inst.model.Buffers
+		suspended = inst.model.Status.State == api.StateSuspended
+		progImage *image.Program
+	)
+	if base != nil {
+		progImage = must(image.IncrementalSnapshot(prog.image, inst.image, buffers, suspended, base.image))
+	} else {
+		progImage = must(image.Snapshot(prog.image, inst.image, buffers, suspended))
+	}
 
 	return progImage, buffers
 }
 
-// mustAnnihilate fails unless instance is stopped.
-func (inst *Instance) mustAnnihilate() {
+// mustCheckRollback checks that the stopped instance can be rolled back to the
+// image, and resumed after that.
+func (inst *Instance) mustCheckRollback(newImage *image.Instance, function string) {
+	lock := inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	inst.mustCheckRollbackWithLock(lock, newImage, function)
+}
+
+func (inst *Instance) mustCheckRollbackWithLock(lock instanceLock, newImage *image.Instance, function string) {
+	if inst.host {
+		z.Panic(badprogram.Error("host instance cannot be rolled back"))
+	}
+	if !inst.model.Exists {
+		z.Panic(notfound.ErrInstance)
+	}
+	if inst.model.Status.State == api.StateRunning {
+		z.Panic(failrequest.Error(event.FailInstanceStatus, "instance must not be running"))
+	}
+
+	status := new(api.Status)
+	setImageStatus(status, newImage)
+	mustCheckResumeStatus(status, function)
+}
+
+// mustRollback replaces the image and buffers of a stopped instance.  The
+// instance image is stolen on success.
+func (inst *Instance) mustRollback(oldProg, newProg *program, newImage *image.Instance, function string) {
+	lock := inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	// Check again in case of a race condition.
+	inst.mustCheckRollbackWithLock(lock, newImage, function)
+
+	if !inst.model.Transient {
+		z.Check(inst.image.Unstore())
+
+		if err := newImage.Store(instanceStorageKey(inst.acc.ID, inst.id), newProg.id, newProg.image); err != nil {
+			if err := inst.store(lock, oldProg); err != nil {
+				slog.Error("server: instance image lost during rollback", "instance", inst.id, "error", err)
+			}
+			z.Panic(err)
+		}
+	}
+
+	inst.endHibernation(lock)
+	inst.dropCheckpoint(lock)
+
+	if inst.altProgImage != nil {
+		inst.altProgImage.Close()
+		inst.altProgImage = nil
+	}
+
+	inst.image.Close()
+	inst.image = newImage
+	inst.model.Buffers = newProg.buffers
+	inst.model.Status = new(api.Status)
+	inst.setStoppedStatus(lock)
+	inst.model.Stopped = timestamppb.Now()
+	inst.notify()
+}
+
+// snapshotBase returns the module of the latest snapshot, or empty string.
+func (inst *Instance) snapshotBase() string {
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	return inst.model.SnapshotBase
+}
+
+func (inst *Instance) setSnapshotBase(module string) {
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	inst.model.SnapshotBase = module
+}
+
+// crashSnapshot detaches the image of a trapped instance.  The image of a
+// transient instance is taken over, as it would be discarded anyway.
+func (inst *Instance) crashSnapshot(ctx Context, lock instanceLock, prog *program, cause api.Cause, config *Config) *crashSnapshot {
+	var instImage *image.Instance
+
+	if inst.model.Transient {
+		instImage = inst.image
+		inst.image = nil
+	} else {
+		var err error
+		instImage, err = inst.image.Clone(prog.image)
+		if err != nil {
+			config.eventFail(ctx, event.TypeFailInternal, internalFail(prog.id, "", inst.id, "crash snapshot", err), err)
+			return nil
+		}
+	}
+
+	return &crashSnapshot{
+		image:   instImage,
+		buffers: inst.model.Buffers,
+		cause:   cause,
+		res:     inst.res,
+	}
+}
+
+// mustClone copies the image of a suspended or halted instance.  Entry
+// function is set if specified; it is required for running a halted instance.
+func (inst *Instance) mustClone(prog *program, function string, suspend bool) (*image.Instance, *snapshot.Buffers) {
+	if inst.host {
+		z.Panic(badprogram.Error("host instance cannot be cloned"))
+	}
+
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	if !inst.model.Exists {
+		z.Panic(notfound.ErrInstance)
+	}
+
+	switch inst.model.Status.State {
+	case api.StateSuspended:
+		if function != "" {
+			z.Panic(failrequest.Error(event.FailInstanceStatus, "function specified for suspended instance"))
+		}
+
+	case api.StateHalted:
+		if function == "" && !suspend {
+			z.Panic(failrequest.Error(event.FailInstanceStatus, "function must be specified when running clone of halted instance"))
+		}
+
+	default:
+		z.Panic(failrequest.Error(event.FailInstanceStatus, "instance must be suspended or halted"))
+	}
+
+	// Suspended stack may refer to the alternative program text.
+	if inst.altProgImage != nil || len(inst.image.Breakpoints()) > 0 {
+		z.Panic(failrequest.Error(event.FailInstanceDebugState, "instance is being debugged"))
+	}
+
+	funcIndex := -1
+	if function != "" {
+		funcIndex = must(prog.image.ResolveEntryFunc(function, false))
+	}
+
+	clone := must(inst.image.Clone(prog.image))
+
+	if funcIndex >= 0 {
+		if err := clone.SetEntryFunc(prog.image, funcIndex); err != nil {
+			clone.Close()
+			z.Panic(err)
+		}
+	}
+
+	return clone, inst.model.Buffers
+}
+
+// mustAnnihilate fails unless instance is stopped.  It reports if the
+// instance had been recorded in inventory.
+func (inst *Instance) mustAnnihilate() (inventoried bool) {
 	lock := inst.mu.Lock()
 	defer inst.mu.Unlock()
 
@@ -436,6 +1129,39 @@ func (inst *Instance) mustAnnihilate() {
 	}
 
 	inst.annihilate(lock)
+	inst.notify()
+	return inst.inventoried
+}
+
+// collect annihilates the instance if it has been stopped for longer than the
+// time to live of its state.  Nothing is changed in dry-run mode.
+func (inst *Instance) collect(config *CollectConfig, now time.Time) (status *api.Status, stopped time.Time, inventoried, expired bool) {
+	lock := inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	if !inst.expired(lock, config, now) {
+		return
+	}
+
+	status = cloneStatus(inst.model.Status)
+	stopped = inst.model.Stopped.AsTime()
+	inventoried = inst.inventoried
+	expired = true
+
+	if !config.DryRun {
+		inst.annihilate(lock)
+		inst.notify()
+	}
+	return
+}
+
+func (inst *Instance) expired(lock instanceLock, config *CollectConfig, now time.Time) bool {
+	if !inst.model.Exists || inst.host || inst.model.Stopped == nil {
+		return false
+	}
+
+	ttl := config.ttl(inst.model.Status.State)
+	return ttl > 0 && now.Sub(inst.model.Stopped.AsTime()) >= ttl
 }
 
 func (inst *Instance) annihilate(lock instanceLock) {
@@ -445,18 +1171,36 @@ func (inst *Instance) annihilate(lock instanceLock) {
 		panic("host instance annihilation not implemented") // XXX
 	}
 
-	inst.image.Unstore()
-	inst.image.Close()
-	inst.image = nil
+	inst.endHibernation(lock)
+	inst.dropCheckpoint(lock)
+	inst.endRecording(lock)
+
+	if inst.wakeupTimer != nil {
+		inst.wakeupTimer.Stop()
+		inst.wakeupTimer = nil
+	}
+
+	if inst.image != nil { // Taken over by crash snapshot.
+		inst.image.Unstore()
+		inst.image.Close()
+		inst.image = nil
+	}
 }
 
-func (inst *Instance) drive(ctx Context, prog *program, module, function string, config *Config) (nonexistent bool) {
+// drive returns with paused set if the instance was suspended for a checkpoint.
+// Its process has been closed, but other resources are retained and the status
+// is still running.
+func (inst *Instance) drive(ctx Context, prog *program, module, function string, config *Config) (nonexistent, paused bool, crash *crashSnapshot) {
 	trapID := trap.InternalError
 	res := &api.Status{
 		State: api.StateKilled,
 		Cause: api.CauseInternal,
 	}
 
+	var wakeup time.Time // Earliest time requested by services during suspension.
+
+	started := time.Now()
+
 	cleanupFunc := func(lock instanceLock) {
 		if inst.image != nil {
 			if res.State >= api.StateTerminated {
@@ -464,8 +1208,36 @@ func (inst *Instance) drive(ctx Context, prog *program, module, function string,
 			}
 			inst.image.SetTrap(trapID)
 			inst.image.SetResult(res.Result)
+
+			if inst.model.CrashSnapshot && res.State == api.StateKilled && crashCause(res.Cause) {
+				crash = inst.crashSnapshot(ctx, lock, prog, res.Cause, config)
+			}
+		}
+
+		inst.accumulateUsage(lock)
+
+		if inst.pausing && trapID == trap.Suspended && res.State == api.StateSuspended && !inst.model.Transient {
+			inst.pausing = false
+			inst.paused = true
+			inst.budget.consume(time.Since(started), inst.process.CPUTime())
+			inst.process.Close()
+			inst.endRecording(lock)
+			paused = true
+			return
 		}
+		inst.pausing = false
+
 		inst.model.Status = res
+		inst.model.Stopped = timestamppb.Now()
+		inst.model.Wakeup = nil
+		if res.State == api.StateSuspended && !wakeup.IsZero() && !inst.model.Transient {
+			inst.model.Wakeup = timestamppb.New(wakeup)
+		}
+		if inst.hibernating && trapID == trap.Suspended && !inst.model.Transient {
+			inst.model.Hibernated = true
+			inst.woken = make(chan struct{})
+		}
+		inst.hibernating = false
 		inst.stop(lock)
 
 		config.eventInstance(ctx, event.TypeInstanceStop, &event.Instance{
@@ -481,6 +1253,8 @@ func (inst *Instance) drive(ctx Context, prog *program, module, function string,
 				Instance: inst.id,
 			}, nil)
 		}
+
+		inst.notify()
 	}
 	defer func() {
 		if cleanupFunc != nil {
@@ -491,11 +1265,49 @@ func (inst *Instance) drive(ctx Context, prog *program, module, function string,
 	}()
 
 	var (
-		result runtime.Result
-		err    error
+		result   runtime.Result
+		err      error
+		timedOut atomic.Bool
 	)
 
-	result, trapID, inst.model.Buffers, err = inst.process.Serve(instanceServingContext(ctx, inst.id), inst.services, inst.model.Buffers)
+	if d := inst.budget.wallTime; d > 0 {
+		proc := inst.process
+		kill := inst.budget.kill
+
+		t := time.AfterFunc(d, func() {
+			timedOut.Store(true)
+			if kill {
+				proc.Kill()
+			} else {
+				proc.Suspend()
+			}
+		})
+		defer t.Stop()
+	}
+
+	if d := inst.idleTimeout; d > 0 && !inst.host {
+		stop := inst.watchIdle(inst.process, d)
+		defer stop()
+	}
+
+	if d := inst.model.CheckpointInterval.AsDuration(); d > 0 && !inst.host {
+		proc := inst.process
+
+		t := time.AfterFunc(d, func() {
+			if inst.requestCheckpoint() {
+				proc.Suspend()
+			}
+		})
+		defer t.Stop()
+	}
+
+	servingCtx := service.ContextWithWakeup(instanceServingContext(ctx, inst.id, module), func(t time.Time) {
+		if wakeup.IsZero() || t.Before(wakeup) {
+			wakeup = t
+		}
+	})
+
+	result, trapID, inst.model.Buffers, err = inst.process.Serve(servingCtx, inst.services, inst.model.Buffers)
 	if err != nil {
 		if inst.host {
 			slog.InfoContext(ctx, "host instance disconnected", "err", err)
@@ -543,9 +1355,14 @@ func (inst *Instance) drive(ctx Context, prog *program, module, function string,
 				config.eventFail(ctx, event.TypeFailInternal, internalFail(module, function, inst.id, "image storage", err), err)
 				return
 			}
+			inst.dropCheckpoint(lock)
 		}
 	}
 
+	if timedOut.Load() && (trapID == trap.Suspended || trapID == trap.Killed) {
+		trapID = trap.Timeout
+	}
+
 	if trapID == trap.Exit {
 		if inst.model.Transient || result.Terminated() {
 			res.State = api.StateTerminated
@@ -556,6 +1373,9 @@ func (inst *Instance) drive(ctx Context, prog *program, module, function string,
 		res.Result = int32(result.Value())
 	} else {
 		res.State, res.Cause = trapStatus(trapID)
+		if trapID == trap.Timeout && inst.budget.kill {
+			res.State = api.StateKilled
+		}
 	}
 
 	return
@@ -584,18 +1404,51 @@ func (inst *Instance) update(update *api.InstanceUpdate) (modified bool) {
 		update.Tags = nil
 	}
 
+	if modified {
+		inst.notify()
+	}
+
 	return
 }
 
+// maxDebugDataSize limits memory read and write operations.
+const maxDebugDataSize = 16 << 20
+
+// debugStep is pending while the instance is running a single step.
+type debugStep struct {
+	breakpoints []uint64 // Restored when the instance stops.
+	policy      ProgramPolicy
+}
+
+// mustStacktrace reads call stack frames of a stopped instance.  The
+// instruction map must have been compiled with the breakpoints which are
+// currently set, so that the return offsets of the frames are source offsets.
+func (inst *Instance) mustStacktrace(insnMap *objectdebug.InsnMap, funcTypes []wa.FuncType, breaks []uint64) []stack.Frame {
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	if inst.model.Status.State == api.StateRunning {
+		z.Panic(failrequest.Error(event.FailInstanceStatus, "instance must be stopped"))
+	}
+	if inst.image == nil {
+		return nil
+	}
+	if !reflect.DeepEqual(inst.image.Breakpoints(), breaks) {
+		z.Panic(failrequest.Error(event.FailInstanceDebugState, "conflict"))
+	}
+
+	return must(inst.image.Stacktrace(insnMap, funcTypes))
+}
+
 func (inst *Instance) mustDebug(ctx Context, prog *program, req *api.DebugRequest) (*instanceRebuild, *api.DebugConfig, *api.DebugResponse) {
 	if inst.host {
 		z.Panic(badprogram.Error("host instance cannot be debugged"))
 	}
 
-	inst.mu.Lock()
+	lock := inst.mu.Lock()
 	defer inst.mu.Unlock()
 
-	if req.Op < api.DebugOpConfigGet || req.Op > api.DebugOpReadStack {
+	if req.Op < api.DebugOpConfigGet || req.Op > api.DebugOpReadStacktrace {
 		z.Panic(failrequest.Error(event.FailUnsupported, "unsupported debug op"))
 	}
 
@@ -603,6 +1456,20 @@ func (inst *Instance) mustDebug(ctx Context, prog *program, req *api.DebugReques
 		z.Panic(failrequest.Error(event.FailInstanceStatus, "instance must be stopped"))
 	}
 
+	switch req.Op {
+	case api.DebugOpWriteMemory, api.DebugOpWriteGlobal:
+		switch inst.model.Status.State {
+		case api.StateSuspended, api.StateHalted:
+		default:
+			z.Panic(failrequest.Error(event.FailInstanceStatus, "instance must be suspended or halted"))
+		}
+
+	case api.DebugOpStep:
+		if inst.model.Status.State != api.StateSuspended {
+			z.Panic(failrequest.Error(event.FailInstanceStatus, "instance must be suspended"))
+		}
+	}
+
 	breaks := inst.image.Breakpoints()
 	modified := false
 	var data []byte
@@ -647,10 +1514,16 @@ func (inst *Instance) mustDebug(ctx Context, prog *program, req *api.DebugReques
 		}
 
 	case api.DebugOpReadGlobals:
-		panic("TODO")
+		values := must(inst.image.Globals(prog.image))
+		data = make([]byte, len(values)*8)
+		for i, x := range values {
+			binary.LittleEndian.PutUint64(data[i*8:], x)
+		}
 
 	case api.DebugOpReadMemory:
-		panic("TODO")
+		inst.mustCheckMemoryRange(lock, req.Addr, req.Size)
+		data = make([]byte, req.Size)
+		z.Check(inst.image.ReadMemory(data, uint32(req.Addr)))
 
 	case api.DebugOpReadStack:
 		callMap := inst.altCallMap
@@ -658,6 +1531,26 @@ func (inst *Instance) mustDebug(ctx Context, prog *program, req *api.DebugReques
 			callMap = &prog.image.Map
 		}
 		data = must(inst.image.ExportStack(callMap))
+
+	case api.DebugOpWriteMemory:
+		inst.mustCheckMemoryRange(lock, req.Addr, uint64(len(req.Data)))
+		z.Check(inst.image.WriteMemory(uint32(req.Addr), req.Data))
+
+	case api.DebugOpWriteGlobal:
+		types := prog.image.GlobalTypes()
+		if req.Addr >= uint64(len(types)) {
+			z.Panic(failrequest.Error(event.FailPayloadError, "global index out of range"))
+		}
+		if !types[req.Addr].Mutable() {
+			z.Panic(failrequest.Error(event.FailPayloadError, "global is immutable"))
+		}
+		if len(req.Data) != 8 {
+			z.Panic(failrequest.Error(event.FailPayloadError, "global value must be 8 bytes"))
+		}
+		z.Check(inst.image.SetGlobal(prog.image, int(req.Addr), binary.LittleEndian.Uint64(req.Data)))
+
+	case api.DebugOpStep, api.DebugOpReadStacktrace:
+		// Performed by server after checking status here.
 	}
 
 	var (
@@ -666,38 +1559,182 @@ func (inst *Instance) mustDebug(ctx Context, prog *program, req *api.DebugReques
 	)
 
 	if modified {
-		if reflect.DeepEqual(breaks, prog.image.Breakpoints()) {
-			if inst.altProgImage != nil {
-				inst.altProgImage.Close()
-				inst.altProgImage = nil
-				inst.altCallMap = nil
-			}
+		rebuild, newConfig = inst.configureBreakpoints(lock, prog, breaks)
+	}
 
-			inst.image.SetBreakpoints(prog.image.Breakpoints())
-		} else {
-			rebuild = &instanceRebuild{
-				inst:       inst,
-				origProgID: prog.id,
-				oldConfig: &api.DebugConfig{
-					Breakpoints: inst.image.Breakpoints(),
-				},
-			}
-			newConfig = &api.DebugConfig{
-				Breakpoints: breaks,
-			}
+	res := inst.debugResponse(lock, prog.id)
+	res.Data = data
+
+	return rebuild, newConfig, res
+}
+
+func (inst *Instance) mustCheckMemoryRange(lock instanceLock, addr, size uint64) {
+	if size > maxDebugDataSize {
+		z.Panic(failrequest.Error(event.FailResourceLimit, "debug data size limit exceeded"))
+	}
+	if memSize := uint64(inst.image.MemorySize()); size > memSize || addr > memSize-size {
+		z.Panic(failrequest.Error(event.FailPayloadError, "memory range out of bounds"))
+	}
+}
+
+// configureBreakpoints returns a rebuild if a program with the breakpoints
+// must be compiled.
+func (inst *Instance) configureBreakpoints(lock instanceLock, prog *program, breaks []uint64) (*instanceRebuild, *api.DebugConfig) {
+	if reflect.DeepEqual(breaks, prog.image.Breakpoints()) {
+		if inst.altProgImage != nil {
+			inst.altProgImage.Close()
+			inst.altProgImage = nil
+			inst.altCallMap = nil
 		}
+
+		inst.image.SetBreakpoints(prog.image.Breakpoints())
+		return nil, nil
 	}
 
-	res := &api.DebugResponse{
-		Module: path.Join(api.KnownModuleSource, prog.id),
-		Status: cloneStatus(inst.model.Status),
-		Config: &api.DebugConfig{
+	rebuild := &instanceRebuild{
+		inst:       inst,
+		origProgID: prog.id,
+		oldConfig: &api.DebugConfig{
 			Breakpoints: inst.image.Breakpoints(),
 		},
-		Data: data,
 	}
+	newConfig := &api.DebugConfig{
+		Breakpoints: breaks,
+	}
+	return rebuild, newConfig
+}
 
-	return rebuild, newConfig, res
+func (inst *Instance) debugResponse(lock instanceLock, progID string) *api.DebugResponse {
+	res := &api.DebugResponse{
+		Module: path.Join(api.KnownModuleSource, progID),
+		Status: cloneStatus(inst.model.Status),
+		Config: new(api.DebugConfig),
+	}
+	if inst.image != nil {
+		res.Config.Breakpoints = inst.image.Breakpoints()
+	}
+	return res
+}
+
+// breakpoints of a stopped instance.
+func (inst *Instance) breakpoints() []uint64 {
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	if inst.image == nil {
+		return nil
+	}
+	return inst.image.Breakpoints()
+}
+
+// mustBeginStep adds breakpoints at the instructions which may be executed
+// next (see stepTargets).  The instruction map must have been compiled with
+// the breakpoints which are currently set; the code is the contents of the
+// code section.
+func (inst *Instance) mustBeginStep(prog *program, insnMap *objectdebug.InsnMap, code []byte, funcTypes []wa.FuncType, orig []uint64, policy *ProgramPolicy) (*instanceRebuild, *api.DebugConfig) {
+	lock := inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	if inst.model.Status.State != api.StateSuspended || inst.step != nil {
+		z.Panic(failrequest.Error(event.FailInstanceStatus, "instance must be suspended"))
+	}
+	if !reflect.DeepEqual(inst.image.Breakpoints(), orig) {
+		z.Panic(failrequest.Error(event.FailInstanceDebugState, "conflict"))
+	}
+
+	frames := must(inst.image.Stacktrace(insnMap, funcTypes))
+	targets := stepTargets(insnMap, code, frames)
+	if len(targets) == 0 {
+		z.Panic(failrequest.Error(event.FailInstanceDebugState, "no instruction to step to"))
+	}
+
+	breaks := dedup.SortUint64(append(append([]uint64{}, orig...), targets...))
+	if len(breaks) > snapshot.MaxBreakpoints {
+		z.Panic(failrequest.Error(event.FailResourceLimit, "too many breakpoints"))
+	}
+
+	inst.step = &debugStep{
+		breakpoints: orig,
+		policy:      *policy,
+	}
+	return inst.configureBreakpoints(lock, prog, breaks)
+}
+
+// endStep returns a rebuild which restores the breakpoints replaced by
+// mustBeginStep.
+func (inst *Instance) endStep(prog *program) (*instanceRebuild, *api.DebugConfig, *ProgramPolicy) {
+	lock := inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	step := inst.step
+	inst.step = nil
+	if step == nil || inst.image == nil {
+		return nil, nil, nil
+	}
+
+	rebuild, config := inst.configureBreakpoints(lock, prog, step.breakpoints)
+	return rebuild, config, &step.policy
+}
+
+// stepTargets finds the source offsets of the instructions which may be
+// executed after the current position of the topmost frame: the following
+// instruction, the instructions which may be targeted by branches in the
+// current function, and the return site in the calling function.  The frames
+// must have been traced using the instruction map, so that their return
+// offsets are source offsets.
+//
+// Branches target instructions which follow loop, else and end instructions.
+func stepTargets(insnMap *objectdebug.InsnMap, code []byte, frames []stack.Frame) []uint64 {
+	if len(frames) == 0 {
+		return nil
+	}
+
+	top := frames[0]
+	if top.FuncIndex < 0 || top.FuncIndex >= len(insnMap.FuncAddrs) {
+		return nil
+	}
+
+	var (
+		current = uint32(top.RetOffset)
+		begin   = insnMap.FuncAddrs[top.FuncIndex]
+		end     = uint32(math.MaxUint32)
+		offsets []uint32 // Instructions of the current function.
+	)
+
+	if top.FuncIndex+1 < len(insnMap.FuncAddrs) {
+		end = insnMap.FuncAddrs[top.FuncIndex+1]
+	}
+
+	for _, insn := range insnMap.Insns {
+		if insn.Addr >= begin && insn.Addr < end && insn.SourceOffset != 0 {
+			offsets = append(offsets, insn.SourceOffset)
+		}
+	}
+	slices.Sort(offsets)
+	offsets = slices.Compact(offsets)
+
+	var targets []uint64
+
+	if i, _ := slices.BinarySearch(offsets, current+1); i < len(offsets) {
+		targets = append(targets, uint64(offsets[i]))
+	}
+
+	for i := 0; i+1 < len(offsets); i++ {
+		if off := offsets[i]; off < uint32(len(code)) {
+			switch opcode.Opcode(code[off]) {
+			case opcode.Loop, opcode.Else, opcode.End:
+				if next := offsets[i+1]; next != current {
+					targets = append(targets, uint64(next))
+				}
+			}
+		}
+	}
+
+	if len(frames) > 1 && frames[1].RetOffset > 0 {
+		targets = append(targets, uint64(frames[1].RetOffset))
+	}
+
+	return dedup.SortUint64(targets)
 }
 
 type instanceRebuild struct {
@@ -710,7 +1747,7 @@ func (rebuild *instanceRebuild) apply(progImage *image.Program, newConfig *api.D
 	inst := rebuild.inst
 	oldConfig := rebuild.oldConfig
 
-	inst.mu.Lock()
+	lock := inst.mu.Lock()
 	defer inst.mu.Unlock()
 
 	if reflect.DeepEqual(inst.image.Breakpoints(), oldConfig.Breakpoints) {
@@ -724,13 +1761,7 @@ func (rebuild *instanceRebuild) apply(progImage *image.Program, newConfig *api.D
 		ok = true
 	}
 
-	res = &api.DebugResponse{
-		Module: path.Join(api.KnownModuleSource, rebuild.origProgID),
-		Status: cloneStatus(inst.model.Status),
-		Config: &api.DebugConfig{
-			Breakpoints: inst.image.Breakpoints(),
-		},
-	}
+	res = inst.debugResponse(lock, rebuild.origProgID)
 	return res, ok
 }
 
@@ -0,0 +1,163 @@
+// Copyright (c) 2026 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"gate.computer/gate/server/api"
+	pb "gate.computer/internal/pb/server"
+	internal "gate.computer/internal/principal"
+	"gate.computer/wag/object"
+	objectdebug "gate.computer/wag/object/debug"
+	"gate.computer/wag/object/stack"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestTimeBudgetConsume(t *testing.T) {
+	b := timeBudget{wallTime: time.Second, cpuTime: 0}
+
+	b.consume(300*time.Millisecond, 100*time.Millisecond)
+	if b.wallTime != 700*time.Millisecond || b.cpuTime != 0 {
+		t.Errorf("budget: %v", b)
+	}
+
+	b.consume(time.Second, time.Second)
+	if b.wallTime <= 0 || b.wallTime > time.Millisecond || b.cpuTime != 0 {
+		t.Errorf("exhausted budget: %v", b)
+	}
+}
+
+func TestCheckpointStorageKey(t *testing.T) {
+	const id = "5f3a6b0e-4c1d-4a8e-9f0b-2d7c6e1a3b4c"
+
+	pri, instID, checkpoint := mustParseInstanceStorageKey(instanceStorageKey(internal.LocalID, id))
+	if pri != internal.LocalID || instID != id || checkpoint {
+		t.Errorf("instance key: %v %q %v", pri, instID, checkpoint)
+	}
+
+	pri, instID, checkpoint = mustParseInstanceStorageKey(checkpointStorageKey(internal.LocalID, id))
+	if pri != internal.LocalID || instID != id || !checkpoint {
+		t.Errorf("checkpoint key: %v %q %v", pri, instID, checkpoint)
+	}
+}
+
+func TestRestoreInstanceStatus(t *testing.T) {
+	inst := restoreInstance("test", nil, nil, &pb.Instance{
+		Transient: true,
+		Status:    &api.Status{State: api.StateSuspended},
+		Tags:      []string{"a"},
+	})
+
+	if inst.Wait(t.Context()).State != api.StateSuspended {
+		t.Error("restored instance is not suspended")
+	}
+
+	info := inst.info("module")
+	if info == nil || info.Transient || len(info.Tags) != 1 {
+		t.Errorf("info: %v", info)
+	}
+}
+
+func TestInstanceScheduledWakeup(t *testing.T) {
+	wakeup := time.Now().Add(time.Hour).Round(0)
+
+	inst := restoreInstance("test", nil, nil, &pb.Instance{
+		Status: &api.Status{State: api.StateSuspended},
+		Wakeup: timestamppb.New(wakeup),
+	})
+
+	if at, ok := inst.scheduledWakeup(); !ok || !at.Equal(wakeup) {
+		t.Errorf("scheduled wakeup: %v %v", at, ok)
+	}
+
+	inst.model.Status = &api.Status{State: api.StateHalted}
+
+	if _, ok := inst.scheduledWakeup(); ok {
+		t.Error("halted instance has scheduled wakeup")
+	}
+}
+
+func TestInstanceWakeupTimer(t *testing.T) {
+	inst := restoreInstance("test", nil, nil, &pb.Instance{
+		Status: &api.Status{State: api.StateSuspended},
+	})
+
+	fired := make(chan int, 2)
+	inst.setWakeupTimer(time.AfterFunc(time.Second/10, func() { fired <- 1 }))
+	inst.setWakeupTimer(time.AfterFunc(time.Second/5, func() { fired <- 2 }))
+
+	if n := <-fired; n != 2 {
+		t.Errorf("superseded timer fired")
+	}
+}
+
+func TestCrashCause(t *testing.T) {
+	for cause, crash := range map[api.Cause]bool{
+		api.CauseNormal:                  false,
+		api.CauseUnreachable:             true,
+		api.CauseMemoryAccessOutOfBounds: true,
+		api.CauseIntegerDivideByZero:     true,
+		api.CauseCallStackExhausted:      false,
+		api.CauseBreakpoint:              false,
+		api.CauseABIViolation:            false,
+		api.CauseInternal:                false,
+		api.CauseTimeout:                 false,
+	} {
+		if crashCause(cause) != crash {
+			t.Errorf("%v", cause)
+		}
+	}
+}
+
+func TestStepTargets(t *testing.T) {
+	code := make([]byte, 40)
+	insnMap := &objectdebug.InsnMap{
+		CallMap: object.CallMap{
+			FuncAddrs: []uint32{0x100, 0x200},
+		},
+	}
+
+	for _, x := range []struct {
+		addr   uint32
+		offset uint32
+		op     byte
+	}{
+		{0x100, 10, 0x03}, // loop
+		{0x104, 11, 0x01}, // nop
+		{0x108, 12, 0x10}, // call
+		{0x110, 14, 0x0d}, // br_if 0
+		{0x118, 16, 0x0b}, // end
+		{0x120, 17, 0x41}, // i32.const
+		{0x128, 19, 0x0b}, // end
+		{0x200, 30, 0x10}, // call
+		{0x208, 32, 0x1a}, // drop
+		{0x210, 33, 0x0b}, // end
+	} {
+		insnMap.Insns = append(insnMap.Insns, objectdebug.InsnMapping{Addr: x.addr, SourceOffset: x.offset})
+		code[x.offset] = x.op
+	}
+
+	frames := []stack.Frame{
+		{FuncIndex: 0, RetOffset: 14},
+		{FuncIndex: 1, RetOffset: 32},
+	}
+
+	// Loop body (backward), following instruction, loop exit, return site.
+	if targets := stepTargets(insnMap, code, frames); !reflect.DeepEqual(targets, []uint64{11, 16, 17, 32}) {
+		t.Errorf("targets: %v", targets)
+	}
+
+	// Last instruction of outermost function.
+	if targets := stepTargets(insnMap, code, []stack.Frame{{FuncIndex: 1, RetOffset: 33}}); len(targets) != 0 {
+		t.Errorf("targets at end: %v", targets)
+	}
+
+	if targets := stepTargets(insnMap, code, nil); len(targets) != 0 {
+		t.Errorf("targets without stack: %v", targets)
+	}
+}
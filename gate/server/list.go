@@ -0,0 +1,60 @@
+// Copyright (c) 2026 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"slices"
+	"sort"
+
+	"gate.computer/gate/server/api"
+)
+
+// matchTags reports if tags include all of the first set and at least one of
+// the second set.  Empty sets match everything.
+func matchTags(tags, all, anyOf []string) bool {
+	for _, t := range all {
+		if !slices.Contains(tags, t) {
+			return false
+		}
+	}
+
+	if len(anyOf) == 0 {
+		return true
+	}
+	for _, t := range anyOf {
+		if slices.Contains(tags, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchState reports if state is one of states.  Empty set matches
+// everything.
+func matchState(state api.State, states []api.State) bool {
+	return len(states) == 0 || slices.Contains(states, state)
+}
+
+// paginate items in id order.  Items with ids up to and including the cursor
+// are skipped.  Next cursor is empty if there are no more items.
+func paginate[T any](items []T, id func(T) string, cursor string, limit int32) (page []T, next string) {
+	sort.Slice(items, func(i, j int) bool {
+		return id(items[i]) < id(items[j])
+	})
+
+	if cursor != "" {
+		i := sort.Search(len(items), func(i int) bool {
+			return id(items[i]) > cursor
+		})
+		items = items[i:]
+	}
+
+	if limit > 0 && len(items) > int(limit) {
+		items = items[:limit]
+		next = id(items[len(items)-1])
+	}
+
+	return items, next
+}
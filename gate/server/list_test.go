@@ -0,0 +1,59 @@
+// Copyright (c) 2026 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMatchTags(t *testing.T) {
+	tags := []string{"a", "b", "c"}
+
+	for i, x := range []struct {
+		all   []string
+		anyOf []string
+		match bool
+	}{
+		{nil, nil, true},
+		{[]string{"a", "c"}, nil, true},
+		{[]string{"a", "d"}, nil, false},
+		{nil, []string{"d", "b"}, true},
+		{nil, []string{"d", "e"}, false},
+		{[]string{"a"}, []string{"d", "c"}, true},
+		{[]string{"d"}, []string{"a"}, false},
+	} {
+		if matchTags(tags, x.all, x.anyOf) != x.match {
+			t.Errorf("case %d: expected match=%v", i, x.match)
+		}
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	id := func(s string) string { return s }
+
+	var (
+		items  = []string{"d", "b", "e", "a", "c"}
+		cursor string
+		pages  [][]string
+	)
+	for {
+		var page []string
+		page, cursor = paginate(slices.Clone(items), id, cursor, 2)
+		pages = append(pages, page)
+		if cursor == "" {
+			break
+		}
+	}
+
+	expect := [][]string{{"a", "b"}, {"c", "d"}, {"e"}}
+	if !slices.EqualFunc(pages, expect, slices.Equal) {
+		t.Errorf("pages: %v", pages)
+	}
+
+	if page, next := paginate(slices.Clone(items), id, "", 0); len(page) != len(items) || next != "" {
+		t.Errorf("unlimited: %v %q", page, next)
+	}
+}
@@ -17,6 +17,9 @@ import (
 // ErrNonceReused may be returned by [NonceChecker.CheckNonce].
 var ErrNonceReused = errors.New("nonce reused")
 
+// ErrKeyValueQuota may be returned by [KeyValueStore.PutValue].
+var ErrKeyValueQuota = errors.New("key-value storage quota exceeded")
+
 type Inventory interface {
 	GetModule(ctx Context, pri principal.ID, key string, buf proto.Message) (found bool, err error)
 	PutModule(ctx Context, pri principal.ID, key string, buf proto.Message) error
@@ -29,6 +32,19 @@ type Inventory interface {
 	RemoveInstance(ctx Context, pri principal.ID, key string) error
 }
 
+// InventoryLister may be implemented by an Inventory.  It is used for
+// consistency checking.
+type InventoryLister interface {
+	ListModules(Context) ([]InventoryKey, error)
+	ListInstances(Context) ([]InventoryKey, error)
+}
+
+// InventoryKey identifies a module or instance record of a principal.
+type InventoryKey struct {
+	Principal *principal.ID
+	Key       string
+}
+
 type SourceCache interface {
 	GetSourceSHA256(ctx Context, uri string) (hash string, err error)
 	PutSourceSHA256(ctx Context, uri, hash string) error
@@ -37,3 +53,19 @@ type SourceCache interface {
 type NonceChecker interface {
 	CheckNonce(ctx Context, scope []byte, nonce string, expires time.Time) error
 }
+
+// KeyValueStore holds values of principals.  Module is empty unless the values
+// are scoped per module.
+type KeyValueStore interface {
+	GetValue(ctx Context, pri principal.ID, module string, key []byte) (value []byte, found bool, err error)
+
+	// PutValue returns ErrKeyValueQuota if the sum of key and value sizes of
+	// the principal (in all modules) would exceed maxTotalSize.
+	PutValue(ctx Context, pri principal.ID, module string, key, value []byte, maxTotalSize int) error
+
+	DeleteValue(ctx Context, pri principal.ID, module string, key []byte) (found bool, err error)
+
+	// ListKeys in ascending order.  Only keys which have the prefix and which
+	// are greater than after are listed.
+	ListKeys(ctx Context, pri principal.ID, module string, prefix, after []byte, limit int) ([][]byte, error)
+}
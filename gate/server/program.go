@@ -6,6 +6,7 @@ package server
 
 import (
 	"bufio"
+	"bytes"
 	"crypto/subtle"
 	"encoding/hex"
 	"fmt"
@@ -24,6 +25,9 @@ import (
 	"gate.computer/internal/error/badmodule"
 	"gate.computer/wag/compile"
 	"gate.computer/wag/object"
+	objectdebug "gate.computer/wag/object/debug"
+	"gate.computer/wag/section"
+	"gate.computer/wag/wa"
 )
 
 var errModuleSizeMismatch = &badmodule.Dual{
@@ -59,6 +63,7 @@ type program struct {
 	id      string
 	image   *image.Program
 	buffers *snapshot.Buffers
+	parent  *program // Referenced by incremental snapshot.
 
 	storeMu sync.Mutex
 	stored  bool
@@ -173,6 +178,10 @@ func (prog *program) unref(lock serverLock) {
 	if prog.refCount == 0 {
 		prog.image.Close()
 		prog.image = nil
+		if prog.parent != nil {
+			prog.parent.unref(lock)
+			prog.parent = nil
+		}
 		runtime.KeepAlive(prog)
 	}
 }
@@ -188,6 +197,18 @@ func (prog *program) mustEnsureStorage() {
 	prog.stored = true
 }
 
+// mustRestoreStorage stores the program again if it has been stored, because
+// an identical program file may have been removed by the collector in the
+// meantime.
+func (prog *program) mustRestoreStorage() {
+	prog.storeMu.Lock()
+	defer prog.storeMu.Unlock()
+
+	if prog.stored {
+		z.Check(prog.image.Store(prog.id))
+	}
+}
+
 func mustRebuildProgramImage(storage image.Storage, progPolicy *ProgramPolicy, content io.Reader, breakpoints []uint64) (*image.Program, *object.CallMap) {
 	callMap := new(object.CallMap)
 
@@ -216,3 +237,43 @@ func mustRebuildProgramImage(storage image.Storage, progPolicy *ProgramPolicy, c
 
 	return progImage, callMap
 }
+
+// mustLoadInsnMap compiles the code section for mapping instructions to
+// source offsets.  The text addresses match a program image which has been
+// built with the same breakpoints.
+func mustLoadInsnMap(storage image.Storage, progPolicy *ProgramPolicy, content io.Reader, breakpoints []uint64) (*objectdebug.InsnMap, []wa.FuncType) {
+	insnMap := new(objectdebug.InsnMap)
+
+	b := must(build.New(storage, 0, progPolicy.MaxTextSize, &insnMap.CallMap, false))
+	defer b.Close()
+
+	r := compile.NewLoader(bufio.NewReader(content))
+	b.InstallEarlySnapshotLoaders()
+	b.Module = must(compile.LoadInitialSections(b.ModuleConfig(), r))
+	b.StackSize = progPolicy.MaxStackSize
+	z.Check(b.BindFunctions(""))
+
+	if b.Snapshot == nil {
+		b.Snapshot = new(snapshot.Snapshot)
+	}
+	b.Snapshot.Breakpoints = append([]uint64(nil), breakpoints...)
+
+	z.Check(compile.LoadCodeSection(b.CodeConfig(insnMap), r, b.Module, abi.Library()))
+
+	return insnMap, b.Module.FuncTypes()
+}
+
+// mustLoadCodeSection reads the payload of the code section, which is what
+// source offsets refer to.
+func mustLoadCodeSection(content io.Reader) []byte {
+	config := compile.Config{
+		CustomSectionLoader: section.CustomLoader(nil), // Skip.
+	}
+
+	r := compile.NewLoader(bufio.NewReader(content))
+	must(compile.LoadInitialSections(&compile.ModuleConfig{Config: config}, r))
+
+	var buf bytes.Buffer
+	n := must(section.CopyStandardSection(&buf, r, section.Code, config.CustomSectionLoader))
+	return buf.Bytes()[buf.Len()-int(n):]
+}
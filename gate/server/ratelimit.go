@@ -0,0 +1,234 @@
+// Copyright (c) 2026 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"sync"
+	"time"
+
+	pbserver "gate.computer/gate/pb/server"
+	"gate.computer/gate/server/api"
+	"gate.computer/internal/principal"
+	"gate.computer/internal/serverapi"
+
+	. "import.name/type/context"
+)
+
+// maxRateLimitBuckets triggers removal of idle buckets.
+const maxRateLimitBuckets = 65536
+
+// RateLimit requests using token buckets which are keyed by principal and
+// operation.  Anonymous requests are keyed by remote host address if it is
+// known; otherwise they share buckets.  Rate limit errors are returned before
+// access authorization.  Operations initiated by the server itself are not
+// limited.
+type RateLimit struct {
+	RateLimitBucket
+
+	// Op overrides the default bucket for operations.  Map keys are operation
+	// names such as MODULE_UPLOAD or LAUNCH_UPLOAD.
+	Op map[string]RateLimitBucket
+}
+
+// RateLimitBucket parameters.  Zero rate means unlimited.
+type RateLimitBucket struct {
+	Rate  float64 // Tokens per second.
+	Burst int     // Bucket capacity; defaults to 1.
+}
+
+func (b RateLimitBucket) capacity() float64 {
+	return float64(max(b.Burst, 1))
+}
+
+type rateLimitKey struct {
+	pri  principal.RawKey
+	anon bool
+	host string // Anonymous client's address.
+	op   api.Op
+}
+
+type contextInternalKey struct{}
+
+var contextInternal any = contextInternalKey{}
+
+// contextWithInternal marks an operation which is initiated by the server itself.
+// Such operations are not rate limited.
+func contextWithInternal(ctx Context) Context {
+	return context.WithValue(ctx, contextInternal, true)
+}
+
+func internalContext(ctx Context) bool {
+	internal, _ := ctx.Value(contextInternal).(bool)
+	return internal
+}
+
+// remoteHost without port number.
+func remoteHost(ctx Context) string {
+	addr := serverapi.ContextRemoteAddr(ctx)
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+type tokenBucket struct {
+	tokens float64
+	time   time.Time
+}
+
+func (tb *tokenBucket) refill(now time.Time, b RateLimitBucket) {
+	tb.tokens = math.Min(tb.tokens+now.Sub(tb.time).Seconds()*b.Rate, b.capacity())
+	tb.time = now
+}
+
+type rateLimiter struct {
+	defaultBucket RateLimitBucket
+	opBuckets     map[api.Op]RateLimitBucket
+
+	mu      sync.Mutex
+	buckets map[rateLimitKey]*tokenBucket
+}
+
+// newRateLimiter returns nil if nothing is limited.
+func newRateLimiter(config *RateLimit) *rateLimiter {
+	l := &rateLimiter{
+		defaultBucket: config.RateLimitBucket,
+		opBuckets:     make(map[api.Op]RateLimitBucket, len(config.Op)),
+		buckets:       make(map[rateLimitKey]*tokenBucket),
+	}
+
+	limited := l.defaultBucket.Rate > 0
+
+	for name, b := range config.Op {
+		op, found := pbserver.Op_value[name]
+		if !found {
+			z.Panic(fmt.Errorf("unknown operation in rate limit configuration: %q", name))
+		}
+		l.opBuckets[api.Op(op)] = b
+
+		if b.Rate > 0 {
+			limited = true
+		}
+	}
+
+	if !limited {
+		return nil
+	}
+	return l
+}
+
+func (l *rateLimiter) bucket(op api.Op) RateLimitBucket {
+	if b, found := l.opBuckets[op]; found {
+		return b
+	}
+	return l.defaultBucket
+}
+
+// take a token or return a RetryAfter error.
+func (l *rateLimiter) take(ctx Context) error {
+	op := api.ContextOp(ctx)
+
+	b := l.bucket(op)
+	if b.Rate <= 0 || internalContext(ctx) {
+		return nil
+	}
+
+	key := rateLimitKey{op: op}
+	if pri := principal.ContextID(ctx); pri != nil {
+		key.pri = principal.Raw(pri)
+	} else {
+		key.anon = true
+		key.host = remoteHost(ctx)
+	}
+
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	tb := l.buckets[key]
+	if tb == nil {
+		if len(l.buckets) >= maxRateLimitBuckets {
+			l.prune(now)
+		}
+
+		tb = &tokenBucket{b.capacity(), now}
+		l.buckets[key] = tb
+	} else {
+		tb.refill(now, b)
+	}
+
+	if tb.tokens >= 1 {
+		tb.tokens--
+		return nil
+	}
+
+	wait := time.Duration((1 - tb.tokens) / b.Rate * float64(time.Second))
+	return RetryAfter(now.Add(wait))
+}
+
+// prune buckets which have been refilled to capacity.  They are equivalent to
+// nonexistent buckets.
+func (l *rateLimiter) prune(now time.Time) {
+	for key, tb := range l.buckets {
+		b := l.bucket(key.op)
+		tb.refill(now, b)
+		if tb.tokens >= b.capacity() {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// rateLimitedAccess takes a token before delegating authorization.
+type rateLimitedAccess struct {
+	Authorizer
+	limiter *rateLimiter
+}
+
+func (a *rateLimitedAccess) Authorize(ctx Context) (Context, error) {
+	if err := a.limiter.take(ctx); err != nil {
+		return ctx, err
+	}
+	return a.Authorizer.Authorize(ctx)
+}
+
+func (a *rateLimitedAccess) AuthorizeProgram(ctx Context, res *ResourcePolicy, prog *ProgramPolicy) (Context, error) {
+	if err := a.limiter.take(ctx); err != nil {
+		return ctx, err
+	}
+	return a.Authorizer.AuthorizeProgram(ctx, res, prog)
+}
+
+func (a *rateLimitedAccess) AuthorizeProgramSource(ctx Context, res *ResourcePolicy, prog *ProgramPolicy, source string) (Context, error) {
+	if err := a.limiter.take(ctx); err != nil {
+		return ctx, err
+	}
+	return a.Authorizer.AuthorizeProgramSource(ctx, res, prog, source)
+}
+
+func (a *rateLimitedAccess) AuthorizeInstance(ctx Context, res *ResourcePolicy, inst *InstancePolicy) (Context, error) {
+	if err := a.limiter.take(ctx); err != nil {
+		return ctx, err
+	}
+	return a.Authorizer.AuthorizeInstance(ctx, res, inst)
+}
+
+func (a *rateLimitedAccess) AuthorizeProgramInstance(ctx Context, res *ResourcePolicy, prog *ProgramPolicy, inst *InstancePolicy) (Context, error) {
+	if err := a.limiter.take(ctx); err != nil {
+		return ctx, err
+	}
+	return a.Authorizer.AuthorizeProgramInstance(ctx, res, prog, inst)
+}
+
+func (a *rateLimitedAccess) AuthorizeProgramInstanceSource(ctx Context, res *ResourcePolicy, prog *ProgramPolicy, inst *InstancePolicy, source string) (Context, error) {
+	if err := a.limiter.take(ctx); err != nil {
+		return ctx, err
+	}
+	return a.Authorizer.AuthorizeProgramInstanceSource(ctx, res, prog, inst, source)
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2026 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"testing"
+
+	"gate.computer/gate/server/api"
+	"gate.computer/internal/serverapi"
+)
+
+func TestRateLimitDisabled(t *testing.T) {
+	if l := newRateLimiter(&RateLimit{Op: map[string]RateLimitBucket{"MODULE_UPLOAD": {}}}); l != nil {
+		t.Error("limiter created without rates")
+	}
+}
+
+func TestRateLimitOp(t *testing.T) {
+	l := newRateLimiter(&RateLimit{
+		Op: map[string]RateLimitBucket{
+			"MODULE_UPLOAD": {Rate: 0.001, Burst: 2},
+		},
+	})
+
+	upload := serverapi.ContextWithOp(context.Background(), api.OpModuleUpload)
+	list := serverapi.ContextWithOp(context.Background(), api.OpModuleList)
+
+	for i := 0; i < 2; i++ {
+		if err := l.take(upload); err != nil {
+			t.Fatal(i, err)
+		}
+	}
+
+	err := l.take(upload)
+	if err == nil {
+		t.Fatal("burst exceeded without error")
+	}
+	if e := api.AsTooManyRequests(err); e == nil || e.RetryAfter() <= 0 {
+		t.Error(err)
+	}
+
+	for i := 0; i < 10; i++ {
+		if err := l.take(list); err != nil {
+			t.Fatal(i, err)
+		}
+	}
+}
+
+func TestRateLimitAnonymous(t *testing.T) {
+	l := newRateLimiter(&RateLimit{RateLimitBucket: RateLimitBucket{Rate: 0.001}})
+
+	ctx := serverapi.ContextWithOp(context.Background(), api.OpModuleList)
+	ctx1 := serverapi.ContextWithRemoteAddr(ctx, "192.0.2.1:1234")
+	ctx2 := serverapi.ContextWithRemoteAddr(ctx, "192.0.2.2:1234")
+
+	if err := l.take(ctx1); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.take(serverapi.ContextWithRemoteAddr(ctx, "192.0.2.1:5678")); err == nil {
+		t.Error("same host was not limited")
+	}
+	if err := l.take(ctx2); err != nil {
+		t.Error("different host was limited:", err)
+	}
+}
+
+func TestRateLimitInternal(t *testing.T) {
+	l := newRateLimiter(&RateLimit{RateLimitBucket: RateLimitBucket{Rate: 0.001}})
+
+	ctx := serverapi.ContextWithOp(context.Background(), api.OpInstanceResume)
+
+	if err := l.take(ctx); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		if err := l.take(contextWithInternal(ctx)); err != nil {
+			t.Fatal(i, err)
+		}
+	}
+	if err := l.take(ctx); err == nil {
+		t.Error("burst exceeded without error")
+	}
+}
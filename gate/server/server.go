@@ -7,10 +7,12 @@ package server
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"log/slog"
 	"net"
 	"strings"
+	"time"
 
 	"gate.computer/gate/image"
 	"gate.computer/gate/runtime"
@@ -21,11 +23,15 @@ import (
 	"gate.computer/gate/server/internal/error/failrequest"
 	"gate.computer/gate/server/internal/error/notfound"
 	"gate.computer/gate/server/logging"
+	"gate.computer/gate/server/model"
 	"gate.computer/gate/snapshot"
 	"gate.computer/gate/source"
 	"gate.computer/internal/error/resourcelimit"
+	pb "gate.computer/internal/pb/server"
 	"gate.computer/internal/principal"
 	"gate.computer/wag/object"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	"import.name/lock"
 
 	. "import.name/type/context"
@@ -64,6 +70,10 @@ type Server struct {
 	programs  map[string]*program
 	accounts  map[principal.RawKey]*account
 	anonymous map[*Instance]struct{}
+	collected map[string]struct{} // Program files removed by collector.
+
+	background     Context // Canceled on shutdown.
+	stopBackground context.CancelFunc
 }
 
 func New(ctx Context, config *Config) (_ *Server, err error) {
@@ -75,7 +85,9 @@ func New(ctx Context, config *Config) (_ *Server, err error) {
 		programs:  make(map[string]*program),
 		accounts:  make(map[principal.RawKey]*account),
 		anonymous: make(map[*Instance]struct{}),
+		collected: make(map[string]struct{}),
 	}
+	s.background, s.stopBackground = context.WithCancel(context.Background())
 
 	if config != nil {
 		s.Config = *config
@@ -92,6 +104,9 @@ func New(ctx Context, config *Config) (_ *Server, err error) {
 	if !s.Configured() {
 		panic("incomplete server configuration")
 	}
+	if l := newRateLimiter(&s.RateLimit); l != nil {
+		s.AccessPolicy = &rateLimitedAccess{s.AccessPolicy, l}
+	}
 
 	progs := must(s.ImageStorage.Programs())
 	insts := must(s.ImageStorage.Instances())
@@ -112,53 +127,222 @@ func New(ctx Context, config *Config) (_ *Server, err error) {
 		s.accounts[principal.Raw(id)] = owner
 	}
 
+	upgraded := make(map[string]bool)
 	for _, id := range progs {
-		s.mustLoadProgramDuringInit(lock, owner, id)
+		s.mustLoadProgramDuringInit(ctx, lock, owner, id, upgraded)
+	}
+	s.linkProgramParentsDuringInit(lock)
+	s.mustRestorePinsDuringInit(ctx, lock)
+
+	stored := make(map[string]bool, len(insts))
+	for _, key := range insts {
+		stored[key] = true
 	}
 	for _, key := range insts {
-		s.mustLoadInstanceDuringInit(lock, key)
+		s.mustLoadInstanceDuringInit(ctx, lock, key, stored, upgraded)
+	}
+
+	if s.Collector.Interval > 0 {
+		go s.collectLoop(s.background)
 	}
 
 	shutdown = nil
 	return s, nil
 }
 
-func (s *Server) mustLoadProgramDuringInit(lock serverLock, owner *account, progID string) {
-	image := must(s.ImageStorage.LoadProgram(progID))
-	if image == nil { // Race condition with human operator?
+// mustLoadProgramDuringInit recompiles the program if it was built for a
+// different runtime version.  Incompatible programs are skipped.
+func (s *Server) mustLoadProgramDuringInit(ctx Context, lock serverLock, owner *account, progID string, upgraded map[string]bool) {
+	progImage, err := s.ImageStorage.LoadProgram(progID)
+	if err != nil {
+		switch {
+		case errors.Is(err, image.ErrRecompile):
+			s.upgradeProgramDuringInit(ctx, lock, owner, progID, err, upgraded)
+		case errors.Is(err, image.ErrIncompatible), errors.Is(err, image.ErrCorrupt):
+			slog.Warn("server: program not loaded", "module", progID, "error", err)
+		default:
+			z.Panic(err)
+		}
+		return
+	}
+	if progImage == nil { // Race condition with human operator?
 		return
 	}
-	defer closeProgramImage(&image)
+	defer closeProgramImage(&progImage)
 
-	prog := newProgram(progID, image, must(image.LoadBuffers()), true)
-	image = nil
+	prog := newProgram(progID, progImage, must(progImage.LoadBuffers()), true)
+	progImage = nil
 
 	if owner != nil {
 		owner.ensureProgramRef(lock, prog, nil)
+		s.mustRestoreModuleRecord(ctx, lock, owner, prog)
 	}
 
 	s.programs[progID] = prog
 }
 
-func (s *Server) mustLoadInstanceDuringInit(lock serverLock, key string) {
-	image := must(s.ImageStorage.LoadInstance(key))
-	if image == nil { // Race condition with human operator?
+// linkProgramParentsDuringInit makes incremental snapshots hold references to
+// their parent programs.  Programs are loaded in arbitrary order.
+func (s *Server) linkProgramParentsDuringInit(lock serverLock) {
+	for _, prog := range s.programs {
+		if parent := s.programs[prog.image.Parent()]; parent != nil {
+			prog.parent = parent.ref(lock)
+		}
+	}
+}
+
+// mustRestorePinsDuringInit restores the pinned modules of all principals
+// found in inventory, if it can be listed.
+func (s *Server) mustRestorePinsDuringInit(ctx Context, lock serverLock) {
+	lister, ok := s.Inventory.(model.InventoryLister)
+	if !ok {
+		return
+	}
+
+	for _, k := range must(lister.ListModules(ctx)) {
+		prog := s.programs[k.Key]
+		if prog == nil {
+			slog.Warn("server: pinned module not found", "principal", k.Principal, "module", k.Key)
+			continue
+		}
+
+		acc := s.ensureAccount(lock, k.Principal)
+		acc.ensureProgramRef(lock, prog, nil)
+		s.mustRestoreModuleRecord(ctx, lock, acc, prog)
+	}
+}
+
+// mustRestoreModuleRecord replaces account's program record with the one
+// found in inventory.
+func (s *Server) mustRestoreModuleRecord(ctx Context, lock serverLock, acc *account, prog *program) {
+	x := new(pb.Module)
+	if must(s.Inventory.GetModule(ctx, *acc.ID, prog.id, x)) {
+		acc.programs[prog] = x
+	}
+}
+
+// upgradeProgramDuringInit builds a stored program again from its module, and
+// replaces the stored program.  Failure is logged.
+func (s *Server) upgradeProgramDuringInit(ctx Context, lock serverLock, owner *account, progID string, reason error, upgraded map[string]bool) {
+	err := z.Recover(func() {
+		content, length, err := s.ImageStorage.LoadProgramModule(progID)
+		z.Check(err)
+
+		policy := new(progPolicy)
+		if _, err := s.AccessPolicy.AuthorizeProgram(contextWithInternal(ctx), &policy.res, &policy.prog); err != nil {
+			policy.prog = DefaultAccessConfig.ProgramPolicy
+		}
+
+		upload := &api.ModuleUpload{
+			Stream: content,
+			Length: length,
+		}
+		prog, _ := mustBuildProgram(s.ImageStorage, &policy.prog, nil, upload, "")
+		defer func() {
+			if prog != nil {
+				prog.unref(lock)
+			}
+		}()
+
+		if prog.id != progID {
+			z.Panic(fmt.Errorf("module hash mismatch: %s", prog.id))
+		}
+
+		prog.mustEnsureStorage()
+
+		if owner != nil {
+			owner.ensureProgramRef(lock, prog, nil)
+			s.mustRestoreModuleRecord(ctx, lock, owner, prog)
+		}
+
+		s.programs[progID] = prog
+		prog = nil
+		upgraded[progID] = true
+	})
+	if err != nil {
+		slog.Error("server: program upgrade failed", "module", progID, "reason", reason, "error", err)
+		return
+	}
+
+	slog.Info("server: program upgraded", "module", progID, "reason", reason)
+}
+
+// mustLoadInstanceDuringInit restores instances which have been recorded in
+// inventory.  An instance which was running is restored from its latest
+// checkpoint unless it was stored after that.
+func (s *Server) mustLoadInstanceDuringInit(ctx Context, lock serverLock, key string, stored, upgraded map[string]bool) {
+	instImage, err := s.ImageStorage.LoadInstance(key)
+	if err != nil {
+		if errors.Is(err, image.ErrIncompatible) {
+			slog.Warn("server: instance not loaded", "key", key, "error", err)
+			return
+		}
+		z.Panic(err)
+	}
+	if instImage == nil { // Race condition with human operator?
+		return
+	}
+	defer closeInstanceImage(&instImage)
+
+	pri, instID, checkpoint := mustParseInstanceStorageKey(key)
+
+	if checkpoint && stored[instanceStorageKey(pri, instID)] {
+		z.Check(instImage.Unstore()) // Superseded.
 		return
 	}
-	defer closeInstanceImage(&image)
 
-	pri, instID := mustParseInstanceStorageKey(key)
 	acc := s.ensureAccount(lock, pri)
 
-	// TODO: restore instance
-	slog.Debug("server: instance loading not implemented", "principal", acc.ID, "instance", instID, "trap", image.Trap())
+	model := new(pb.Instance)
+	if !must(s.Inventory.GetInstance(ctx, *pri, instID, model)) {
+		// TODO: restore instances without inventory record
+		slog.Debug("server: instance loading not implemented", "principal", acc.ID, "instance", instID, "trap", instImage.Trap())
+		return
+	}
+
+	prog := s.programs[model.Module]
+	if prog == nil {
+		slog.Warn("server: instance module not found", "principal", acc.ID, "instance", instID, "module", model.Module)
+		return
+	}
+
+	err = instImage.CheckCompatibility()
+	if err == nil && upgraded[model.Module] && instImage.StackUsage() != 0 {
+		err = fmt.Errorf("%w: instance %s was suspended before its program was recompiled", image.ErrIncompatible, instID)
+	}
+	if err != nil {
+		slog.Warn("server: instance cannot be resumed", "principal", acc.ID, "instance", instID, "error", err)
+		model.Status = &api.Status{
+			State: api.StateKilled,
+			Cause: api.CauseInternal,
+			Error: "instance cannot be resumed after runtime upgrade",
+		}
+		model.Hibernated = false
+		z.Check(s.Inventory.UpdateInstance(ctx, *pri, instID, model))
+	}
+
+	inst := restoreInstance(instID, acc, instImage, model)
+	instImage = nil
+
+	acc.instances[instID] = accountInstance{inst, prog.ref(lock)}
+
+	slog.Info("server: instance restored", "principal", acc.ID, "instance", instID, "checkpoint", checkpoint)
+
+	if t, ok := inst.scheduledWakeup(); ok {
+		s.scheduleWakeup(inst, t)
+	}
 }
 
 func (s *Server) Shutdown(ctx Context) error {
 	var (
+		accs      map[principal.RawKey]*account
 		accInsts  []*Instance
 		anonInsts map[*Instance]struct{}
 	)
+	if s.stopBackground != nil {
+		s.stopBackground()
+	}
+
 	lock.GuardTag(&s.mu, func(lock serverLock) {
 		progs := s.programs
 		s.programs = nil
@@ -167,7 +351,7 @@ func (s *Server) Shutdown(ctx Context) error {
 			prog.unref(lock)
 		}
 
-		accs := s.accounts
+		accs = s.accounts
 		s.accounts = nil
 
 		for _, acc := range accs {
@@ -194,11 +378,16 @@ func (s *Server) Shutdown(ctx Context) error {
 		if inst.Wait(ctx).State == api.StateRunning {
 			aborted = true
 		}
+		inst.releaseHibernation()
 	}
 	for inst := range anonInsts {
 		inst.Wait(ctx)
 	}
 
+	for _, acc := range accs {
+		acc.watchers.close()
+	}
+
 	if aborted {
 		return ctx.Err()
 	}
@@ -238,7 +427,9 @@ func (s *Server) UploadModule(ctx Context, upload *api.ModuleUpload, know *api.M
 		z.Panic(resourcelimit.Error("module size limit exceeded"))
 	}
 
-	// TODO: check resource policy
+	if know.Pin && upload.Length > int64(policy.res.TotalStorageSize) {
+		z.Panic(failrequest.Error(event.FailResourceLimit, "total storage size limit exceeded"))
+	}
 
 	if upload.Hash != "" && s.mustLoadKnownModule(ctx, policy, upload, know) {
 		return upload.Hash, nil
@@ -310,7 +501,7 @@ func (s *Server) mustLoadKnownModule(ctx Context, policy *progPolicy, upload *ap
 		z.Panic(resourcelimit.Error("program code size limit exceeded"))
 	}
 
-	s.mustRegisterProgramRef(ctx, prog, know)
+	s.mustRegisterProgramRef(ctx, &policy.res, prog, know)
 	prog = nil
 
 	s.eventModule(ctx, event.TypeModuleUploadExist, &event.Module{
@@ -325,7 +516,7 @@ func (s *Server) mustLoadUnknownModule(ctx Context, policy *progPolicy, upload *
 	defer s.unrefProgram(&prog)
 	progID := prog.id
 
-	redundant := s.mustRegisterProgramRef(ctx, prog, know)
+	redundant := s.mustRegisterProgramRef(ctx, &policy.res, prog, know)
 	prog = nil
 
 	if redundant {
@@ -363,10 +554,16 @@ func (s *Server) UnixInstance(ctx Context, conn *net.UnixConn) (err error) {
 		z.Panic(errAnonymous)
 	}
 
-	proc := must(runtime.NewUnixProcess(conn))
+	acc.mustReserveProc(&policy.res, 0)
+	proc, e := runtime.NewUnixProcess(conn)
+	if e != nil {
+		acc.releaseProc(0)
+		z.Panic(e)
+	}
 	defer func() {
 		if proc != nil {
 			proc.Close()
+			acc.releaseProc(0)
 		}
 	}()
 
@@ -374,14 +571,14 @@ func (s *Server) UnixInstance(ctx Context, conn *net.UnixConn) (err error) {
 		z.Panic(PermissionDenied("no service policy"))
 	}
 
-	services := policy.inst.Services(ctx)
+	services := policy.inst.Services(contextWithResourcePolicy(ctx, &policy.res))
 	defer func() {
 		if services != nil {
 			services.Close()
 		}
 	}()
 
-	inst := newInstance(makeInstanceID(), acc, true, true, nil, new(snapshot.Buffers), proc, services, policy.inst.TimeResolution, nil, nil)
+	inst := newInstance(makeInstanceID(), acc, true, true, nil, new(snapshot.Buffers), proc, 0, services, policy.inst.TimeResolution, timeBudget{}, &policy.res, 0, nil, false, nil, nil)
 	proc = nil
 	services = nil
 
@@ -434,7 +631,7 @@ func (s *Server) NewInstance(ctx Context, module string, launch *api.LaunchOptio
 	defer closeInstanceImage(&instImage)
 
 	ref := &api.ModuleOptions{}
-	inst, prog, _ := s.mustRegisterProgramRefInstance(ctx, acc, prog, instImage, &policy.inst, ref, launch)
+	inst, prog, _ := s.mustRegisterProgramRefInstance(ctx, acc, prog, instImage, nil, &policy.res, &policy.inst, ref, launch)
 	instImage = nil
 
 	s.mustRunOrDeleteInstance(ctx, inst, prog, launch.Function)
@@ -521,7 +718,9 @@ func (s *Server) mustLoadModuleInstance(ctx Context, acc *account, policy *instP
 		z.Panic(resourcelimit.Error("module size limit exceeded"))
 	}
 
-	// TODO: check resource policy
+	if know.Pin && upload.Length > int64(policy.res.TotalStorageSize) {
+		z.Panic(failrequest.Error(event.FailResourceLimit, "total storage size limit exceeded"))
+	}
 
 	if upload.Hash != "" {
 		inst := s.mustLoadKnownModuleInstance(ctx, acc, policy, upload, know, launch)
@@ -554,7 +753,7 @@ func (s *Server) mustLoadKnownModuleInstance(ctx Context, acc *account, policy *
 	instImage := must(image.NewInstance(prog.image, policy.inst.MaxMemorySize, policy.inst.StackSize, funcIndex))
 	defer closeInstanceImage(&instImage)
 
-	inst, prog, _ := s.mustRegisterProgramRefInstance(ctx, acc, prog, instImage, &policy.inst, know, launch)
+	inst, prog, _ := s.mustRegisterProgramRefInstance(ctx, acc, prog, instImage, nil, &policy.res, &policy.inst, know, launch)
 	instImage = nil
 
 	s.eventModule(ctx, event.TypeModuleUploadExist, &event.Module{
@@ -575,7 +774,7 @@ func (s *Server) mustLoadUnknownModuleInstance(ctx Context, acc *account, policy
 	defer s.unrefProgram(&prog)
 	progID := prog.id
 
-	inst, prog, redundantProg := s.mustRegisterProgramRefInstance(ctx, acc, prog, instImage, &policy.inst, know, launch)
+	inst, prog, redundantProg := s.mustRegisterProgramRefInstance(ctx, acc, prog, instImage, nil, &policy.res, &policy.inst, know, launch)
 	instImage = nil
 
 	if upload.Hash != "" {
@@ -649,8 +848,9 @@ func (s *Server) ModuleInfo(ctx Context, module string) (_ *api.ModuleInfo, err
 	}
 
 	info := &api.ModuleInfo{
-		Module: prog.id,
-		Tags:   append([]string(nil), x.Tags...),
+		Module:  prog.id,
+		Tags:    append([]string(nil), x.Tags...),
+		Lineage: proto.CloneOf(x.Lineage),
 	}
 
 	s.eventModule(ctx, event.TypeModuleInfo, &event.Module{
@@ -660,7 +860,7 @@ func (s *Server) ModuleInfo(ctx Context, module string) (_ *api.ModuleInfo, err
 	return info, nil
 }
 
-func (s *Server) Modules(ctx Context) (_ *api.Modules, err error) {
+func (s *Server) Modules(ctx Context, opt *api.ModuleListOptions) (_ *api.Modules, err error) {
 	if internal.DontPanic() {
 		defer func() { err = z.Error(recover()) }()
 	}
@@ -668,6 +868,8 @@ func (s *Server) Modules(ctx Context) (_ *api.Modules, err error) {
 	ctx, end := s.startOp(ctx, api.OpModuleList)
 	defer end(ctx)
 
+	opt = prepareModuleListOptions(opt)
+
 	ctx = must(s.AccessPolicy.Authorize(ctx))
 
 	pri := principal.ContextID(ctx)
@@ -677,23 +879,24 @@ func (s *Server) Modules(ctx Context) (_ *api.Modules, err error) {
 
 	s.event(ctx, event.TypeModuleList)
 
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	acc := s.accounts[principal.Raw(pri)]
-	if acc == nil {
-		return new(api.Modules), nil
-	}
+	var mods []*api.ModuleInfo
+	lock.GuardTag(&s.mu, func(serverLock) {
+		if acc := s.accounts[principal.Raw(pri)]; acc != nil {
+			mods = make([]*api.ModuleInfo, 0, len(acc.programs))
+			for prog, x := range acc.programs {
+				if matchTags(x.Tags, opt.TagsAll, opt.TagsAny) {
+					mods = append(mods, &api.ModuleInfo{
+						Module:  prog.id,
+						Tags:    append([]string(nil), x.Tags...),
+						Lineage: proto.CloneOf(x.Lineage),
+					})
+				}
+			}
+		}
+	})
 
-	infos := &api.Modules{
-		Modules: make([]*api.ModuleInfo, 0, len(acc.programs)),
-	}
-	for prog, x := range acc.programs {
-		infos.Modules = append(infos.Modules, &api.ModuleInfo{
-			Module: prog.id,
-			Tags:   append([]string(nil), x.Tags...),
-		})
-	}
+	infos := new(api.Modules)
+	infos.Modules, infos.NextCursor = paginate(mods, (*api.ModuleInfo).GetModule, opt.Cursor, opt.Limit)
 	return infos, nil
 }
 
@@ -813,11 +1016,13 @@ func (s *Server) PinModule(ctx Context, module string, know *api.ModuleOptions)
 		}
 
 	do:
-		// TODO: check resource limits
+		acc.mustCheckProgramRefLimits(lock, prog, &policy.res)
 		return acc.ensureProgramRef(lock, prog, know.Tags)
 	})
 
 	if modified {
+		s.storeModuleRecord(ctx, pri, module)
+
 		s.eventModule(ctx, event.TypeModulePin, &event.Module{
 			Module:   module,
 			TagCount: int32(len(know.Tags)),
@@ -859,6 +1064,8 @@ func (s *Server) UnpinModule(ctx Context, module string) (err error) {
 		z.Panic(notfound.ErrModule)
 	}
 
+	s.removeModuleRecord(ctx, pri, module)
+
 	s.eventModule(ctx, event.TypeModuleUnpin, &event.Module{
 		Module: module,
 	})
@@ -866,6 +1073,15 @@ func (s *Server) UnpinModule(ctx Context, module string) (err error) {
 	return
 }
 
+// removeModuleRecord from inventory after the module has been unpinned.
+// Otherwise a stale record would be restored during initialization if the
+// module is pinned again.  Failure is reported as an event.
+func (s *Server) removeModuleRecord(ctx Context, pri *principal.ID, module string) {
+	if err := s.Inventory.RemoveModule(ctx, *pri, module); err != nil {
+		s.eventFail(ctx, event.TypeFailInternal, internalFail(module, "", "", "inventory", err), err)
+	}
+}
+
 func (s *Server) InstanceConnection(ctx Context, instance string) (_ api.Instance, _ func(Context, io.Reader, io.WriteCloser) *api.Status, err error) {
 	if internal.DontPanic() {
 		defer func() { err = z.Error(recover()) }()
@@ -877,6 +1093,8 @@ func (s *Server) InstanceConnection(ctx Context, instance string) (_ api.Instanc
 	ctx = must(s.AccessPolicy.Authorize(ctx))
 
 	inst := s.mustGetInstance(ctx, instance)
+	s.mustWakeInstance(ctx, inst)
+
 	conn := inst.connect(ctx)
 	if conn == nil {
 		s.eventFail(ctx, event.TypeFailRequest, &event.Fail{
@@ -999,6 +1217,7 @@ func (s *Server) ResumeInstance(ctx Context, instance string, resume *api.Resume
 	defer end(ctx)
 
 	resume = prepareResumeOptions(resume)
+	mustCheckInvokeOptions(resume.Invoke)
 	policy := new(instPolicy)
 
 	ctx = must(s.AccessPolicy.AuthorizeInstance(ctx, &policy.res, &policy.inst))
@@ -1006,22 +1225,140 @@ func (s *Server) ResumeInstance(ctx Context, instance string, resume *api.Resume
 	inst, prog := s.mustGetInstanceRefProgram(ctx, instance)
 	defer s.unrefProgram(&prog)
 
-	inst.mustCheckResume(resume.Function)
+	var rollback *instanceRollback
+	if resume.Module != "" {
+		rollback = s.mustPrepareRollback(inst, resume.Module, &policy.inst)
+		defer s.closeRollback(rollback)
+	}
+
+	s.mustResumeInstance(ctx, inst, prog, policy, resume, rollback)
+	prog = nil
+
+	s.eventInstance(ctx, event.TypeInstanceResume, &event.Instance{
+		Instance: inst.id,
+		Module:   resume.Module,
+		Function: resume.Function,
+	}, nil)
+
+	return inst, nil
+}
+
+// mustResumeInstance allocates resources for a stopped instance according to
+// policy and runs it.  The instance is rolled back after the checks and
+// allocations, if rollback is specified.  The program reference is stolen.
+func (s *Server) mustResumeInstance(ctx Context, inst *Instance, prog *program, policy *instPolicy, resume *api.ResumeOptions, rollback *instanceRollback) {
+	defer s.unrefProgram(&prog)
+
+	var resident int64
+	if rollback != nil {
+		inst.mustCheckRollback(rollback.image, resume.Function)
+		resident = residentSize(rollback.prog.image, rollback.image)
+	} else {
+		inst.mustCheckResume(resume.Function)
+		resident = inst.residentSize(prog)
+	}
+
+	proc, services := s.mustAllocateInstanceResources(ctx, inst.acc, resident, &policy.res, &policy.inst)
+	defer closeInstanceResources(inst.acc, resident, &proc, &services)
 
-	proc, services := s.mustAllocateInstanceResources(ctx, &policy.inst)
-	defer closeInstanceResources(&proc, &services)
+	if rollback != nil {
+		s.mustRollbackInstance(ctx, inst, &prog, rollback, resume.Function)
+	}
 
-	inst.mustResume(resume.Function, proc, services, policy.inst.TimeResolution, s.openDebugLog(resume.Invoke))
+	inst.mustResume(resume.Function, proc, resident, services, policy.inst.TimeResolution, prepareTimeBudget(resume.Budget, &policy.inst), &policy.res, policy.inst.IdleTimeout, resume.CheckpointInterval, s.openDebugLog(resume.Invoke))
 	proc = nil
 	services = nil
 
 	s.mustRunOrDeleteInstance(ctx, inst, prog, resume.Function)
 	prog = nil
+}
 
-	s.eventInstance(ctx, event.TypeInstanceResume, &event.Instance{
-		Instance: inst.id,
-		Function: resume.Function,
-	}, nil)
+// instanceRollback holds a snapshot program and an instance image created
+// from it.
+type instanceRollback struct {
+	prog  *program
+	image *image.Instance
+}
+
+// mustPrepareRollback of a stopped instance to a snapshot module.
+func (s *Server) mustPrepareRollback(inst *Instance, module string, policy *InstancePolicy) *instanceRollback {
+	snap := lock.GuardTagged(&s.mu, func(lock serverLock) *program {
+		if p := s.programs[module]; p != nil {
+			return inst.acc.refProgram(lock, p)
+		}
+		return nil
+	})
+	if snap == nil {
+		z.Panic(notfound.ErrModule)
+	}
+	defer s.unrefProgram(&snap)
+
+	instImage := must(image.NewInstance(snap.image, policy.MaxMemorySize, policy.StackSize, -1))
+
+	rollback := &instanceRollback{snap, instImage}
+	snap = nil
+	return rollback
+}
+
+func (s *Server) closeRollback(rollback *instanceRollback) {
+	closeInstanceImage(&rollback.image)
+	s.unrefProgram(&rollback.prog)
+}
+
+// mustRollbackInstance replaces the instance's image and program with the
+// rollback's, and persists the change.  The caller's program reference is
+// swapped with the rollback's.
+func (s *Server) mustRollbackInstance(ctx Context, inst *Instance, prog **program, rollback *instanceRollback, function string) {
+	inst.mustRollback(*prog, rollback.prog, rollback.image, function)
+	rollback.image = nil
+
+	lock.GuardTag(&s.mu, func(lock serverLock) {
+		if x, found := inst.acc.instances[inst.id]; found && x.inst == inst {
+			x.prog.unref(lock)
+			inst.acc.instances[inst.id] = accountInstance{inst, rollback.prog.ref(lock)}
+		}
+	})
+
+	*prog, rollback.prog = rollback.prog, *prog
+
+	z.Check(s.storeInstanceRecord(ctx, inst, (*prog).id))
+}
+
+func (s *Server) CloneInstance(ctx Context, instance string, launch *api.LaunchOptions) (_ api.Instance, err error) {
+	if internal.DontPanic() {
+		defer func() { err = z.Error(recover()) }()
+	}
+
+	ctx, end := s.startOp(ctx, api.OpInstanceClone)
+	defer end(ctx)
+
+	launch = mustPrepareLaunchOptions(launch)
+	mustCheckInvokeOptions(launch.Invoke)
+
+	policy := new(instPolicy)
+	ctx = must(s.AccessPolicy.AuthorizeInstance(ctx, &policy.res, &policy.inst))
+
+	acc := s.mustCheckAccountInstanceID(ctx, launch.Instance)
+	if acc == nil {
+		z.Panic(errAnonymous)
+	}
+
+	source, prog := s.mustGetInstanceRefProgram(ctx, instance)
+	defer s.unrefProgram(&prog)
+
+	instImage, buffers := source.mustClone(prog, launch.Function, launch.Suspend)
+	defer closeInstanceImage(&instImage)
+
+	ref := &api.ModuleOptions{}
+	inst, prog, _ := s.mustRegisterProgramRefInstance(ctx, acc, prog, instImage, buffers, &policy.res, &policy.inst, ref, launch)
+	instImage = nil
+
+	s.mustRunOrDeleteInstance(ctx, inst, prog, launch.Function)
+	prog = nil
+
+	info := newInstanceCreateInfo(inst.id, "", launch)
+	info.Source = source.id
+	s.eventInstance(ctx, event.TypeInstanceClone, info, nil)
 
 	return inst, nil
 }
@@ -1037,9 +1374,15 @@ func (s *Server) DeleteInstance(ctx Context, instance string) (err error) {
 	ctx = must(s.AccessPolicy.Authorize(ctx))
 
 	inst := s.mustGetInstance(ctx, instance)
-	inst.mustAnnihilate()
+	inventoried := inst.mustAnnihilate()
 	s.deleteNonexistentInstance(inst)
 
+	if inventoried {
+		if err := s.Inventory.RemoveInstance(ctx, *inst.acc.ID, inst.id); err != nil {
+			s.eventFail(ctx, event.TypeFailInternal, internalFail("", "", inst.id, "inventory", err), err)
+		}
+	}
+
 	s.eventInstance(ctx, event.TypeInstanceDelete, &event.Instance{
 		Instance: inst.id,
 	}, nil)
@@ -1068,7 +1411,7 @@ func (s *Server) Snapshot(ctx Context, instance string, know *api.ModuleOptions)
 		inst.suspend(false)
 		if inst.Wait(context.Background()).State == api.StateSuspended {
 			defer func() {
-				_, e := s.ResumeInstance(ctx, instance, nil)
+				_, e := s.ResumeInstance(contextWithInternal(ctx), instance, nil)
 				if module != "" {
 					z.Check(e)
 				}
@@ -1081,14 +1424,130 @@ func (s *Server) Snapshot(ctx Context, instance string, know *api.ModuleOptions)
 }
 
 func (s *Server) mustSnapshot(ctx Context, instance string, know *api.ModuleOptions) string {
-	ctx = must(s.AccessPolicy.Authorize(ctx))
-
-	// TODO: check module storage limits
+	policy := new(progPolicy)
+	ctx = must(s.AccessPolicy.AuthorizeProgram(ctx, &policy.res, &policy.prog))
 
 	inst, oldProg := s.mustGetInstanceRefProgram(ctx, instance)
 	defer s.unrefProgram(&oldProg)
 
-	newImage, buffers := inst.mustSnapshot(oldProg)
+	var base *program
+	if policy.prog.IncrementalSnapshot {
+		base = s.refSnapshotBase(inst, oldProg)
+		defer s.unrefProgram(&base)
+	}
+
+	newImage, buffers := inst.mustSnapshot(oldProg, base)
+	progID := s.mustRegisterSnapshot(ctx, &policy.res, inst, oldProg.id, base, newImage, buffers, know)
+
+	inst.setSnapshotBase(progID)
+	if err := s.storeInstanceRecord(ctx, inst, oldProg.id); err != nil {
+		s.eventFail(ctx, event.TypeFailInternal, internalFail(oldProg.id, "", inst.id, "inventory", err), err)
+	}
+
+	return progID
+}
+
+// recordModuleLineage in the account's program record, and store the record
+// in inventory.
+func (s *Server) recordModuleLineage(ctx Context, module string, lineage *api.ModuleLineage) error {
+	pri := principal.ContextID(ctx)
+
+	record := lock.GuardTagged(&s.mu, func(lock serverLock) *pb.Module {
+		if x := s.accountModule(lock, pri, module); x != nil {
+			x.Lineage = lineage
+			return proto.CloneOf(x)
+		}
+		return nil
+	})
+
+	return s.putModuleRecord(ctx, pri, module, record)
+}
+
+// storeModuleRecord copies the account's program record to inventory, so that
+// the pin can be restored during initialization.  Failure is reported as an
+// event.
+func (s *Server) storeModuleRecord(ctx Context, pri *principal.ID, module string) {
+	record := lock.GuardTagged(&s.mu, func(lock serverLock) *pb.Module {
+		if x := s.accountModule(lock, pri, module); x != nil {
+			return proto.CloneOf(x)
+		}
+		return nil
+	})
+
+	if err := s.putModuleRecord(ctx, pri, module, record); err != nil {
+		s.eventFail(ctx, event.TypeFailInternal, internalFail(module, "", "", "inventory", err), err)
+	}
+}
+
+// accountModule returns the account's program record, or nil.
+func (s *Server) accountModule(lock serverLock, pri *principal.ID, module string) *pb.Module {
+	if acc := s.accounts[principal.Raw(pri)]; acc != nil {
+		if prog := s.programs[module]; prog != nil {
+			return acc.programs[prog]
+		}
+	}
+	return nil
+}
+
+// putModuleRecord inserts or updates a record in inventory.  Nil record is
+// ignored.
+func (s *Server) putModuleRecord(ctx Context, pri *principal.ID, module string, record *pb.Module) error {
+	if record == nil {
+		return nil
+	}
+
+	found, err := s.Inventory.GetModule(ctx, *pri, module, new(pb.Module))
+	if err != nil {
+		return err
+	}
+	if found {
+		return s.Inventory.UpdateModule(ctx, *pri, module, record)
+	}
+	return s.Inventory.PutModule(ctx, *pri, module, record)
+}
+
+// refSnapshotBase returns the latest snapshot of the instance if it's still
+// available, or the instance's program.
+func (s *Server) refSnapshotBase(inst *Instance, prog *program) *program {
+	module := inst.snapshotBase()
+
+	lock := s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if base := s.programs[module]; module != "" && base != nil {
+		return base.ref(lock)
+	}
+	return prog.ref(lock)
+}
+
+// storeCrashSnapshot registers the snapshot of an instance which was killed
+// by a trap.  The module is tagged with the instance ID and the cause.  The
+// resource policy captured at launch applies.
+func (s *Server) storeCrashSnapshot(ctx Context, inst *Instance, prog *program, crash *crashSnapshot) {
+	defer crash.image.Close()
+
+	err := z.Recover(func() {
+		ctx := principal.ContextWithID(ctx, inst.acc.ID)
+		progImage := must(image.Snapshot(prog.image, crash.image, crash.buffers, false))
+
+		know := &api.ModuleOptions{
+			Pin: true,
+			Tags: []string{
+				"instance=" + inst.id,
+				"cause=" + crash.cause.String(),
+			},
+		}
+
+		s.mustRegisterSnapshot(ctx, &crash.res, inst, prog.id, nil, progImage, crash.buffers, know)
+	})
+	if err != nil {
+		s.eventFail(ctx, event.TypeFailInternal, internalFail(prog.id, "", inst.id, "crash snapshot", err), err)
+	}
+}
+
+// mustRegisterSnapshot steals newImage.  Base program is referenced by the new
+// program if it's an incremental snapshot of it.
+func (s *Server) mustRegisterSnapshot(ctx Context, res *ResourcePolicy, inst *Instance, parent string, base *program, newImage *image.Program, buffers *snapshot.Buffers, know *api.ModuleOptions) string {
 	defer closeProgramImage(&newImage)
 
 	h := api.KnownModuleHash.New()
@@ -1101,9 +1560,24 @@ func (s *Server) mustSnapshot(ctx Context, instance string, know *api.ModuleOpti
 	newImage = nil
 	defer s.unrefProgram(&newProg)
 
-	s.mustRegisterProgramRef(ctx, newProg, know)
+	if base != nil && newProg.image.Parent() == base.id {
+		lock.GuardTag(&s.mu, func(lock serverLock) {
+			newProg.parent = base.ref(lock)
+		})
+	}
+
+	s.mustRegisterProgramRef(ctx, res, newProg, know)
 	newProg = nil
 
+	lineage := &api.ModuleLineage{
+		Parent:   parent,
+		Instance: inst.id,
+		Created:  timestamppb.Now(),
+	}
+	if err := s.recordModuleLineage(ctx, progID, lineage); err != nil {
+		s.eventFail(ctx, event.TypeFailInternal, internalFail(progID, "", inst.id, "inventory", err), err)
+	}
+
 	s.eventInstance(ctx, event.TypeInstanceSnapshot, &event.Instance{
 		Instance: inst.id,
 		Module:   progID,
@@ -1178,15 +1652,85 @@ func (s *Server) DebugInstance(ctx Context, instance string, req *api.DebugReque
 		progImage = nil
 	}
 
+	switch req.Op {
+	case api.DebugOpStep:
+		res = s.mustStepInstance(ctx, inst, defaultProg, &policy.prog)
+
+	case api.DebugOpReadStacktrace:
+		breaks := inst.breakpoints()
+		insnMap, funcTypes := mustLoadInsnMap(s.ImageStorage, &policy.prog, defaultProg.image.NewModuleReader(), breaks)
+		info := mustLoadDebugInfo(defaultProg.image.NewModuleReader())
+		res.Stacktrace = info.symbolize(inst.mustStacktrace(insnMap, funcTypes, breaks))
+	}
+
 	s.eventInstance(ctx, event.TypeInstanceDebug, &event.Instance{
 		Instance: inst.id,
-		Compiled: rebuild != nil,
+		Compiled: rebuild != nil || req.Op == api.DebugOpStep,
 	}, nil)
 
 	return res, nil
 }
 
-func (s *Server) Instances(ctx Context) (_ *api.Instances, err error) {
+// mustStepInstance sets temporary breakpoints and resumes the instance.  The
+// original breakpoints are restored when the instance stops.
+func (s *Server) mustStepInstance(ctx Context, inst *Instance, prog *program, progPolicy *ProgramPolicy) *api.DebugResponse {
+	breaks := inst.breakpoints()
+	insnMap, funcTypes := mustLoadInsnMap(s.ImageStorage, progPolicy, prog.image.NewModuleReader(), breaks)
+	code := mustLoadCodeSection(prog.image.NewModuleReader())
+
+	rebuild, config := inst.mustBeginStep(prog, insnMap, code, funcTypes, breaks, progPolicy)
+
+	started := false
+	defer func() {
+		if !started {
+			if err := s.endInstanceStep(ctx, inst, prog); err != nil {
+				s.eventFail(ctx, event.TypeFailInternal, internalFail(prog.id, "", inst.id, "debug", err), err)
+			}
+		}
+	}()
+
+	if rebuild != nil {
+		progImage, callMap := mustRebuildProgramImage(s.ImageStorage, progPolicy, prog.image.NewModuleReader(), config.Breakpoints)
+
+		if _, ok := rebuild.apply(progImage, config, callMap); !ok {
+			progImage.Close()
+			z.Panic(failrequest.Error(event.FailInstanceDebugState, "conflict"))
+		}
+	}
+
+	policy := new(instPolicy)
+	ctx = must(s.AccessPolicy.AuthorizeInstance(contextWithInternal(ctx), &policy.res, &policy.inst))
+
+	s.mustResumeInstance(ctx, inst, lock.GuardTagged(&s.mu, prog.ref), policy, new(api.ResumeOptions), nil)
+	started = true
+
+	return lock.GuardTagged(&inst.mu, func(lock instanceLock) *api.DebugResponse {
+		return inst.debugResponse(lock, prog.id)
+	})
+}
+
+// endInstanceStep restores the breakpoints which were replaced for a single
+// step.
+func (s *Server) endInstanceStep(ctx Context, inst *Instance, prog *program) (err error) {
+	if internal.DontPanic() {
+		defer func() { err = z.Error(recover()) }()
+	}
+
+	rebuild, config, progPolicy := inst.endStep(prog)
+	if rebuild == nil {
+		return nil
+	}
+
+	progImage, callMap := mustRebuildProgramImage(s.ImageStorage, progPolicy, prog.image.NewModuleReader(), config.Breakpoints)
+
+	if _, ok := rebuild.apply(progImage, config, callMap); !ok {
+		progImage.Close()
+		return failrequest.Error(event.FailInstanceDebugState, "conflict")
+	}
+	return nil
+}
+
+func (s *Server) Instances(ctx Context, opt *api.InstanceListOptions) (_ *api.Instances, err error) {
 	if internal.DontPanic() {
 		defer func() { err = z.Error(recover()) }()
 	}
@@ -1194,6 +1738,8 @@ func (s *Server) Instances(ctx Context) (_ *api.Instances, err error) {
 	ctx, end := s.startOp(ctx, api.OpInstanceList)
 	defer end(ctx)
 
+	opt = prepareInstanceListOptions(opt)
+
 	ctx = must(s.AccessPolicy.Authorize(ctx))
 
 	pri := principal.ContextID(ctx)
@@ -1220,17 +1766,90 @@ func (s *Server) Instances(ctx Context) (_ *api.Instances, err error) {
 	})
 
 	// Each instance has its own lock.
-	infos := &api.Instances{
-		Instances: make([]*api.InstanceInfo, 0, len(insts)),
-	}
+	matched := make([]*api.InstanceInfo, 0, len(insts))
 	for _, x := range insts {
 		if info := x.inst.info(x.progID); info != nil {
-			infos.Instances = append(infos.Instances, info)
+			if matchTags(info.Tags, opt.TagsAll, opt.TagsAny) && matchState(info.Status.State, opt.States) {
+				matched = append(matched, info)
+			}
 		}
 	}
+
+	infos := new(api.Instances)
+	infos.Instances, infos.NextCursor = paginate(matched, (*api.InstanceInfo).GetInstance, opt.Cursor, opt.Limit)
 	return infos, nil
 }
 
+// WatchInstances sends information about the account's instances when their
+// state or tags change.  Deleted instances are reported with nonexistent
+// state.  The channel is closed when the context is done or the server is
+// shut down.
+func (s *Server) WatchInstances(ctx Context) (_ <-chan *api.InstanceInfo, err error) {
+	if internal.DontPanic() {
+		defer func() { err = z.Error(recover()) }()
+	}
+
+	ctx, end := s.startOp(ctx, api.OpInstanceWatch)
+	defer end(ctx)
+
+	ctx = must(s.AccessPolicy.Authorize(ctx))
+
+	pri := principal.ContextID(ctx)
+	if pri == nil {
+		z.Panic(errAnonymous)
+	}
+
+	w := newInstanceWatcher()
+
+	var acc *account
+	lock.GuardTag(&s.mu, func(lock serverLock) {
+		if s.accounts != nil {
+			acc = s.ensureAccount(lock, pri)
+		}
+	})
+	if acc == nil || !acc.watchers.register(w) {
+		z.Panic(ErrServerClosed)
+	}
+
+	s.event(ctx, event.TypeInstanceWatch)
+
+	c := make(chan *api.InstanceInfo)
+
+	go func() {
+		defer close(c)
+		defer acc.watchers.unregister(w)
+
+		for {
+			select {
+			case <-w.wake:
+			case <-w.closed:
+				return
+			case <-ctx.Done():
+				return
+			}
+
+			for _, inst := range w.take() {
+				var progID string
+				lock.GuardTag(&s.mu, func(serverLock) {
+					if x, found := acc.instances[inst.id]; found && x.inst == inst {
+						progID = x.prog.id
+					}
+				})
+
+				select {
+				case c <- watchedInstanceInfo(inst, progID):
+				case <-w.closed:
+					return
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return c, nil
+}
+
 // ensureAccount may return nil.  It must not be called while the server is
 // shutting down.
 func (s *Server) ensureAccount(lock serverLock, pri *principal.ID) *account {
@@ -1270,7 +1889,7 @@ func (s *Server) unrefProgram(p **program) {
 
 // mustRegisterProgramRef with the server and an account.  Caller's program
 // reference is stolen (except on error).
-func (s *Server) mustRegisterProgramRef(ctx Context, prog *program, know *api.ModuleOptions) (redundant bool) {
+func (s *Server) mustRegisterProgramRef(ctx Context, res *ResourcePolicy, prog *program, know *api.ModuleOptions) (redundant bool) {
 	var pri *principal.ID
 
 	if know.Pin {
@@ -1279,18 +1898,35 @@ func (s *Server) mustRegisterProgramRef(ctx Context, prog *program, know *api.Mo
 			z.Panic(errAnonymous)
 		}
 
+		// Fail before storing the program if possible.
+		lock.GuardTag(&s.mu, func(lock serverLock) {
+			s.mustCheckProgramRefLimits(lock, pri, prog, res)
+		})
+
 		prog.mustEnsureStorage()
 	}
 
+	var pinned string
+	defer func() {
+		if pinned != "" {
+			s.storeModuleRecord(ctx, pri, pinned)
+		}
+	}()
+
 	lock := s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if know.Pin {
+		s.mustCheckProgramRefLimits(lock, pri, prog, res)
+	}
+
 	prog, redundant = s.mustMergeProgramRef(lock, prog)
 
 	if know.Pin {
 		// mergeProgramRef checked for shutdown, so the ensure methods are safe
 		// to call.
 		if s.ensureAccount(lock, pri).ensureProgramRef(lock, prog, know.Tags) {
+			pinned = prog.id // Stored after unlocking.
 			// TODO: move outside of critical section
 			s.eventModule(ctx, event.TypeModulePin, &event.Module{
 				Module:   prog.id,
@@ -1341,6 +1977,11 @@ func (s *Server) mustRunOrDeleteInstance(ctx Context, inst *Instance, prog *prog
 	if drive {
 		go s.driveInstance(ctx, inst, prog, function)
 		prog = nil
+		return
+	}
+
+	if err := s.storeInstanceRecord(ctx, inst, prog.id); err != nil {
+		s.eventFail(ctx, event.TypeFailInternal, internalFail(prog.id, function, inst.id, "inventory", err), err)
 	}
 }
 
@@ -1348,9 +1989,178 @@ func (s *Server) mustRunOrDeleteInstance(ctx Context, inst *Instance, prog *prog
 func (s *Server) driveInstance(ctx Context, inst *Instance, prog *program, function string) {
 	defer s.unrefProgram(&prog)
 
-	if nonexistent := inst.drive(ctx, prog, prog.id, function, &s.Config); nonexistent {
-		s.deleteNonexistentInstance(inst)
+	for {
+		nonexistent, paused, crash := inst.drive(ctx, prog, prog.id, function, &s.Config)
+		if crash != nil {
+			s.storeCrashSnapshot(ctx, inst, prog, crash)
+		}
+		if nonexistent {
+			s.deleteNonexistentInstance(inst)
+			return
+		}
+		if !paused || !s.checkpointInstance(ctx, inst, prog) {
+			break
+		}
+	}
+
+	if err := s.endInstanceStep(ctx, inst, prog); err != nil {
+		s.eventFail(ctx, event.TypeFailInternal, internalFail(prog.id, function, inst.id, "debug", err), err)
+	}
+
+	if err := s.storeInstanceRecord(ctx, inst, prog.id); err != nil {
+		s.eventFail(ctx, event.TypeFailInternal, internalFail(prog.id, function, inst.id, "inventory", err), err)
+	}
+
+	if wake, woken := inst.serviceWaker(); wake != nil {
+		go s.awaitServiceWake(ctx, inst, wake, woken)
+	}
+
+	if t, ok := inst.scheduledWakeup(); ok {
+		s.scheduleWakeup(inst, t)
+	}
+}
+
+// storeInstanceRecord of a stopped instance in inventory.  The record is
+// created when a persistent instance has been stored for the first time.
+func (s *Server) storeInstanceRecord(ctx Context, inst *Instance, module string) error {
+	record, inventoried := inst.stoppedInventoryRecord(module)
+	if record == nil {
+		return nil
+	}
+	if inventoried {
+		return s.Inventory.UpdateInstance(ctx, *inst.acc.ID, inst.id, record)
+	}
+	return s.Inventory.PutInstance(ctx, *inst.acc.ID, inst.id, record)
+}
+
+// checkpointInstance stores a paused instance and continues running it.  It
+// returns false if the instance was stopped instead.
+func (s *Server) checkpointInstance(ctx Context, inst *Instance, prog *program) bool {
+	record, inventoried, err := inst.storeCheckpoint(prog)
+	if err == nil {
+		if inventoried {
+			err = s.Inventory.UpdateInstance(ctx, *inst.acc.ID, inst.id, record)
+		} else {
+			err = s.Inventory.PutInstance(ctx, *inst.acc.ID, inst.id, record)
+		}
+	}
+	s.eventInstance(ctx, event.TypeInstanceCheckpoint, &event.Instance{
+		Instance: inst.id,
+	}, err)
+
+	proc, err := s.ProcessFactory.NewProcess(ctx)
+	if err != nil {
+		inst.stopPaused()
+		s.eventFail(ctx, event.TypeFailInternal, internalFail(prog.id, "", inst.id, "checkpoint", err), err)
+		return false
 	}
+
+	if err := inst.unpause(prog, proc); err != nil {
+		s.eventFail(ctx, event.TypeFailInternal, internalFail(prog.id, "", inst.id, "checkpoint", err), err)
+		return false
+	}
+
+	return true
+}
+
+// awaitServiceWake resumes a hibernated instance when its services have
+// something to deliver.
+func (s *Server) awaitServiceWake(ctx Context, inst *Instance, wake, woken <-chan struct{}) {
+	select {
+	case <-wake:
+	case <-woken:
+		return
+	}
+
+	if err := s.wakeInstance(ctx, inst); err != nil {
+		slog.WarnContext(ctx, "server: instance wakeup failed", "instance", inst.id, "err", err)
+	}
+}
+
+func (s *Server) wakeInstance(ctx Context, inst *Instance) (err error) {
+	if internal.DontPanic() {
+		defer func() { err = z.Error(recover()) }()
+	}
+
+	s.mustWakeInstance(ctx, inst)
+	return nil
+}
+
+// mustWakeInstance resumes a hibernated instance.  It does nothing if the
+// instance is not hibernated.
+func (s *Server) mustWakeInstance(ctx Context, inst *Instance) {
+	res, hibernated, retained := inst.hibernation()
+	if !hibernated {
+		return
+	}
+
+	prog := s.mustRefInstanceProgram(inst)
+	defer s.unrefProgram(&prog)
+
+	if !retained {
+		// Hibernated before the server was restarted.
+		s.mustResumeInstanceForOwner(ctx, inst, prog)
+		prog = nil
+		return
+	}
+
+	resident := inst.residentSize(prog)
+	inst.acc.mustReserveProc(&res, resident)
+
+	var services InstanceServices // Retained by instance.
+	proc, err := s.ProcessFactory.NewProcess(ctx)
+	if err != nil {
+		inst.acc.releaseProc(resident)
+		z.Panic(err)
+	}
+	defer closeInstanceResources(inst.acc, resident, &proc, &services)
+
+	if !inst.wake(proc, resident) {
+		return // Race condition.
+	}
+	proc = nil
+
+	// The instance outlives the request which caused the wakeup.
+	s.mustRunOrDeleteInstance(context.WithoutCancel(ctx), inst, prog, "")
+	prog = nil
+
+	s.eventInstance(ctx, event.TypeInstanceResume, &event.Instance{
+		Instance: inst.id,
+	}, nil)
+}
+
+// mustResumeInstanceForOwner resumes a suspended instance without a request
+// by the owner.  The services are allocated on behalf of the owner according
+// to the current policy; rate limit is not applied.  The program reference is
+// stolen.
+func (s *Server) mustResumeInstanceForOwner(ctx Context, inst *Instance, prog *program) {
+	defer s.unrefProgram(&prog)
+
+	policy := new(instPolicy)
+	ctx = contextWithInternal(principal.ContextWithID(ctx, inst.acc.ID))
+	ctx = must(s.AccessPolicy.AuthorizeInstance(ctx, &policy.res, &policy.inst))
+
+	// The instance outlives the request which caused the wakeup.
+	s.mustResumeInstance(context.WithoutCancel(ctx), inst, prog, policy, new(api.ResumeOptions), nil)
+	prog = nil
+
+	s.eventInstance(ctx, event.TypeInstanceResume, &event.Instance{
+		Instance: inst.id,
+	}, nil)
+}
+
+// mustRefInstanceProgram of an instance which is registered with an account.
+func (s *Server) mustRefInstanceProgram(inst *Instance) *program {
+	prog := lock.GuardTagged(&s.mu, func(lock serverLock) *program {
+		if x, found := inst.acc.instances[inst.id]; found && x.inst == inst {
+			return x.prog.ref(lock)
+		}
+		return nil
+	})
+	if prog == nil {
+		z.Panic(notfound.ErrInstance)
+	}
+	return prog
 }
 
 func (s *Server) mustGetInstance(ctx Context, instance string) *Instance {
@@ -1398,12 +2208,38 @@ func (s *Server) mustGetInstanceBorrowProgram(lock serverLock, pri *principal.ID
 	return x.inst, x.prog
 }
 
-func (s *Server) mustAllocateInstanceResources(ctx Context, policy *InstancePolicy) (*runtime.Process, InstanceServices) {
+// mustCheckProgramRefLimits of the principal's account.  The program doesn't
+// need to be canonical.
+func (s *Server) mustCheckProgramRefLimits(lock serverLock, pri *principal.ID, prog *program, res *ResourcePolicy) {
+	if s.programs == nil {
+		z.Panic(ErrServerClosed)
+	}
+	if existing := s.programs[prog.id]; existing != nil {
+		prog = existing
+	}
+
+	s.ensureAccount(lock, pri).mustCheckProgramRefLimits(lock, prog, res)
+}
+
+// mustAllocateInstanceResources reserves a process slot and resident size from
+// the account if acc is non-nil.  The reservation is released by
+// closeInstanceResources or when the instance stops.
+func (s *Server) mustAllocateInstanceResources(ctx Context, acc *account, resident int64, res *ResourcePolicy, policy *InstancePolicy) (*runtime.Process, InstanceServices) {
 	if policy.Services == nil {
 		z.Panic(PermissionDenied("no service policy"))
 	}
 
-	services := policy.Services(ctx)
+	if acc != nil {
+		acc.mustReserveProc(res, resident)
+	}
+	reserved := acc != nil
+	defer func() {
+		if reserved {
+			acc.releaseProc(resident)
+		}
+	}()
+
+	services := policy.Services(contextWithResourcePolicy(ctx, res))
 	defer func() {
 		if services != nil {
 			services.Close()
@@ -1411,6 +2247,7 @@ func (s *Server) mustAllocateInstanceResources(ctx Context, policy *InstancePoli
 	}()
 
 	proc := must(s.ProcessFactory.NewProcess(ctx))
+	reserved = false
 
 	ss := services
 	services = nil
@@ -1420,20 +2257,35 @@ func (s *Server) mustAllocateInstanceResources(ctx Context, policy *InstancePoli
 // mustRegisterProgramRefInstance with server, and an account if ref is true.
 // Caller's instance image is stolen (except on error).  Caller's program
 // reference is replaced with a reference to the canonical program object.
-func (s *Server) mustRegisterProgramRefInstance(ctx Context, acc *account, prog *program, instImage *image.Instance, policy *InstancePolicy, know *api.ModuleOptions, launch *api.LaunchOptions) (inst *Instance, canonicalProg *program, redundantProg bool) {
+// mustRegisterProgramRefInstance uses program's buffers if buffers is nil.
+func (s *Server) mustRegisterProgramRefInstance(ctx Context, acc *account, prog *program, instImage *image.Instance, buffers *snapshot.Buffers, res *ResourcePolicy, policy *InstancePolicy, know *api.ModuleOptions, launch *api.LaunchOptions) (inst *Instance, canonicalProg *program, redundantProg bool) {
+	if know.Pin || !launch.Transient || launch.CrashSnapshot {
+		if acc == nil {
+			z.Panic(errAnonymous)
+		}
+	}
+
+	if know.Pin {
+		// Fail before allocating resources if possible.
+		lock.GuardTag(&s.mu, func(lock serverLock) {
+			s.mustCheckProgramRefLimits(lock, acc.ID, prog, res)
+		})
+	}
+
+	replay := s.mustReadReplay(launch.Invoke)
+
 	var (
 		proc     *runtime.Process
+		resident int64
 		services InstanceServices
 	)
 	if !launch.Suspend && !instImage.Final() {
-		proc, services = s.mustAllocateInstanceResources(ctx, policy)
-		defer closeInstanceResources(&proc, &services)
+		resident = residentSize(prog.image, instImage)
+		proc, services = s.mustAllocateInstanceResources(ctx, acc, resident, res, policy)
+		defer closeInstanceResources(acc, resident, &proc, &services)
 	}
 
 	if know.Pin || !launch.Transient {
-		if acc == nil {
-			z.Panic(errAnonymous)
-		}
 		prog.mustEnsureStorage()
 	}
 
@@ -1442,6 +2294,13 @@ func (s *Server) mustRegisterProgramRefInstance(ctx Context, acc *account, prog
 		instance = makeInstanceID()
 	}
 
+	var pinned string
+	defer func() {
+		if pinned != "" {
+			s.storeModuleRecord(ctx, acc.ID, pinned)
+		}
+	}()
+
 	lock := s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -1450,11 +2309,23 @@ func (s *Server) mustRegisterProgramRefInstance(ctx Context, acc *account, prog
 			z.Panic(ErrServerClosed)
 		}
 		acc.mustCheckUniqueInstanceID(lock, instance)
+
+		if know.Pin {
+			s.mustCheckProgramRefLimits(lock, acc.ID, prog, res)
+		}
 	}
 
 	prog, redundantProg = s.mustMergeProgramRef(lock, prog)
 
-	inst = newInstance(instance, acc, launch.Transient, false, instImage, prog.buffers, proc, services, policy.TimeResolution, launch.Tags, s.openDebugLog(launch.Invoke))
+	if buffers == nil {
+		buffers = prog.buffers
+	}
+
+	crashSnapshot := acc != nil && (launch.CrashSnapshot || policy.CrashSnapshot)
+
+	inst = newInstance(instance, acc, launch.Transient, false, instImage, buffers, proc, resident, services, policy.TimeResolution, prepareTimeBudget(launch.Budget, policy), res, policy.IdleTimeout, launch.CheckpointInterval, crashSnapshot, launch.Tags, s.openDebugLog(launch.Invoke))
+	inst.record = s.openRecording(launch.Invoke)
+	inst.replay = replay
 	proc = nil
 	services = nil
 
@@ -1463,6 +2334,8 @@ func (s *Server) mustRegisterProgramRefInstance(ctx Context, acc *account, prog
 			// mergeProgramRef checked for shutdown, so ensureProgramRef is
 			// safe to call.
 			if acc.ensureProgramRef(lock, prog, know.Tags) {
+				pinned = prog.id // Stored after unlocking.
+
 				// TODO: move outside of critical section
 				s.eventModule(ctx, event.TypeModulePin, &event.Module{
 					Module:   prog.id,
@@ -1501,6 +2374,10 @@ func (s *Server) mustMergeProgramRef(lock serverLock, prog *program) (canonical
 		if s.programs == nil {
 			z.Panic(ErrServerClosed)
 		}
+		if _, found := s.collected[prog.id]; found {
+			delete(s.collected, prog.id)
+			prog.mustRestoreStorage()
+		}
 		s.programs[prog.id] = prog // Pass reference to map.
 		return prog, false
 
@@ -1550,6 +2427,20 @@ func mustPrepareLaunchOptions(opt *api.LaunchOptions) *api.LaunchOptions {
 	return opt
 }
 
+func prepareModuleListOptions(opt *api.ModuleListOptions) *api.ModuleListOptions {
+	if opt == nil {
+		return new(api.ModuleListOptions)
+	}
+	return opt
+}
+
+func prepareInstanceListOptions(opt *api.InstanceListOptions) *api.InstanceListOptions {
+	if opt == nil {
+		return new(api.InstanceListOptions)
+	}
+	return opt
+}
+
 func prepareResumeOptions(opt *api.ResumeOptions) *api.ResumeOptions {
 	if opt == nil {
 		return new(api.ResumeOptions)
@@ -1557,6 +2448,23 @@ func prepareResumeOptions(opt *api.ResumeOptions) *api.ResumeOptions {
 	return opt
 }
 
+// prepareTimeBudget caps the requested budget with the policy maximums.
+func prepareTimeBudget(b *api.TimeBudget, policy *InstancePolicy) timeBudget {
+	return timeBudget{
+		wallTime: capDuration(b.GetWallTime().AsDuration(), policy.MaxWallTime),
+		cpuTime:  capDuration(b.GetCpuTime().AsDuration(), policy.MaxCPUTime),
+		kill:     b.GetKill(),
+	}
+}
+
+// capDuration treats non-positive values as unlimited.
+func capDuration(d, limit time.Duration) time.Duration {
+	if d <= 0 || (limit > 0 && d > limit) {
+		return limit
+	}
+	return d
+}
+
 func prepareInstanceUpdate(opt *api.InstanceUpdate) *api.InstanceUpdate {
 	if opt == nil {
 		return new(api.InstanceUpdate)
@@ -1578,10 +2486,13 @@ func closeInstanceImage(p **image.Instance) {
 	}
 }
 
-func closeInstanceResources(proc **runtime.Process, services *InstanceServices) {
+func closeInstanceResources(acc *account, resident int64, proc **runtime.Process, services *InstanceServices) {
 	if *proc != nil {
 		(*proc).Close()
 		*proc = nil
+		if acc != nil {
+			acc.releaseProc(resident)
+		}
 	}
 	if *services != nil {
 		(*services).Close()
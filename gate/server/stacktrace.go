@@ -0,0 +1,175 @@
+// Copyright (c) 2026 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"bufio"
+	"debug/dwarf"
+	"io"
+	"sort"
+
+	"gate.computer/gate/server/api"
+	"gate.computer/wag/compile"
+	"gate.computer/wag/object/stack"
+	"gate.computer/wag/section"
+	"gate.computer/wag/wa"
+)
+
+// debugInfo contains symbol information extracted from a module's name and
+// DWARF custom sections.
+type debugInfo struct {
+	funcTypes []wa.FuncType
+	names     section.NameSection
+	lines     []lineEntry // Sorted by address.
+}
+
+type lineEntry struct {
+	addr   uint64
+	file   string
+	line   int
+	column int
+	end    bool
+}
+
+// mustLoadDebugInfo reads function types and custom sections from module.
+// Missing or malformed DWARF sections are ignored.
+func mustLoadDebugInfo(content io.Reader) *debugInfo {
+	info := new(debugInfo)
+
+	var custom section.CustomSections
+	config := compile.Config{
+		CustomSectionLoader: section.CustomLoader(map[string]section.CustomContentLoader{
+			"name":            info.names.Load,
+			".debug_abbrev":   custom.Load,
+			".debug_info":     custom.Load,
+			".debug_line":     custom.Load,
+			".debug_pubnames": custom.Load,
+			".debug_ranges":   custom.Load,
+			".debug_str":      custom.Load,
+		}),
+	}
+
+	r := compile.NewLoader(bufio.NewReader(content))
+
+	mod := must(compile.LoadInitialSections(&compile.ModuleConfig{Config: config}, r))
+	info.funcTypes = mod.FuncTypes()
+
+	for _, id := range []section.ID{section.Code, section.Data} {
+		if _, err := section.CopyStandardSection(io.Discard, r, id, config.CustomSectionLoader); err != nil {
+			if err == io.EOF {
+				return info
+			}
+			z.Panic(err)
+		}
+	}
+
+	if err := compile.LoadCustomSections(&config, r); err != nil && err != io.EOF {
+		z.Panic(err)
+	}
+
+	if custom.Sections[".debug_info"] != nil {
+		data, err := dwarf.New(
+			custom.Sections[".debug_abbrev"],
+			nil,
+			nil,
+			custom.Sections[".debug_info"],
+			custom.Sections[".debug_line"],
+			custom.Sections[".debug_pubnames"],
+			custom.Sections[".debug_ranges"],
+			custom.Sections[".debug_str"],
+		)
+		if err == nil {
+			info.lines = readLineEntries(data)
+		}
+	}
+
+	return info
+}
+
+func readLineEntries(data *dwarf.Data) (entries []lineEntry) {
+	r := data.Reader()
+
+	for {
+		e, err := r.Next()
+		if err != nil || e == nil {
+			break
+		}
+
+		if e.Tag == dwarf.TagCompileUnit {
+			lr, err := data.LineReader(e)
+			if err == nil && lr != nil {
+				for {
+					var le dwarf.LineEntry
+					if lr.Next(&le) != nil {
+						break
+					}
+
+					x := lineEntry{
+						addr:   le.Address,
+						line:   le.Line,
+						column: le.Column,
+						end:    le.EndSequence,
+					}
+					if le.File != nil {
+						x.file = le.File.Name
+					}
+					entries = append(entries, x)
+				}
+			}
+		}
+
+		r.SkipChildren()
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].addr < entries[j].addr
+	})
+	return
+}
+
+// findLine returns the line entry which covers the code offset.
+func (info *debugInfo) findLine(offset uint64) (lineEntry, bool) {
+	i := sort.Search(len(info.lines), func(i int) bool {
+		return info.lines[i].addr > offset
+	})
+	if i == 0 {
+		return lineEntry{}, false
+	}
+	e := info.lines[i-1]
+	if e.end {
+		return lineEntry{}, false
+	}
+	return e, true
+}
+
+// symbolize converts stack frames to API representation.
+func (info *debugInfo) symbolize(frames []stack.Frame) []*api.StackFrame {
+	if len(frames) == 0 {
+		return nil
+	}
+
+	result := make([]*api.StackFrame, 0, len(frames))
+
+	for _, f := range frames {
+		x := &api.StackFrame{
+			Function: uint32(f.FuncIndex),
+			Offset:   uint32(f.RetOffset),
+		}
+
+		if f.FuncIndex >= 0 && f.FuncIndex < len(info.names.FuncNames) {
+			x.Name = info.names.FuncNames[f.FuncIndex].FuncName
+		}
+
+		if e, found := info.findLine(uint64(f.RetOffset)); found {
+			x.File = e.file
+			x.Line = uint32(e.line)
+			x.Column = uint32(e.column)
+		}
+
+		result = append(result, x)
+	}
+
+	return result
+}
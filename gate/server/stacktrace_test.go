@@ -0,0 +1,57 @@
+// Copyright (c) 2026 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"testing"
+
+	"gate.computer/wag/object/stack"
+	"gate.computer/wag/section"
+)
+
+func TestDebugInfoSymbolize(t *testing.T) {
+	info := &debugInfo{
+		names: section.NameSection{
+			FuncNames: []section.FuncName{
+				{FuncName: "main"},
+				{FuncName: "helper"},
+			},
+		},
+		lines: []lineEntry{
+			{addr: 0x10, file: "main.c", line: 3, column: 5},
+			{addr: 0x20, file: "main.c", line: 4},
+			{addr: 0x30, end: true},
+			{addr: 0x40, file: "helper.c", line: 10},
+		},
+	}
+
+	frames := info.symbolize([]stack.Frame{
+		{FuncIndex: 1, RetOffset: 0x44},
+		{FuncIndex: 0, RetOffset: 0x18},
+		{FuncIndex: 0, RetOffset: 0x34},
+		{FuncIndex: 2, RetOffset: 0x8},
+	})
+
+	for i, x := range []struct {
+		name   string
+		file   string
+		line   uint32
+		column uint32
+	}{
+		{"helper", "helper.c", 10, 0},
+		{"main", "main.c", 3, 5},
+		{"main", "", 0, 0},
+		{"", "", 0, 0},
+	} {
+		f := frames[i]
+		if f.Name != x.name || f.File != x.file || f.Line != x.line || f.Column != x.column {
+			t.Errorf("frame %d: %v", i, f)
+		}
+	}
+
+	if info.symbolize(nil) != nil {
+		t.Error("empty stack")
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright (c) 2026 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"gate.computer/gate/image"
+	"gate.computer/gate/server/internal"
+	"gate.computer/gate/server/model"
+	pb "gate.computer/internal/pb/server"
+
+	. "import.name/type/context"
+)
+
+var (
+	ErrInstanceNotRecorded = errors.New("instance file has no inventory record")
+	ErrInstanceFileMissing = errors.New("instance record has no image file")
+	ErrModuleFileMissing   = errors.New("module has no program file")
+)
+
+// StorageIssue found by CheckStorage.
+type StorageIssue struct {
+	Principal string // Empty if unknown.
+	Module    string // Program file name.
+	Instance  string // Instance id, or storage key if it couldn't be parsed.
+	Err       error
+	Repair    string // Action taken, if any.
+}
+
+func (x *StorageIssue) String() string {
+	s := x.Err.Error()
+	if x.Principal != "" {
+		s = fmt.Sprintf("principal %s: %s", x.Principal, s)
+	}
+	if x.Instance != "" {
+		s = fmt.Sprintf("instance %s: %s", x.Instance, s)
+	}
+	if x.Module != "" {
+		s = fmt.Sprintf("module %s: %s", x.Module, s)
+	}
+	if x.Repair != "" {
+		s = fmt.Sprintf("%s (%s)", s, x.Repair)
+	}
+	return s
+}
+
+// CheckStorage verifies image files and compares them with inventory records.
+// Records without files can be found only if the inventory implements
+// model.InventoryLister.  If repair is set, corrupt and unrecorded files are
+// quarantined and records without files are removed.  Incremental snapshots
+// of corrupt programs are quarantined with them.  Programs which must be
+// recompiled are reported but not repaired; the server does it on startup.
+//
+// The storage must not be used by a server during the check.
+func CheckStorage(ctx Context, fs *image.Filesystem, inventory model.Inventory, repair bool) (issues []*StorageIssue, err error) {
+	if internal.DontPanic() {
+		defer func() { err = z.Error(recover()) }()
+	}
+
+	for _, p := range must(fs.Check()) {
+		x := &StorageIssue{Err: p.Err}
+		if p.Instance {
+			x.setInstanceKey(p.Name)
+		} else {
+			x.Module = p.Name
+		}
+
+		if repair && errors.Is(p.Err, image.ErrCorrupt) {
+			if p.Instance {
+				z.Check(fs.QuarantineInstance(p.Name))
+			} else {
+				z.Check(fs.QuarantineProgram(p.Name))
+			}
+			x.Repair = "quarantined"
+		}
+
+		issues = append(issues, x)
+	}
+
+	progs := make(map[string]bool)
+	for _, name := range must(fs.Programs()) {
+		progs[name] = true
+	}
+
+	keys := must(fs.Instances())
+	slices.Sort(keys)
+
+	insts := make(map[string]bool)
+	for _, key := range keys {
+		insts[key] = true
+	}
+
+	for _, key := range keys {
+		pri, instID, checkpoint, err := parseInstanceStorageKey(key)
+		if err != nil {
+			x := &StorageIssue{Instance: key, Err: err}
+			if repair {
+				z.Check(fs.QuarantineInstance(key))
+				x.Repair = "quarantined"
+			}
+			issues = append(issues, x)
+			continue
+		}
+
+		if checkpoint && insts[instanceStorageKey(pri, instID)] {
+			continue // Superseded; removed by server.
+		}
+
+		record := new(pb.Instance)
+		if !must(inventory.GetInstance(ctx, *pri, instID, record)) {
+			x := &StorageIssue{Principal: pri.String(), Instance: instID, Err: ErrInstanceNotRecorded}
+			if repair {
+				z.Check(fs.QuarantineInstance(key))
+				x.Repair = "quarantined"
+			}
+			issues = append(issues, x)
+			continue
+		}
+
+		if record.Module != "" && !progs[record.Module] {
+			issues = append(issues, &StorageIssue{
+				Principal: pri.String(),
+				Module:    record.Module,
+				Instance:  instID,
+				Err:       ErrModuleFileMissing,
+			})
+		}
+	}
+
+	lister, ok := inventory.(model.InventoryLister)
+	if !ok {
+		return issues, nil
+	}
+
+	for _, k := range must(lister.ListInstances(ctx)) {
+		if insts[instanceStorageKey(k.Principal, k.Key)] || insts[checkpointStorageKey(k.Principal, k.Key)] {
+			continue
+		}
+
+		x := &StorageIssue{Principal: k.Principal.String(), Instance: k.Key, Err: ErrInstanceFileMissing}
+		if repair {
+			z.Check(inventory.RemoveInstance(ctx, *k.Principal, k.Key))
+			x.Repair = "record removed"
+		}
+		issues = append(issues, x)
+	}
+
+	for _, k := range must(lister.ListModules(ctx)) {
+		if progs[k.Key] {
+			continue
+		}
+
+		x := &StorageIssue{Principal: k.Principal.String(), Module: k.Key, Err: ErrModuleFileMissing}
+		if repair {
+			z.Check(inventory.RemoveModule(ctx, *k.Principal, k.Key))
+			x.Repair = "record removed"
+		}
+		issues = append(issues, x)
+	}
+
+	return issues, nil
+}
+
+func (x *StorageIssue) setInstanceKey(key string) {
+	pri, instID, _, err := parseInstanceStorageKey(key)
+	if err != nil {
+		x.Instance = key
+		return
+	}
+	x.Principal = pri.String()
+	x.Instance = instID
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2026 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"log/slog"
+	"time"
+
+	"gate.computer/gate/server/internal"
+
+	. "import.name/type/context"
+)
+
+// wakeupRetryInterval is the delay before a failed scheduled resumption is
+// attempted again.
+const wakeupRetryInterval = time.Minute
+
+// scheduleWakeup resumes a suspended instance at the time requested by its
+// services.  A previously scheduled wakeup is canceled.  Nothing is done if
+// the instance has been resumed, stopped or rescheduled in the meantime, or if
+// the server has been shut down.
+func (s *Server) scheduleWakeup(inst *Instance, t time.Time) {
+	s.startWakeupTimer(inst, time.Until(t), t)
+}
+
+func (s *Server) startWakeupTimer(inst *Instance, d time.Duration, t time.Time) {
+	inst.setWakeupTimer(time.AfterFunc(d, func() {
+		s.wakeupInstance(inst, t)
+	}))
+}
+
+func (s *Server) wakeupInstance(inst *Instance, t time.Time) {
+	ctx := s.background
+	if ctx.Err() != nil {
+		return
+	}
+
+	if scheduled, ok := inst.scheduledWakeup(); !ok || !scheduled.Equal(t) {
+		return
+	}
+
+	if _, hibernated, _ := inst.hibernation(); hibernated {
+		if err := s.wakeInstance(ctx, inst); err != nil {
+			slog.WarnContext(ctx, "server: scheduled instance wakeup failed", "instance", inst.id, "err", err)
+		}
+		return
+	}
+
+	if err := s.resumeScheduledInstance(ctx, inst); err != nil {
+		slog.WarnContext(ctx, "server: scheduled instance resumption failed", "principal", inst.acc.ID, "instance", inst.id, "err", err, "retry", wakeupRetryInterval)
+		s.startWakeupTimer(inst, wakeupRetryInterval, t)
+	}
+}
+
+func (s *Server) resumeScheduledInstance(ctx Context, inst *Instance) (err error) {
+	if internal.DontPanic() {
+		defer func() { err = z.Error(recover()) }()
+	}
+
+	s.mustResumeInstanceForOwner(ctx, inst, s.mustRefInstanceProgram(inst))
+	return nil
+}
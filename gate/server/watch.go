@@ -0,0 +1,117 @@
+// Copyright (c) 2026 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"sync"
+
+	"gate.computer/gate/server/api"
+	"import.name/lock"
+)
+
+// instanceWatcher collects changed instances of an account.  Multiple changes
+// of an instance are coalesced while the watcher is lagging.
+type instanceWatcher struct {
+	wake   chan struct{} // Buffered.
+	closed chan struct{}
+
+	mu      sync.Mutex
+	pending []*Instance
+	queued  map[*Instance]struct{}
+}
+
+func newInstanceWatcher() *instanceWatcher {
+	return &instanceWatcher{
+		wake:   make(chan struct{}, 1),
+		closed: make(chan struct{}),
+		queued: make(map[*Instance]struct{}),
+	}
+}
+
+func (w *instanceWatcher) add(inst *Instance) {
+	lock.Guard(&w.mu, func() {
+		if _, found := w.queued[inst]; !found {
+			w.queued[inst] = struct{}{}
+			w.pending = append(w.pending, inst)
+		}
+	})
+
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (w *instanceWatcher) take() (insts []*Instance) {
+	lock.Guard(&w.mu, func() {
+		insts = w.pending
+		w.pending = nil
+		clear(w.queued)
+	})
+	return
+}
+
+// instanceWatchers of an account.
+type instanceWatchers struct {
+	mu       sync.Mutex
+	watchers map[*instanceWatcher]struct{}
+	closed   bool
+}
+
+// register returns false if the account has been shut down.
+func (ws *instanceWatchers) register(w *instanceWatcher) bool {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	if ws.closed {
+		return false
+	}
+	if ws.watchers == nil {
+		ws.watchers = make(map[*instanceWatcher]struct{})
+	}
+	ws.watchers[w] = struct{}{}
+	return true
+}
+
+func (ws *instanceWatchers) unregister(w *instanceWatcher) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	delete(ws.watchers, w)
+}
+
+// notify may be called while holding instance or server lock.
+func (ws *instanceWatchers) notify(inst *Instance) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	for w := range ws.watchers {
+		w.add(inst)
+	}
+}
+
+func (ws *instanceWatchers) close() {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	if !ws.closed {
+		ws.closed = true
+		for w := range ws.watchers {
+			close(w.closed)
+		}
+		ws.watchers = nil
+	}
+}
+
+// watchedInstanceInfo describes also nonexistent instances.
+func watchedInstanceInfo(inst *Instance, module string) *api.InstanceInfo {
+	if info := inst.info(module); info != nil {
+		return info
+	}
+	return &api.InstanceInfo{
+		Instance: inst.id,
+		Status:   new(api.Status),
+	}
+}
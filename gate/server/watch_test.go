@@ -0,0 +1,46 @@
+// Copyright (c) 2026 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"testing"
+)
+
+func TestInstanceWatcherCoalesce(t *testing.T) {
+	var ws instanceWatchers
+
+	w := newInstanceWatcher()
+	if !ws.register(w) {
+		t.Fatal("register failed")
+	}
+
+	a := &Instance{id: "a"}
+	b := &Instance{id: "b"}
+
+	ws.notify(a)
+	ws.notify(b)
+	ws.notify(a)
+
+	<-w.wake
+
+	if insts := w.take(); len(insts) != 2 || insts[0] != a || insts[1] != b {
+		t.Errorf("instances: %v", insts)
+	}
+	if insts := w.take(); len(insts) != 0 {
+		t.Errorf("instances after take: %v", insts)
+	}
+
+	ws.close()
+
+	select {
+	case <-w.closed:
+	default:
+		t.Error("watcher not closed")
+	}
+
+	if ws.register(newInstanceWatcher()) {
+		t.Error("registered after close")
+	}
+}
@@ -23,6 +23,11 @@ type Config struct {
 	Origins      []string // Value "*" causes Origin header to be ignored.
 	NonceChecker model.NonceChecker
 
+	// MigrationDestinations are servers (scheme and authority, e.g.
+	// "https://example.net") to which instances may be migrated.  The migrate
+	// action is not supported if there are none.
+	MigrationDestinations []string
+
 	// StartSpan within request context, ending when endSpan is called.  See
 	// gate.computer/gate/trace/tracelink.  The pattern string indicates the
 	// matching HTTP route handler.
@@ -14,6 +14,7 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -22,7 +23,9 @@ import (
 	"gate.computer/gate/server/api"
 	"gate.computer/gate/server/logging"
 	"gate.computer/gate/web"
+	"gate.computer/gate/web/migrate"
 	"gate.computer/internal/principal"
+	"gate.computer/internal/serverapi"
 	"github.com/gorilla/websocket"
 	"google.golang.org/protobuf/encoding/protojson"
 
@@ -167,6 +170,8 @@ func newHandler(pattern string, config *Config, scheme string, localAuthorizatio
 		ctx, end := contextWithSpanEnding(s.StartSpan(r, pattern))
 		defer end()
 
+		ctx = serverapi.ContextWithRemoteAddr(ctx, r.RemoteAddr)
+
 		if !s.anyOrigin {
 			if origin := r.Header.Get(web.HeaderOrigin); origin != "" {
 				mustBeAllowedOrigin(w, r, s, origin)
@@ -382,6 +387,7 @@ func initHandleInstances(mux *http.ServeMux, pattern string, s *webserver) {
 
 		mux.HandleFunc("GET "+pattern+"{$}", func(w http.ResponseWriter, r *http.Request) {
 			setAccessControlAllowHeaders(w, r, s, methods, headers)
+			handleGetInstances(w, r, s)
 		})
 
 		mux.HandleFunc("POST "+pattern+"{$}", func(w http.ResponseWriter, r *http.Request) {
@@ -398,7 +404,7 @@ func initHandleInstances(mux *http.ServeMux, pattern string, s *webserver) {
 	{
 		const (
 			methods = "GET, HEAD, OPTIONS, POST"
-			headers = web.HeaderAuthorization + ", " + web.HeaderContentType
+			headers = web.HeaderAuthorization + ", " + web.HeaderContentType + ", " + web.HeaderDestinationAuthorization
 		)
 
 		mux.HandleFunc("GET "+pattern+"{instance...}", func(w http.ResponseWriter, r *http.Request) {
@@ -494,11 +500,11 @@ func handleGetKnownModule(w http.ResponseWriter, r *http.Request, s *webserver,
 }
 
 func handlePostKnownModules(w http.ResponseWriter, r *http.Request, s *webserver) {
-	mustNotHaveQuery(w, r, s)
+	opt := mustParseModuleListOptions(w, r, s)
 	mustNotHaveContentType(w, r, s)
 	mustNotHaveContent(w, r, s)
 	mustAcceptJSON(w, r, s)
-	handleModuleList(w, r, s)
+	handleModuleList(w, r, s, opt)
 }
 
 func handlePutKnownModule(w http.ResponseWriter, r *http.Request, s *webserver, key string) {
@@ -696,12 +702,25 @@ func handleGetInstance(w http.ResponseWriter, r *http.Request, s *webserver, ins
 	}
 }
 
+func handleGetInstances(w http.ResponseWriter, r *http.Request, s *webserver) {
+	query := mustParseOptionalQuery(w, r, s)
+
+	switch popLastParam(w, r, s, query, web.ParamAction) {
+	case web.ActionWatch:
+		mustNotHaveParams(w, r, s, query)
+		handleInstanceWatchWebsocket(w, r, s)
+
+	default:
+		respondUnsupportedAction(w, r, s)
+	}
+}
+
 func handlePostInstances(w http.ResponseWriter, r *http.Request, s *webserver) {
-	mustNotHaveQuery(w, r, s)
+	opt := mustParseInstanceListOptions(w, r, s)
 	mustNotHaveContentType(w, r, s)
 	mustNotHaveContent(w, r, s)
 	mustAcceptJSON(w, r, s)
-	handleInstanceList(w, r, s)
+	handleInstanceList(w, r, s, opt)
 }
 
 func handlePostInstance(w http.ResponseWriter, r *http.Request, s *webserver, instance string) {
@@ -726,9 +745,10 @@ func handlePostInstance(w http.ResponseWriter, r *http.Request, s *webserver, in
 
 		case web.ActionResume:
 			function := mustPopOptionalLastFunctionParam(w, r, s, query)
+			module := popOptionalLastParam(w, r, s, query, web.ParamModule)
 			invoke := popOptionalLastLogParam(w, r, s, query)
 			mustNotHaveParams(w, r, s, query)
-			handleInstanceResume(w, r, s, function, instance, invoke)
+			handleInstanceResume(w, r, s, function, module, instance, invoke)
 			return
 
 		case web.ActionSnapshot:
@@ -751,16 +771,26 @@ func handlePostInstance(w http.ResponseWriter, r *http.Request, s *webserver, in
 			mustNotHaveParams(w, r, s, query)
 			handleInstanceDebug(w, r, s, instance)
 			return
+
+		case web.ActionMigrate:
+			destination := popLastParam(w, r, s, query, web.ParamDestination)
+			mustNotHaveParams(w, r, s, query)
+			handleInstanceMigrate(w, r, s, instance, destination)
+			return
 		}
 	}
 
 	var (
+		clone   bool
 		kill    bool
 		suspend bool
 		wait    bool
 	)
 	for _, a := range actions {
 		switch a {
+		case web.ActionClone:
+			clone = true
+
 		case web.ActionKill:
 			kill = true
 
@@ -777,6 +807,17 @@ func handlePostInstance(w http.ResponseWriter, r *http.Request, s *webserver, in
 	}
 
 	switch {
+	case clone && !kill && !wait:
+		function := mustPopOptionalLastFunctionParam(w, r, s, query)
+		cloneInstance := popOptionalLastParam(w, r, s, query, web.ParamInstance)
+		instTags := popOptionalParams(query, web.ParamInstanceTag)
+		invoke := popOptionalLastLogParam(w, r, s, query)
+		mustNotHaveParams(w, r, s, query)
+		handleInstanceClone(w, r, s, instance, function, cloneInstance, instTags, suspend, invoke)
+
+	case clone:
+		respondUnsupportedAction(w, r, s)
+
 	case kill && !suspend:
 		mustNotHaveParams(w, r, s, query)
 		handleInstanceWaiter(w, r, s, api.OpInstanceKill, killInstance, instance, wait)
@@ -804,12 +845,12 @@ func handleStatic(w http.ResponseWriter, r *http.Request, s *webserver, static *
 	w.Write(static.content)
 }
 
-func handleModuleList(w http.ResponseWriter, r *http.Request, s *webserver) {
+func handleModuleList(w http.ResponseWriter, r *http.Request, s *webserver, opt *api.ModuleListOptions) {
 	ctx := r.Context()
 	wr := &requestResponseWriter{w, r}
 	ctx = mustParseAuthorizationHeader(ctx, wr, s, true)
 
-	infos, err := s.Server.Modules(ctx)
+	infos, err := s.Server.Modules(ctx, opt)
 	if err != nil {
 		respondServerError(ctx, wr, s, "", "", "", "", err)
 		return
@@ -1219,12 +1260,12 @@ func handleLaunchUpload(w http.ResponseWriter, r *http.Request, s *webserver, pi
 	}
 }
 
-func handleInstanceList(w http.ResponseWriter, r *http.Request, s *webserver) {
+func handleInstanceList(w http.ResponseWriter, r *http.Request, s *webserver, opt *api.InstanceListOptions) {
 	ctx := r.Context()
 	wr := &requestResponseWriter{w, r}
 	ctx = mustParseAuthorizationHeader(ctx, wr, s, true)
 
-	instances, err := s.Server.Instances(ctx)
+	instances, err := s.Server.Instances(ctx, opt)
 	if err != nil {
 		respondServerError(ctx, wr, s, "", "", "", "", err)
 		return
@@ -1237,6 +1278,64 @@ func handleInstanceList(w http.ResponseWriter, r *http.Request, s *webserver) {
 	w.Write(content)
 }
 
+func handleInstanceWatchWebsocket(w http.ResponseWriter, r *http.Request, s *webserver) {
+	ctx := r.Context()
+
+	conn, err := websocketUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		reportProtocolError(ctx, s, err)
+		return
+	}
+	defer conn.Close()
+
+	var req web.Watch
+	conn.SetReadLimit(maxWebsocketRequestSize)
+
+	if err := conn.ReadJSON(&req); err != nil {
+		if e := net.Error(nil); errors.As(err, &e) {
+			reportNetworkError(ctx, s, err)
+		} else {
+			reportProtocolError(ctx, s, err)
+		}
+		return
+	}
+
+	ctx = mustParseAuthorization(ctx, websocketResponseWriter{conn}, s, req.Authorization, true)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	infos, err := s.Server.WatchInstances(ctx)
+	if err != nil {
+		respondServerError(ctx, websocketResponseWriter{conn}, s, "", "", "", "", err)
+		return
+	}
+
+	endContextSpan(ctx)
+	ctx = s.DetachTrace(ctx)
+
+	// Client isn't supposed to send anything more; cancel on disconnect.
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for info := range infos {
+		if err := conn.WriteMessage(websocket.TextMessage, must(protojson.Marshal(info))); err != nil {
+			reportNetworkError(ctx, s, err)
+			return
+		}
+	}
+
+	if ctx.Err() == nil {
+		conn.WriteMessage(websocket.CloseMessage, websocketNormalClosure)
+	}
+}
+
 func handleInstance(w http.ResponseWriter, r *http.Request, s *webserver, op api.Op, method instanceMethod, instance string) {
 	ctx := r.Context()
 	wr := &requestResponseWriter{w, r}
@@ -1302,7 +1401,7 @@ func handleInstanceWaiter(w http.ResponseWriter, r *http.Request, s *webserver,
 	w.WriteHeader(http.StatusOK)
 }
 
-func handleInstanceResume(w http.ResponseWriter, r *http.Request, s *webserver, function, instance string, invoke *api.InvokeOptions) {
+func handleInstanceResume(w http.ResponseWriter, r *http.Request, s *webserver, function, module, instance string, invoke *api.InvokeOptions) {
 	ctx := r.Context()
 	wr := &requestResponseWriter{w, r}
 	ctx = mustParseAuthorizationHeader(ctx, wr, s, true)
@@ -1310,13 +1409,40 @@ func handleInstanceResume(w http.ResponseWriter, r *http.Request, s *webserver,
 	resume := &api.ResumeOptions{
 		Invoke:   invoke,
 		Function: function,
+		Module:   module,
 	}
 
 	if _, err := s.Server.ResumeInstance(ctx, instance, resume); err != nil {
+		respondServerError(ctx, wr, s, "", module, function, instance, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func handleInstanceClone(w http.ResponseWriter, r *http.Request, s *webserver, instance, function, cloneInstance string, instTags []string, suspend bool, invoke *api.InvokeOptions) {
+	ctx := r.Context()
+	if cloneInstance != "" {
+		mustValidateInstanceIDInParam(w, r, s, cloneInstance)
+	}
+	wr := &requestResponseWriter{w, r}
+	ctx = mustParseAuthorizationHeader(ctx, wr, s, true)
+
+	launch := &api.LaunchOptions{
+		Invoke:   invoke,
+		Function: function,
+		Instance: cloneInstance,
+		Suspend:  suspend,
+		Tags:     instTags,
+	}
+
+	inst, err := s.Server.CloneInstance(ctx, instance, launch)
+	if err != nil {
 		respondServerError(ctx, wr, s, "", "", function, instance, err)
 		return
 	}
 
+	w.Header().Set(web.HeaderInstance, inst.ID())
 	w.WriteHeader(http.StatusOK)
 }
 
@@ -1470,6 +1596,38 @@ func handleInstanceSnapshot(w http.ResponseWriter, r *http.Request, s *webserver
 	w.WriteHeader(http.StatusCreated)
 }
 
+func handleInstanceMigrate(w http.ResponseWriter, r *http.Request, s *webserver, instance, destination string) {
+	ctx := r.Context()
+	if len(s.MigrationDestinations) == 0 {
+		respondUnsupportedAction(w, r, s)
+		return
+	}
+	wr := &requestResponseWriter{w, r}
+	ctx = mustParseAuthorizationHeader(ctx, wr, s, true)
+
+	destination = strings.TrimSuffix(destination, "/")
+	if !slices.ContainsFunc(s.MigrationDestinations, func(allowed string) bool {
+		return strings.TrimSuffix(allowed, "/") == destination
+	}) {
+		respondDestinationNotAllowed(w, r, s, destination)
+		return
+	}
+
+	dst := &migrate.Endpoint{
+		URL: destination,
+		Authorization: func() (string, error) {
+			return r.Header.Get(web.HeaderDestinationAuthorization), nil
+		},
+	}
+
+	if err := migrate.Push(ctx, s.Server, dst, instance); err != nil {
+		respondServerError(ctx, wr, s, "", "", "", instance, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
 func handleInstanceUpdate(w http.ResponseWriter, r *http.Request, s *webserver, instance string) {
 	ctx := r.Context()
 	mustHaveContentType(w, r, s, web.ContentTypeJSON)
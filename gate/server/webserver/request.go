@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 
 	"gate.computer/gate/server"
@@ -249,6 +250,70 @@ func mustPopOptionalLastFunctionParam(w http.ResponseWriter, r *http.Request, s
 	return value
 }
 
+func mustPopOptionalLastLimitParam(w http.ResponseWriter, r *http.Request, s *webserver, query url.Values) int32 {
+	value := popOptionalLastParam(w, r, s, query, web.ParamLimit)
+	if value == "" {
+		return 0
+	}
+
+	n, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || n <= 0 {
+		respondInvalidLimit(w, r, s, value)
+		panic(responded)
+	}
+	return int32(n)
+}
+
+func mustPopOptionalStateParams(w http.ResponseWriter, r *http.Request, s *webserver, query url.Values) (states []api.State) {
+	for _, value := range popOptionalParams(query, web.ParamState) {
+		var state api.State
+
+		switch value {
+		case web.StateRunning:
+			state = api.StateRunning
+		case web.StateSuspended:
+			state = api.StateSuspended
+		case web.StateHalted:
+			state = api.StateHalted
+		case web.StateTerminated:
+			state = api.StateTerminated
+		case web.StateKilled:
+			state = api.StateKilled
+		default:
+			respondInvalidState(w, r, s, value)
+			panic(responded)
+		}
+
+		states = append(states, state)
+	}
+	return
+}
+
+func mustParseModuleListOptions(w http.ResponseWriter, r *http.Request, s *webserver) *api.ModuleListOptions {
+	query := mustParseOptionalQuery(w, r, s)
+	opt := &api.ModuleListOptions{
+		TagsAll: popOptionalParams(query, web.ParamTag),
+		TagsAny: popOptionalParams(query, web.ParamAnyTag),
+		Cursor:  popOptionalLastParam(w, r, s, query, web.ParamCursor),
+		Limit:   mustPopOptionalLastLimitParam(w, r, s, query),
+	}
+	mustNotHaveParams(w, r, s, query)
+	return opt
+}
+
+func mustParseInstanceListOptions(w http.ResponseWriter, r *http.Request, s *webserver) *api.InstanceListOptions {
+	query := mustParseOptionalQuery(w, r, s)
+	opt := &api.InstanceListOptions{
+		TagsAll: popOptionalParams(query, web.ParamTag),
+		TagsAny: popOptionalParams(query, web.ParamAnyTag),
+		States:  mustPopOptionalStateParams(w, r, s, query),
+		Cursor:  popOptionalLastParam(w, r, s, query, web.ParamCursor),
+		Limit:   mustPopOptionalLastLimitParam(w, r, s, query),
+	}
+	mustNotHaveParams(w, r, s, query)
+	return opt
+}
+
 func mustNotHaveParams(w http.ResponseWriter, r *http.Request, s *webserver, query url.Values) {
 	if len(query) > 0 {
 		respondExcessQueryParams(w, r, s)
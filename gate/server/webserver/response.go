@@ -155,6 +155,18 @@ func respondInvalidFunction(w http.ResponseWriter, r *http.Request, s *webserver
 	reportProtocolError(r.Context(), s, err)
 }
 
+func respondInvalidLimit(w http.ResponseWriter, r *http.Request, s *webserver, value string) {
+	err := fmt.Errorf("invalid limit: %q", value)
+	respond(w, r, http.StatusBadRequest, err.Error())
+	reportProtocolError(r.Context(), s, err)
+}
+
+func respondInvalidState(w http.ResponseWriter, r *http.Request, s *webserver, value string) {
+	err := fmt.Errorf("invalid state: %q", value)
+	respond(w, r, http.StatusBadRequest, err.Error())
+	reportProtocolError(r.Context(), s, err)
+}
+
 func respondUnsupportedLog(w http.ResponseWriter, r *http.Request, s *webserver, value string) {
 	err := fmt.Errorf("unsupported log argument: %q", value)
 	respond(w, r, http.StatusNotImplemented, err.Error())
@@ -177,6 +189,11 @@ func respondUnsupportedAction(w http.ResponseWriter, r *http.Request, s *webserv
 	reportProtocolError(r.Context(), s, fmt.Errorf("bad action query: %s", r.URL.RawQuery))
 }
 
+func respondDestinationNotAllowed(w http.ResponseWriter, r *http.Request, s *webserver, destination string) {
+	respond(w, r, http.StatusForbidden, "migration destination not allowed")
+	reportProtocolError(r.Context(), s, fmt.Errorf("migration destination not allowed: %q", destination))
+}
+
 func respondUnsupportedFeature(w http.ResponseWriter, r *http.Request, s *webserver) {
 	w.Header().Set("Cache-Control", cacheControlStatic)
 	respond(w, r, http.StatusNotImplemented, "unsupported feature")
@@ -0,0 +1,756 @@
+// Copyright (c) 2026 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gate_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"gate.computer/gate/image"
+	"gate.computer/gate/packet"
+	"gate.computer/gate/principal"
+	"gate.computer/gate/runtime"
+	"gate.computer/gate/server"
+	"gate.computer/gate/server/api"
+	"gate.computer/gate/server/event"
+	"gate.computer/gate/service"
+	"gate.computer/gate/service/origin"
+	"gate.computer/gate/snapshot"
+	"gate.computer/gate/source"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	. "import.name/testing/mustr"
+	. "import.name/type/context"
+)
+
+func newAccessServer(t *testing.T, access *server.PublicAccess) *server.Server {
+	t.Helper()
+
+	return newInventoryServer(t, access, newTestInventory())
+}
+
+// newInventoryServer can be used to restart a server.
+func newInventoryServer(t *testing.T, access *server.PublicAccess, inventory *testInventory) *server.Server {
+	t.Helper()
+
+	return newStorageServer(t, access, inventory, nil)
+}
+
+// newStorageServer uses in-memory image storage if storage is nil.
+func newStorageServer(t *testing.T, access *server.PublicAccess, inventory *testInventory, storage image.Storage) *server.Server {
+	t.Helper()
+
+	return newConfigServer(t, newServerConfig(access, inventory, storage))
+}
+
+func newServerConfig(access *server.PublicAccess, inventory *testInventory, storage image.Storage) *server.Config {
+	if access.Services == nil {
+		access.Services = newServices()
+	}
+
+	return &server.Config{
+		UUID:           uuid.NewString(),
+		ImageStorage:   storage,
+		ProcessFactory: newExecutor(),
+		Inventory:      inventory,
+		AccessPolicy:   access,
+		ModuleSources:  map[string]source.Source{"/test": helloSource{}},
+		SourceCache:    newTestSourceCache(),
+		OpenDebugLog:   openDebugLog,
+	}
+}
+
+func newConfigServer(t *testing.T, config *server.Config) *server.Server {
+	t.Helper()
+
+	s := Must(t, R(server.New(context.Background(), config)))
+	t.Cleanup(func() { s.Shutdown(context.Background()) })
+	return s
+}
+
+// newFilesystemStorage can be shared by restarted servers.
+func newFilesystemStorage(t *testing.T) *image.Filesystem {
+	t.Helper()
+
+	fs := Must(t, R(image.NewFilesystem(t.TempDir())))
+	t.Cleanup(func() { fs.Close() })
+	return fs
+}
+
+func newModuleUpload(wasm []byte) *api.ModuleUpload {
+	return &api.ModuleUpload{
+		Stream: io.NopCloser(bytes.NewReader(wasm)),
+		Length: int64(len(wasm)),
+		Hash:   sha256hex(wasm),
+	}
+}
+
+func assertFailType(t *testing.T, err error, expect event.FailType) {
+	t.Helper()
+
+	var e interface{ FailType() event.FailType }
+	if assert.True(t, errors.As(err, &e), "error: %v", err) {
+		assert.Equal(t, e.FailType(), expect)
+	}
+}
+
+func localContext() Context {
+	return principal.ContextWithLocalID(context.Background())
+}
+
+func TestResourceLimitStorage(t *testing.T) {
+	access := server.NewPublicAccess(nil)
+	access.TotalStorageSize = len(wasmHello) - 1
+	s := newAccessServer(t, access)
+	ctx := localContext()
+
+	_, err := s.UploadModule(ctx, newModuleUpload(wasmHello), &api.ModuleOptions{Pin: true})
+	assertFailType(t, err, event.FailResourceLimit)
+
+	Must(t, R(s.UploadModule(ctx, newModuleUpload(wasmNop), &api.ModuleOptions{Pin: true})))
+}
+
+func TestResourceLimitProcs(t *testing.T) {
+	access := server.NewPublicAccess(nil)
+	access.MaxProcs = 1
+	s := newAccessServer(t, access)
+	ctx := localContext()
+
+	launch := &api.LaunchOptions{Function: "loop", Transient: true}
+
+	_, inst, err := s.UploadModuleInstance(ctx, newModuleUpload(wasmSuspend), nil, launch)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, err = s.UploadModuleInstance(ctx, newModuleUpload(wasmSuspend), nil, launch)
+	assertFailType(t, err, event.FailResourceLimit)
+
+	Must(t, R(s.KillInstance(ctx, inst.ID())))
+	Must(t, R(s.WaitInstance(ctx, inst.ID())))
+
+	_, inst, err = s.UploadModuleInstance(ctx, newModuleUpload(wasmSuspend), nil, launch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	Must(t, R(s.KillInstance(ctx, inst.ID())))
+}
+
+func TestResourceLimitResident(t *testing.T) {
+	access := server.NewPublicAccess(nil)
+	access.TotalResidentSize = 4096
+	s := newAccessServer(t, access)
+	ctx := localContext()
+
+	_, _, err := s.UploadModuleInstance(ctx, newModuleUpload(wasmSuspend), nil, &api.LaunchOptions{Function: "loop", Transient: true})
+	assertFailType(t, err, event.FailResourceLimit)
+}
+
+func TestTimeBudget(t *testing.T) {
+	s := newAccessServer(t, server.NewPublicAccess(nil))
+	ctx := localContext()
+
+	for _, x := range []struct {
+		name       string
+		budget     *api.TimeBudget
+		checkpoint time.Duration
+		state      api.State
+	}{
+		{"WallTime", &api.TimeBudget{WallTime: durationpb.New(time.Second / 2)}, 0, api.StateSuspended},
+		{"WallTimeKill", &api.TimeBudget{WallTime: durationpb.New(time.Second / 2), Kill: true}, 0, api.StateKilled},
+		{"CPUTime", &api.TimeBudget{CpuTime: durationpb.New(time.Second)}, 0, api.StateSuspended},
+		{"WallTimeCheckpoint", &api.TimeBudget{WallTime: durationpb.New(time.Second)}, time.Second / 5, api.StateSuspended},
+		{"CPUTimeCheckpoint", &api.TimeBudget{CpuTime: durationpb.New(2 * time.Second)}, time.Second / 2, api.StateSuspended},
+	} {
+		t.Run(x.name, func(t *testing.T) {
+			launch := &api.LaunchOptions{
+				Function: "loop",
+				Budget:   x.budget,
+			}
+			if x.checkpoint > 0 {
+				launch.CheckpointInterval = durationpb.New(x.checkpoint)
+			}
+
+			_, inst, err := s.UploadModuleInstance(ctx, newModuleUpload(wasmSuspend), nil, launch)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer s.DeleteInstance(ctx, inst.ID())
+
+			// Checkpoints must not renew the budget.
+			ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+			defer cancel()
+
+			status := Must(t, R(s.WaitInstance(ctx, inst.ID())))
+			assert.Equal(t, status.State, x.state)
+			assert.Equal(t, status.Cause, api.CauseTimeout)
+		})
+	}
+}
+
+func TestCloneInstance(t *testing.T) {
+	s := newAccessServer(t, server.NewPublicAccess(nil))
+	ctx := localContext()
+
+	_, running, err := s.UploadModuleInstance(ctx, newModuleUpload(wasmSuspend), nil, &api.LaunchOptions{Function: "loop"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Running", func(t *testing.T) {
+		_, err := s.CloneInstance(ctx, running.ID(), nil)
+		assertFailType(t, err, event.FailInstanceStatus)
+	})
+
+	time.Sleep(time.Second / 3)
+	Must(t, R(s.SuspendInstance(ctx, running.ID())))
+	assert.Equal(t, running.Wait(ctx).State, api.StateSuspended)
+
+	t.Run("Suspended", func(t *testing.T) {
+		_, err := s.CloneInstance(ctx, running.ID(), &api.LaunchOptions{Function: "loop"})
+		assertFailType(t, err, event.FailInstanceStatus)
+
+		clone := Must(t, R(s.CloneInstance(ctx, running.ID(), nil)))
+		assert.NotEqual(t, clone.ID(), running.ID())
+		assert.Equal(t, clone.Status().State, api.StateRunning)
+
+		time.Sleep(time.Second / 3)
+		Must(t, R(s.SuspendInstance(ctx, clone.ID())))
+		assert.Equal(t, clone.Wait(ctx).State, api.StateSuspended)
+
+		info := Must(t, R(s.InstanceInfo(ctx, running.ID())))
+		assert.Equal(t, info.Status.State, api.StateSuspended)
+	})
+
+	t.Run("Halted", func(t *testing.T) {
+		_, halted, err := s.UploadModuleInstance(ctx, newModuleUpload(wasmHelloDebug), nil, &api.LaunchOptions{Function: "debug"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, halted.Wait(ctx).State, api.StateHalted)
+
+		_, err = s.CloneInstance(ctx, halted.ID(), nil)
+		assertFailType(t, err, event.FailInstanceStatus)
+
+		clone := Must(t, R(s.CloneInstance(ctx, halted.ID(), &api.LaunchOptions{Function: "debug", Transient: true})))
+		assert.Equal(t, clone.Wait(ctx).State, api.StateTerminated)
+
+		clone = Must(t, R(s.CloneInstance(ctx, halted.ID(), &api.LaunchOptions{Suspend: true})))
+		assert.Equal(t, clone.Wait(ctx).State, api.StateHalted)
+	})
+}
+
+type testWaker chan struct{}
+
+func (w testWaker) Wake() <-chan struct{} { return w }
+func (w testWaker) Close() error          { return nil }
+
+func TestHibernation(t *testing.T) {
+	registry := new(service.Registry)
+	if err := service.Init(context.Background(), registry); err != nil {
+		t.Fatal(err)
+	}
+
+	waker := make(testWaker, 1)
+
+	access := server.NewPublicAccess(func(ctx Context) server.InstanceServices {
+		connector := origin.New(nil)
+		r := registry.Clone()
+		r.MustRegister(connector)
+		return server.NewWakingInstanceServices(connector, r, waker)
+	})
+	access.IdleTimeout = time.Second / 4
+
+	inventory := newTestInventory()
+	storage := newFilesystemStorage(t)
+	s := newStorageServer(t, access, inventory, storage)
+	ctx := localContext()
+
+	_, inst, err := s.UploadModuleInstance(ctx, newModuleUpload(wasmSuspend), &api.ModuleOptions{Pin: true}, &api.LaunchOptions{Function: "loop"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := inst.ID()
+
+	awaitHibernation := func(s *server.Server) *api.InstanceInfo {
+		t.Helper()
+
+		status := Must(t, R(s.WaitInstance(ctx, id)))
+		assert.Equal(t, status.State, api.StateSuspended)
+
+		info := Must(t, R(s.InstanceInfo(ctx, id)))
+		assert.True(t, info.Hibernated)
+		return info
+	}
+
+	info := awaitHibernation(s)
+
+	t.Run("Service", func(t *testing.T) {
+		waker <- struct{}{}
+
+		for deadline := time.Now().Add(5 * time.Second); ; {
+			x := Must(t, R(s.InstanceInfo(ctx, id)))
+			if !x.Resumed.AsTime().Equal(info.Resumed.AsTime()) {
+				assert.False(t, x.Hibernated)
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatal("instance was not woken")
+			}
+			time.Sleep(time.Second / 100)
+		}
+
+		awaitHibernation(s)
+	})
+
+	t.Run("Connection", func(t *testing.T) {
+		if _, _, err := s.InstanceConnection(ctx, id); err != nil {
+			t.Fatal(err)
+		}
+
+		x := Must(t, R(s.InstanceInfo(ctx, id)))
+		assert.Equal(t, x.Status.State, api.StateRunning)
+		assert.False(t, x.Hibernated)
+
+		awaitHibernation(s)
+	})
+
+	t.Run("Restart", func(t *testing.T) {
+		if err := s.Shutdown(ctx); err != nil {
+			t.Fatal(err)
+		}
+		s := newStorageServer(t, access, inventory, storage)
+
+		x := Must(t, R(s.InstanceInfo(ctx, id)))
+		assert.Equal(t, x.Status.State, api.StateSuspended)
+		assert.True(t, x.Hibernated)
+
+		if _, _, err := s.InstanceConnection(ctx, id); err != nil {
+			t.Fatal(err)
+		}
+
+		x = Must(t, R(s.InstanceInfo(ctx, id)))
+		assert.Equal(t, x.Status.State, api.StateRunning)
+		assert.False(t, x.Hibernated)
+
+		Must(t, R(s.KillInstance(ctx, id)))
+		Must(t, R(s.WaitInstance(ctx, id)))
+	})
+}
+
+func TestCheckpointRestart(t *testing.T) {
+	root := t.TempDir()
+	storage := Must(t, R(image.NewFilesystem(root)))
+	defer storage.Close()
+
+	inventory := newTestInventory()
+	checkpoints := make(chan string, 1)
+
+	config := newServerConfig(server.NewPublicAccess(nil), inventory, storage)
+	config.AddEvent = func(ctx Context, ev *event.Event, err error) {
+		if ev.Type == event.TypeInstanceCheckpoint && err == nil {
+			select {
+			case checkpoints <- ev.GetInstance().GetInstance():
+			default:
+			}
+		}
+	}
+	s := newConfigServer(t, config)
+	ctx := localContext()
+
+	_, inst, err := s.UploadModuleInstance(ctx, newModuleUpload(wasmSuspend), nil, &api.LaunchOptions{
+		Function:           "loop",
+		CheckpointInterval: durationpb.New(time.Second / 4),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := inst.ID()
+
+	select {
+	case x := <-checkpoints:
+		assert.Equal(t, x, id)
+	case <-time.After(10 * time.Second):
+		t.Fatal("instance was not checkpointed")
+	}
+
+	// Simulate a crash by restarting on a copy of the state while the
+	// instance is still running.
+	crashInventory := inventory.clone()
+	crashRoot := t.TempDir()
+	if err := os.CopyFS(crashRoot, os.DirFS(root)); err != nil {
+		t.Fatal(err)
+	}
+	crashStorage := Must(t, R(image.NewFilesystem(crashRoot)))
+	defer crashStorage.Close()
+
+	s = newStorageServer(t, server.NewPublicAccess(nil), crashInventory, crashStorage)
+
+	info := Must(t, R(s.InstanceInfo(ctx, id)))
+	assert.Equal(t, info.Status.State, api.StateSuspended)
+
+	Must(t, R(s.ResumeInstance(ctx, id, nil)))
+
+	info = Must(t, R(s.InstanceInfo(ctx, id)))
+	assert.Equal(t, info.Status.State, api.StateRunning)
+
+	Must(t, R(s.KillInstance(ctx, id)))
+	Must(t, R(s.WaitInstance(ctx, id)))
+}
+
+// wakeupRegistry requests a wakeup when an instance is suspended.
+type wakeupRegistry struct {
+	runtime.ServiceRegistry
+	at time.Time
+}
+
+func (r wakeupRegistry) CreateServer(ctx Context, config runtime.ServiceConfig, initial []*snapshot.Service, send chan<- packet.Thunk) (runtime.InstanceServer, []runtime.ServiceState, <-chan error, error) {
+	x, states, done, err := r.ServiceRegistry.CreateServer(ctx, config, initial, send)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return wakeupServer{x, r.at}, states, done, nil
+}
+
+type wakeupServer struct {
+	runtime.InstanceServer
+	at time.Time
+}
+
+func (s wakeupServer) Shutdown(ctx Context, suspend bool) ([]*snapshot.Service, error) {
+	if suspend {
+		service.RequestWakeup(ctx, s.at)
+	}
+	return s.InstanceServer.Shutdown(ctx, suspend)
+}
+
+func TestScheduledWakeupRestart(t *testing.T) {
+	registry := new(service.Registry)
+	if err := service.Init(context.Background(), registry); err != nil {
+		t.Fatal(err)
+	}
+
+	wakeup := time.Now().Add(2 * time.Second)
+
+	access := server.NewPublicAccess(func(ctx Context) server.InstanceServices {
+		connector := origin.New(nil)
+		r := registry.Clone()
+		r.MustRegister(connector)
+		return server.NewInstanceServices(connector, wakeupRegistry{r, wakeup})
+	})
+
+	inventory := newTestInventory()
+	storage := newFilesystemStorage(t)
+	s := newStorageServer(t, access, inventory, storage)
+	ctx := localContext()
+
+	_, inst, err := s.UploadModuleInstance(ctx, newModuleUpload(wasmSuspend), nil, &api.LaunchOptions{Function: "loop"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := inst.ID()
+
+	time.Sleep(time.Second / 3)
+	Must(t, R(s.SuspendInstance(ctx, id)))
+	assert.Equal(t, inst.Wait(ctx).State, api.StateSuspended)
+
+	if err := s.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+	s = newStorageServer(t, access, inventory, storage)
+
+	info := Must(t, R(s.InstanceInfo(ctx, id)))
+	assert.Equal(t, info.Status.State, api.StateSuspended)
+
+	for deadline := wakeup.Add(5 * time.Second); ; {
+		info := Must(t, R(s.InstanceInfo(ctx, id)))
+		if info.Status.State == api.StateRunning {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("instance was not woken: %v", info.Status)
+		}
+		time.Sleep(time.Second / 100)
+	}
+
+	Must(t, R(s.KillInstance(ctx, id)))
+	Must(t, R(s.WaitInstance(ctx, id)))
+}
+
+func TestDebugStacktrace(t *testing.T) {
+	s := newAccessServer(t, server.NewPublicAccess(nil))
+	ctx := localContext()
+
+	_, inst, err := s.UploadModuleInstance(ctx, newModuleUpload(wasmSuspend), nil, &api.LaunchOptions{Function: "loop"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.DeleteInstance(ctx, inst.ID())
+
+	time.Sleep(time.Second / 3)
+	Must(t, R(s.SuspendInstance(ctx, inst.ID())))
+	assert.Equal(t, inst.Wait(ctx).State, api.StateSuspended)
+
+	res := Must(t, R(s.DebugInstance(ctx, inst.ID(), &api.DebugRequest{Op: api.DebugOpReadStacktrace})))
+	if len(res.Stacktrace) == 0 {
+		t.Fatal("no stack frames")
+	}
+
+	var (
+		names []string
+		lines int
+	)
+	for _, f := range res.Stacktrace {
+		names = append(names, f.Name)
+		if f.File != "" {
+			assert.True(t, strings.HasSuffix(f.File, "suspend.cpp"), f.File)
+			assert.NotZero(t, f.Line)
+			lines++
+		}
+	}
+	assert.Contains(t, names, "loop")
+	assert.NotZero(t, lines, "no frames with source location")
+}
+
+func TestCrashSnapshot(t *testing.T) {
+	s := newAccessServer(t, server.NewPublicAccess(nil))
+	ctx := localContext()
+
+	for _, transient := range []bool{true, false} {
+		name := "Persistent"
+		if transient {
+			name = "Transient"
+		}
+
+		t.Run(name, func(t *testing.T) {
+			launch := &api.LaunchOptions{
+				Function:      "crash",
+				Transient:     transient,
+				CrashSnapshot: true,
+			}
+
+			_, inst, err := s.UploadModuleInstance(ctx, newModuleUpload(wasmTrap), nil, launch)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !transient {
+				defer s.DeleteInstance(ctx, inst.ID())
+			}
+
+			status := inst.Wait(ctx)
+			assert.Equal(t, status.State, api.StateKilled)
+			assert.Equal(t, status.Cause, api.CauseUnreachable)
+
+			// The snapshot is stored after the instance has stopped.
+			tag := "instance=" + inst.ID()
+			deadline := time.Now().Add(5 * time.Second)
+			for {
+				for _, m := range Must(t, R(s.Modules(ctx, nil))).Modules {
+					for _, x := range m.Tags {
+						if x == tag {
+							assert.Contains(t, m.Tags, "cause=UNREACHABLE")
+							return
+						}
+					}
+				}
+				if time.Now().After(deadline) {
+					t.Fatal("crash snapshot not found")
+				}
+				time.Sleep(time.Second / 20)
+			}
+		})
+	}
+}
+
+func TestSnapshotLineage(t *testing.T) {
+	s := newAccessServer(t, server.NewPublicAccess(nil))
+	ctx := localContext()
+
+	_, inst, err := s.UploadModuleInstance(ctx, newModuleUpload(wasmSuspend), nil, &api.LaunchOptions{Function: "loop"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.DeleteInstance(ctx, inst.ID())
+
+	time.Sleep(time.Second / 3)
+	Must(t, R(s.SuspendInstance(ctx, inst.ID())))
+	assert.Equal(t, inst.Wait(ctx).State, api.StateSuspended)
+
+	module := Must(t, R(s.Snapshot(ctx, inst.ID(), &api.ModuleOptions{Pin: true})))
+
+	info := Must(t, R(s.ModuleInfo(ctx, module)))
+	if assert.NotNil(t, info.Lineage) {
+		assert.Equal(t, info.Lineage.Parent, hashSuspend)
+		assert.Equal(t, info.Lineage.Instance, inst.ID())
+		assert.NotNil(t, info.Lineage.Created)
+	}
+
+	for _, m := range Must(t, R(s.Modules(ctx, nil))).Modules {
+		if m.Module == module {
+			assert.Equal(t, m.Lineage.GetParent(), hashSuspend)
+		}
+	}
+}
+
+func TestRollbackInstance(t *testing.T) {
+	s := newAccessServer(t, server.NewPublicAccess(nil))
+	ctx := localContext()
+
+	_, inst, err := s.UploadModuleInstance(ctx, newModuleUpload(wasmSuspend), nil, &api.LaunchOptions{Function: "loop"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.DeleteInstance(ctx, inst.ID())
+
+	time.Sleep(time.Second / 3)
+	Must(t, R(s.SuspendInstance(ctx, inst.ID())))
+	assert.Equal(t, inst.Wait(ctx).State, api.StateSuspended)
+
+	module := Must(t, R(s.Snapshot(ctx, inst.ID(), &api.ModuleOptions{Pin: true})))
+
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := s.ResumeInstance(ctx, inst.ID(), &api.ResumeOptions{Module: hashNop})
+		assert.Error(t, err)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := s.ResumeInstance(ctx, inst.ID(), &api.ResumeOptions{Module: module, Function: "loop"})
+		assertFailType(t, err, event.FailInstanceStatus)
+
+		// The instance must not have been rolled back.
+		info := Must(t, R(s.InstanceInfo(ctx, inst.ID())))
+		assert.Equal(t, info.Module, hashSuspend)
+		assert.Equal(t, info.Status.State, api.StateSuspended)
+	})
+
+	t.Run("Resume", func(t *testing.T) {
+		Must(t, R(s.ResumeInstance(ctx, inst.ID(), &api.ResumeOptions{Module: module})))
+
+		info := Must(t, R(s.InstanceInfo(ctx, inst.ID())))
+		assert.Equal(t, info.Module, module)
+		assert.Equal(t, info.Status.State, api.StateRunning)
+
+		time.Sleep(time.Second / 3)
+		Must(t, R(s.SuspendInstance(ctx, inst.ID())))
+		assert.Equal(t, inst.Wait(ctx).State, api.StateSuspended)
+	})
+}
+
+func TestCollectRestoredPins(t *testing.T) {
+	access := server.NewPublicAccess(nil)
+	inventory := newTestInventory()
+	storage := newFilesystemStorage(t)
+	s := newStorageServer(t, access, inventory, storage)
+	ctx := localContext()
+
+	module := Must(t, R(s.UploadModule(ctx, newModuleUpload(wasmNop), &api.ModuleOptions{Pin: true, Tags: []string{"pinned"}})))
+
+	if err := s.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+	s = newStorageServer(t, access, inventory, storage)
+
+	info := Must(t, R(s.ModuleInfo(ctx, module)))
+	assert.Equal(t, info.Tags, []string{"pinned"})
+
+	report := Must(t, R(s.Collect(ctx, &server.CollectConfig{Programs: true, DryRun: true})))
+	assert.NotContains(t, report.Modules, module)
+}
+
+func TestCollectUnrestoredInstancePrograms(t *testing.T) {
+	access := server.NewPublicAccess(nil)
+	inventory := newTestInventory()
+	storage := newFilesystemStorage(t)
+	s := newStorageServer(t, access, inventory, storage)
+	ctx := localContext()
+
+	_, inst, err := s.UploadModuleInstance(ctx, newModuleUpload(wasmSuspend), nil, &api.LaunchOptions{Function: "loop"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(time.Second / 3)
+	Must(t, R(s.SuspendInstance(ctx, inst.ID())))
+	assert.Equal(t, inst.Wait(ctx).State, api.StateSuspended)
+
+	if err := s.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// The instance will not be restored without inventory record.
+	for _, k := range Must(t, R(inventory.ListInstances(ctx))) {
+		if err := inventory.RemoveInstance(ctx, *k.Principal, k.Key); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s = newStorageServer(t, access, inventory, storage)
+
+	report := Must(t, R(s.Collect(ctx, &server.CollectConfig{Programs: true})))
+	assert.Empty(t, report.Modules)
+	assert.Contains(t, Must(t, R(storage.Programs())), hashSuspend)
+}
+
+func TestCheckStorageIncremental(t *testing.T) {
+	root := t.TempDir()
+	fs := Must(t, R(image.NewFilesystem(root)))
+	defer fs.Close()
+
+	access := server.NewPublicAccess(nil)
+	access.IncrementalSnapshot = true
+	inventory := newTestInventory()
+	s := newStorageServer(t, access, inventory, fs)
+	ctx := localContext()
+
+	_, inst, err := s.UploadModuleInstance(ctx, newModuleUpload(wasmSuspend), nil, &api.LaunchOptions{Function: "loop"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(time.Second / 3)
+	Must(t, R(s.SuspendInstance(ctx, inst.ID())))
+	assert.Equal(t, inst.Wait(ctx).State, api.StateSuspended)
+
+	parent := Must(t, R(s.Snapshot(ctx, inst.ID(), &api.ModuleOptions{Pin: true})))
+	child := Must(t, R(s.Snapshot(ctx, inst.ID(), &api.ModuleOptions{Pin: true})))
+	assert.NotEqual(t, parent, child)
+
+	if err := s.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	filename := path.Join(root, "program", parent)
+	if err := os.Remove(filename); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filename, []byte("garbage"), 0o400); err != nil {
+		t.Fatal(err)
+	}
+
+	issues := Must(t, R(server.CheckStorage(ctx, fs, inventory, true)))
+
+	quarantined := make(map[string]bool)
+	for _, x := range issues {
+		if x.Repair == "quarantined" {
+			quarantined[x.Module] = true
+		}
+	}
+	assert.True(t, quarantined[parent])
+	assert.True(t, quarantined[child])
+
+	progs := Must(t, R(fs.Programs()))
+	assert.NotContains(t, progs, parent)
+	assert.NotContains(t, progs, child)
+	assert.Contains(t, progs, hashSuspend)
+}
@@ -0,0 +1,175 @@
+// Copyright (c) 2026 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package fetch implements a service which makes HTTP requests on behalf of
+// programs.  Destinations must be allowed by configured rules.
+//
+// A request is opened with a call which specifies method, URL and headers.
+// The call is answered with a stream id.  Request body (if any) is written to
+// the stream, and response body is read from it.  Response status and headers
+// are received by making another call with the stream id.  HTTP connections
+// are not retained when the instance is suspended.
+package fetch
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"gate.computer/gate/service"
+
+	. "import.name/type/context"
+)
+
+const (
+	serviceName     = "fetch"
+	serviceRevision = "0"
+)
+
+const (
+	DefaultMaxRequests     = 8
+	DefaultMaxResponseSize = 16 * 1024 * 1024
+	DefaultTimeout         = 30 * time.Second
+)
+
+// Rule allows requests to matching destinations.  Patterns use path.Match
+// syntax.
+type Rule struct {
+	// Principal ID and program scope which are required for the rule to
+	// apply.  Empty value doesn't restrict.
+	Principal string
+	Scope     string
+
+	// Scheme is http or https.  Empty value matches both.
+	Scheme string
+
+	// Host name pattern.  It must include port if the URL specifies one.
+	Host string
+
+	// Path pattern.  It matches also descendants of matching paths.  Empty
+	// value matches all paths.
+	Path string
+}
+
+func (r *Rule) appliesTo(ctx Context) bool {
+	return r.Host != "" && service.ContextMatches(ctx, r.Principal, r.Scope)
+}
+
+func (r *Rule) matches(u *url.URL, urlPath string) bool {
+	if r.Scheme != "" && r.Scheme != u.Scheme {
+		return false
+	}
+	if ok, _ := path.Match(r.Host, u.Host); !ok {
+		return false
+	}
+	if r.Path == "" {
+		return true
+	}
+
+	for p := urlPath; ; p = path.Dir(p) {
+		if ok, _ := path.Match(r.Path, p); ok {
+			return true
+		}
+		if p == "/" {
+			return false
+		}
+	}
+}
+
+// allowed checks if the URL matches any of the rules.  The URL path must be
+// in canonical form.
+func allowed(rules []Rule, u *url.URL) bool {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return false
+	}
+
+	urlPath := u.Path
+	if urlPath == "" {
+		urlPath = "/"
+	}
+	if path.Clean(urlPath) != strings.TrimSuffix(urlPath, "/") && urlPath != "/" {
+		return false
+	}
+	urlPath = path.Clean(urlPath)
+
+	for i := range rules {
+		if rules[i].matches(u, urlPath) {
+			return true
+		}
+	}
+	return false
+}
+
+type Config struct {
+	Rules []Rule
+
+	MaxRequests     int           // Concurrent requests per instance.
+	MaxResponseSize int           // Longer response bodies are truncated.
+	Timeout         time.Duration // Including response body transfer.
+}
+
+var DefaultConfig = Config{
+	MaxRequests:     DefaultMaxRequests,
+	MaxResponseSize: DefaultMaxResponseSize,
+	Timeout:         DefaultTimeout,
+}
+
+type Service struct {
+	config Config
+	client *http.Client
+}
+
+func New(c *Config) *Service {
+	s := &Service{
+		client: new(http.Client),
+	}
+	if c != nil {
+		s.config = *c
+	}
+	if s.config.MaxRequests <= 0 {
+		s.config.MaxRequests = DefaultMaxRequests
+	}
+	if s.config.MaxResponseSize <= 0 {
+		s.config.MaxResponseSize = DefaultMaxResponseSize
+	}
+	if s.config.Timeout <= 0 {
+		s.config.Timeout = DefaultTimeout
+	}
+	return s
+}
+
+func (s *Service) Properties() service.Properties {
+	return service.Properties{
+		Service: service.Service{
+			Name:     serviceName,
+			Revision: serviceRevision,
+		},
+		Streams: true,
+	}
+}
+
+func (s *Service) Discoverable(ctx Context) bool {
+	return len(s.rules(ctx)) > 0
+}
+
+func (s *Service) CreateInstance(ctx Context, config service.InstanceConfig, snapshot []byte) (service.Instance, error) {
+	inst := newInstance(s, config.Service)
+	if err := inst.restore(snapshot); err != nil {
+		return nil, err
+	}
+	return inst, nil
+}
+
+// rules which apply to the contextual principal and program scope.
+func (s *Service) rules(ctx Context) []Rule {
+	var rules []Rule
+	for _, r := range s.config.Rules {
+		if r.appliesTo(ctx) {
+			rules = append(rules, r)
+		}
+	}
+	return rules
+}
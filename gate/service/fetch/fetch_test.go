@@ -0,0 +1,208 @@
+// Copyright (c) 2026 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fetch
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"gate.computer/gate/packet"
+	"gate.computer/gate/principal"
+	"gate.computer/gate/service/servicetest"
+	"github.com/stretchr/testify/assert"
+
+	. "import.name/type/context"
+)
+
+func TestFactory(t *testing.T) {
+	ctx := principal.ContextWithLocalID(t.Context())
+
+	s := New(&Config{
+		Rules: []Rule{{Principal: principal.ContextID(ctx).String(), Host: "example.net"}},
+	})
+
+	servicetest.FactoryTest(ctx, t, s, servicetest.FactorySpec{})
+}
+
+func TestAllowed(t *testing.T) {
+	rules := []Rule{
+		{Scheme: "https", Host: "*.example.net", Path: "/api"},
+		{Host: "localhost:*", Path: "/v*/items"},
+	}
+
+	for _, x := range []struct {
+		url string
+		ok  bool
+	}{
+		{"https://www.example.net/api", true},
+		{"https://www.example.net/api/", true},
+		{"https://www.example.net/api/foo/bar", true},
+		{"https://www.example.net/apis", false},
+		{"https://www.example.net/", false},
+		{"https://www.example.net/api/../admin", false},
+		{"https://www.example.net//api", false},
+		{"http://www.example.net/api", false},
+		{"https://www.example.net:8443/api", false},
+		{"https://example.net/api", false},
+		{"http://localhost:8080/v1/items/3", true},
+		{"http://localhost/v1/items", false},
+		{"ftp://localhost:21/v1/items", false},
+	} {
+		u, err := url.Parse(x.url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, allowed(rules, u), x.ok, x.url)
+	}
+}
+
+func call(ctx Context, t *testing.T, i *servicetest.InstanceTester, op uint8, content []byte) (uint16, []byte) {
+	t.Helper()
+
+	p := append(packet.MakeCall(servicetest.Code, 0), op)
+	p = append(p, content...)
+	assert.Nil(t, i.Handle(ctx, t, p))
+
+	for {
+		p := i.Receive(ctx, t)
+		if p.Domain() == packet.DomainCall {
+			c := p.Content()
+			return binary.LittleEndian.Uint16(c), c[2:]
+		}
+	}
+}
+
+func open(ctx Context, t *testing.T, i *servicetest.InstanceTester, h requestHeader) (uint16, int32) {
+	t.Helper()
+
+	b, err := json.Marshal(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	code, reply := call(ctx, t, i, callOpen, b)
+	if code != errNone {
+		return code, -1
+	}
+	return code, int32(binary.LittleEndian.Uint32(reply))
+}
+
+func TestInstance(t *testing.T) {
+	ctx := principal.ContextWithLocalID(t.Context())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-Method", r.Method)
+		w.Header().Set("X-Test", r.Header.Get("X-Test"))
+		w.Write([]byte("echo: "))
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := New(&Config{
+		Rules: []Rule{{Host: u.Host, Path: "/allowed"}},
+	})
+
+	i := servicetest.NewInstanceTester(ctx, t, s, servicetest.InstanceSpec{})
+
+	code, _ := open(ctx, t, i, requestHeader{URL: server.URL + "/denied"})
+	assert.Equal(t, code, errDenied)
+
+	code, id := open(ctx, t, i, requestHeader{
+		Method: http.MethodPost,
+		URL:    server.URL + "/allowed/path",
+		Header: http.Header{"X-Test": {"hello"}},
+		Body:   true,
+	})
+	assert.Equal(t, code, errNone)
+
+	body := packet.MakeData(servicetest.Code, id, 5)
+	copy(body.Data(), "world")
+	i.Handle(ctx, t, packet.Buf(body))
+	i.Handle(ctx, t, packet.MakeDataEOF(servicetest.Code, id))
+	i.Handle(ctx, t, packet.MakeFlow(servicetest.Code, id, 1000))
+
+	p := append(packet.MakeCall(servicetest.Code, 0), callResponse)
+	p = binary.LittleEndian.AppendUint32(p, uint32(id))
+	i.Handle(ctx, t, p)
+
+	var (
+		head     []byte
+		response []byte
+		eof      bool
+	)
+
+	for head == nil || !eof {
+		p := i.Receive(ctx, t)
+
+		switch p.Domain() {
+		case packet.DomainCall:
+			head = p.Content()
+
+		case packet.DomainData:
+			p := packet.DataBuf(p)
+			assert.Equal(t, p.ID(), id)
+			response = append(response, p.Data()...)
+			eof = p.EOF()
+		}
+	}
+
+	assert.Equal(t, binary.LittleEndian.Uint16(head), errNone)
+
+	var h responseHeader
+	if err := json.Unmarshal(head[2:], &h); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, h.Status, http.StatusOK)
+	assert.Equal(t, h.Header.Get("X-Method"), http.MethodPost)
+	assert.Equal(t, h.Header.Get("X-Test"), "hello")
+	assert.Equal(t, string(response), "echo: world")
+
+	i.Handle(ctx, t, packet.MakeFlowEOF(servicetest.Code, id))
+
+	code, _ = call(ctx, t, i, callResponse, binary.LittleEndian.AppendUint32(nil, uint32(id)))
+	assert.Equal(t, code, errInvalid)
+
+	i.Shutdown(ctx, t)
+}
+
+func TestInstanceResponseSize(t *testing.T) {
+	ctx := principal.ContextWithLocalID(t.Context())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(make([]byte, 100))
+	}))
+	defer server.Close()
+
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := New(&Config{
+		Rules:           []Rule{{Host: u.Host}},
+		MaxResponseSize: 99,
+	})
+
+	i := servicetest.NewInstanceTester(ctx, t, s, servicetest.InstanceSpec{})
+
+	code, id := open(ctx, t, i, requestHeader{URL: server.URL})
+	assert.Equal(t, code, errNone)
+
+	code, _ = call(ctx, t, i, callResponse, binary.LittleEndian.AppendUint32(nil, uint32(id)))
+	assert.Equal(t, code, errTooLarge)
+
+	i.Shutdown(ctx, t)
+}
@@ -0,0 +1,588 @@
+// Copyright (c) 2026 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fetch
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"gate.computer/gate/packet"
+	"gate.computer/gate/packet/packetio"
+	"gate.computer/gate/service"
+	"gate.computer/internal/error/badprogram"
+	"gate.computer/internal/varint"
+	"import.name/lock"
+
+	. "import.name/type/context"
+)
+
+const (
+	callOpen uint8 = iota
+	callResponse
+)
+
+// Error codes.
+const (
+	errNone uint16 = iota
+	errInvalid
+	errDenied
+	errTooMany
+	errTooLarge
+	errFailed
+	errInterrupted
+)
+
+const (
+	bufSize       = 65536 // Request body buffer.
+	maxPending    = 256   // Unanswered calls.
+	maxRedirects  = 10
+	maxHeaderSize = 65536
+)
+
+var (
+	errPendingCalls = badprogram.Error("too many pending fetch service calls")
+	errRedirect     = errors.New("redirect destination is not allowed")
+)
+
+// requestHeader is the JSON content of an open call.
+type requestHeader struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header"`
+	Body   bool        `json:"body"`
+}
+
+// responseHeader is the JSON content of a response call reply.
+type responseHeader struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header"`
+	Length int64       `json:"length"` // -1 if unknown.
+}
+
+type request struct {
+	packetio.Stream
+	head    chan []byte // Response call reply.
+	taken   bool        // Response call has been made (or stream restored).
+	stopped chan struct{}
+}
+
+func newRequest() *request {
+	return &request{
+		Stream:  packetio.MakeStream(bufSize),
+		head:    make(chan []byte, 1),
+		stopped: make(chan struct{}),
+	}
+}
+
+type instance struct {
+	service.InstanceBase
+
+	s *Service
+	packet.Service
+
+	ctx     Context // Set in Start.
+	cancel  context.CancelFunc
+	send    chan<- packet.Thunk
+	replies chan (<-chan []byte) // Reply contents in call order.
+	replied chan struct{}        // Closed when reply loop has finished.
+	pending int                  // Unanswered calls when restored or suspended.
+
+	mu       sync.Mutex // Protects the fields below.
+	requests map[int32]*request
+	shutting bool
+}
+
+func newInstance(s *Service, config packet.Service) *instance {
+	return &instance{
+		s:        s,
+		Service:  config,
+		replies:  make(chan (<-chan []byte), maxPending),
+		replied:  make(chan struct{}),
+		requests: make(map[int32]*request),
+	}
+}
+
+func (inst *instance) restore(snapshot []byte) error {
+	if len(snapshot) == 0 {
+		return nil
+	}
+
+	pending, snapshot, err := varint.Scan(snapshot)
+	if err != nil {
+		return err
+	}
+	if pending < 0 || pending > maxPending {
+		return errors.New("fetch service snapshot is invalid")
+	}
+	inst.pending = int(pending)
+
+	if len(snapshot) == 0 {
+		return nil
+	}
+
+	numStreams, snapshot, err := varint.Scan(snapshot)
+	if err != nil {
+		return err
+	}
+	if numStreams < 0 || int(numStreams) > inst.s.config.MaxRequests {
+		return errors.New("fetch service snapshot is invalid")
+	}
+
+	for range numStreams {
+		var id int32
+
+		id, snapshot, err = varint.Scan(snapshot)
+		if err != nil {
+			return err
+		}
+
+		r := newRequest()
+		r.taken = true
+		snapshot, err = r.Unmarshal(snapshot, inst.Service)
+		if err != nil {
+			return err
+		}
+
+		if _, exist := inst.requests[id]; exist {
+			return errors.New("fetch service resumed stream with duplicate id")
+		}
+		inst.requests[id] = r
+	}
+
+	return nil
+}
+
+func (inst *instance) Start(ctx Context, send chan<- packet.Thunk, abort func(error)) error {
+	inst.ctx, inst.cancel = context.WithCancel(ctx)
+	inst.send = send
+
+	// Calls which were unanswered during suspension are answered first.
+	for range inst.pending {
+		inst.replies <- ready(replyError(errInterrupted))
+	}
+	inst.pending = 0
+
+	// All requests at this point are restored ones.
+	for id, r := range inst.requests {
+		go inst.drain(inst.ctx, id, r)
+	}
+
+	go inst.replyLoop(inst.ctx)
+	return nil
+}
+
+func (inst *instance) Handle(ctx Context, send chan<- packet.Thunk, p packet.Buf) (packet.Buf, error) {
+	switch p.Domain() {
+	case packet.DomainCall:
+		reply := inst.handleCall(ctx, p.Content())
+
+		select {
+		case inst.replies <- reply:
+		default:
+			return nil, errPendingCalls
+		}
+
+	case packet.DomainFlow:
+		p := packet.FlowBuf(p)
+
+		for i := 0; i < p.Len(); i++ {
+			flow := p.At(i)
+
+			r := inst.request(flow.ID)
+			if r == nil {
+				continue // Finished.
+			}
+
+			if _, ok := flow.Note(); !ok {
+				if err := packetio.Subscribe(r, flow.Value); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+	case packet.DomainData:
+		p := packet.DataBuf(p)
+
+		r := inst.request(p.ID())
+		if r == nil {
+			break // Finished.
+		}
+
+		if p.DataLen() != 0 {
+			if _, err := r.Write(p.Data()); err != nil {
+				return nil, err
+			}
+		} else {
+			if err := r.CloseWrite(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+func (inst *instance) request(id int32) (r *request) {
+	lock.Guard(&inst.mu, func() {
+		r = inst.requests[id]
+	})
+	return
+}
+
+// handleCall returns a channel which will receive the reply content.
+func (inst *instance) handleCall(ctx Context, buf []byte) <-chan []byte {
+	if len(buf) == 0 {
+		return ready(replyError(errInvalid))
+	}
+
+	switch buf[0] {
+	case callOpen:
+		return ready(inst.open(ctx, buf[1:]))
+
+	case callResponse:
+		if len(buf) == 1+4 {
+			id := int32(binary.LittleEndian.Uint32(buf[1:]))
+
+			var head <-chan []byte
+			lock.Guard(&inst.mu, func() {
+				if r := inst.requests[id]; r != nil && !r.taken {
+					head = r.head
+					r.taken = true
+				}
+			})
+			if head != nil {
+				return head
+			}
+		}
+	}
+
+	return ready(replyError(errInvalid))
+}
+
+func (inst *instance) open(ctx Context, buf []byte) []byte {
+	var h requestHeader
+	if err := json.Unmarshal(buf, &h); err != nil {
+		return replyError(errInvalid)
+	}
+
+	u, err := url.Parse(h.URL)
+	if err != nil {
+		return replyError(errInvalid)
+	}
+
+	rules := inst.s.rules(ctx)
+	if !allowed(rules, u) {
+		return replyError(errDenied)
+	}
+
+	var (
+		body     io.Reader
+		bodyPipe *io.PipeWriter
+	)
+	if h.Body {
+		body, bodyPipe = io.Pipe()
+	}
+
+	req, err := http.NewRequest(h.Method, u.String(), body)
+	if err != nil {
+		return replyError(errInvalid)
+	}
+	for k, vs := range h.Header {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+
+	client := *inst.s.client
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxRedirects {
+			return errors.New("too many redirects")
+		}
+		if !allowed(rules, req.URL) {
+			return errRedirect
+		}
+		return nil
+	}
+
+	var (
+		id int32
+		r  *request
+	)
+	lock.Guard(&inst.mu, func() {
+		if len(inst.requests) < inst.s.config.MaxRequests && !inst.shutting {
+			for id = 0; inst.requests[id] != nil; id++ {
+			}
+			r = newRequest()
+			inst.requests[id] = r
+		}
+	})
+	if r == nil {
+		return replyError(errTooMany)
+	}
+
+	go inst.fetch(id, r, &client, req, bodyPipe)
+
+	reply := make([]byte, 2+4)
+	binary.LittleEndian.PutUint32(reply[2:], uint32(id))
+	return reply
+}
+
+// fetch transfers the request and response bodies while the HTTP request is
+// being made.
+func (inst *instance) fetch(id int32, r *request, client *http.Client, req *http.Request, body *io.PipeWriter) {
+	defer close(r.stopped)
+
+	ctx, cancel := context.WithTimeout(inst.ctx, inst.s.config.Timeout)
+	defer cancel()
+
+	resp := newResponseReader()
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		res, err := client.Do(req.WithContext(ctx))
+		if err != nil {
+			if body != nil {
+				body.CloseWithError(err)
+			}
+			code := errFailed
+			if errors.Is(err, errRedirect) {
+				code = errDenied
+			}
+			r.head <- replyError(code)
+			resp.set(nil)
+			return
+		}
+
+		if res.ContentLength > int64(inst.s.config.MaxResponseSize) {
+			res.Body.Close()
+			r.head <- replyError(errTooLarge)
+			resp.set(nil)
+			return
+		}
+
+		reply, err := json.Marshal(responseHeader{
+			Status: res.StatusCode,
+			Header: res.Header,
+			Length: res.ContentLength,
+		})
+		if err != nil || len(reply) > maxHeaderSize {
+			res.Body.Close()
+			r.head <- replyError(errTooLarge)
+			resp.set(nil)
+			return
+		}
+
+		r.head <- append(replyError(errNone), reply...)
+		resp.set(res.Body)
+	}()
+
+	var w io.WriteCloser
+	if body != nil {
+		w = body
+	}
+
+	_ = r.Transfer(ctx, inst.Service, id, resp.limit(ctx, inst.s.config.MaxResponseSize), w, inst.send)
+	r.WriteStream.State.Data = nil // Connections are not conserved.
+
+	cancel()
+	<-done
+	resp.close()
+
+	inst.finish(id, r)
+}
+
+// drain a restored stream without connection until it's fully closed.
+func (inst *instance) drain(ctx Context, id int32, r *request) {
+	defer close(r.stopped)
+
+	// Errors would be I/O errors, but there is no connection.
+	_ = r.Transfer(ctx, inst.Service, id, nil, nil, inst.send)
+
+	inst.finish(id, r)
+}
+
+func (inst *instance) finish(id int32, r *request) {
+	if r.Live() {
+		return
+	}
+
+	lock.Guard(&inst.mu, func() {
+		if !inst.shutting {
+			delete(inst.requests, id)
+		}
+	})
+}
+
+func (inst *instance) replyLoop(ctx Context) {
+	defer close(inst.replied)
+
+	for {
+		var reply <-chan []byte
+
+		select {
+		case reply = <-inst.replies:
+		case <-ctx.Done():
+			return
+		}
+
+		var content []byte
+
+		select {
+		case content = <-reply:
+		case <-ctx.Done():
+			inst.pending = 1
+			return
+		}
+
+		p := packet.MakeCall(inst.Code, len(content))
+		copy(p.Content(), content)
+
+		select {
+		case inst.send <- p.Thunk():
+		case <-ctx.Done():
+			inst.pending = 1
+			return
+		}
+	}
+}
+
+func (inst *instance) Shutdown(ctx Context, suspend bool) ([]byte, error) {
+	var requests map[int32]*request
+
+	lock.Guard(&inst.mu, func() {
+		inst.shutting = true
+		requests = inst.requests
+	})
+
+	if inst.cancel != nil {
+		inst.cancel()
+		<-inst.replied
+
+		for _, r := range requests {
+			r.StopTransfer()
+		}
+		for _, r := range requests {
+			<-r.stopped
+		}
+	}
+
+	if !suspend {
+		return nil, nil
+	}
+
+	pending := int32(inst.pending + len(inst.replies))
+
+	var numStreams int32
+	for _, r := range requests {
+		if r.Live() {
+			numStreams++
+		}
+	}
+
+	if pending == 0 && numStreams == 0 {
+		return nil, nil
+	}
+
+	size := varint.Len(pending)
+	if numStreams > 0 {
+		size += varint.Len(numStreams)
+		for id, r := range requests {
+			if r.Live() {
+				size += varint.Len(id)
+				size += r.MarshaledSize()
+			}
+		}
+	}
+
+	output := make([]byte, size)
+	b := varint.Put(output, pending)
+	if numStreams > 0 {
+		b = varint.Put(b, numStreams)
+		for id, r := range requests {
+			if r.Live() {
+				b = varint.Put(b, id)
+				b = r.Marshal(b)
+			}
+		}
+	}
+
+	return output, nil
+}
+
+// responseReader blocks until response body is available.
+type responseReader struct {
+	ready chan struct{}
+	body  io.ReadCloser // Nil if the request failed.
+}
+
+func newResponseReader() *responseReader {
+	return &responseReader{
+		ready: make(chan struct{}),
+	}
+}
+
+func (r *responseReader) set(body io.ReadCloser) {
+	r.body = body
+	close(r.ready)
+}
+
+func (r *responseReader) limit(ctx Context, n int) io.Reader {
+	return &limitedReader{r, ctx, int64(n)}
+}
+
+func (r *responseReader) close() {
+	if r.body != nil {
+		r.body.Close()
+	}
+}
+
+// limitedReader reads response body up to n bytes.
+type limitedReader struct {
+	r   *responseReader
+	ctx Context
+	n   int64
+}
+
+func (l *limitedReader) Read(b []byte) (int, error) {
+	select {
+	case <-l.r.ready:
+	case <-l.ctx.Done():
+		return 0, l.ctx.Err()
+	}
+
+	if l.r.body == nil || l.n <= 0 {
+		return 0, io.EOF
+	}
+
+	if int64(len(b)) > l.n {
+		b = b[:l.n]
+	}
+	n, err := l.r.body.Read(b)
+	l.n -= int64(n)
+	if n > 0 && err == io.EOF {
+		err = nil // Transfer needs a separate EOF.
+	}
+	return n, err
+}
+
+func ready(content []byte) <-chan []byte {
+	c := make(chan []byte, 1)
+	c <- content
+	return c
+}
+
+func replyError(code uint16) []byte {
+	b := make([]byte, 2)
+	binary.LittleEndian.PutUint16(b, code)
+	return b
+}
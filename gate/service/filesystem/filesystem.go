@@ -0,0 +1,156 @@
+// Copyright (c) 2026 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package filesystem implements a service which exposes host directories to
+// programs as read-only trees.
+//
+// The root directory of the tree contains the mounts which apply to the
+// program.  Files are opened using calls, and their contents are read from
+// streams.  File positions are retained when the instance is suspended.
+package filesystem
+
+import (
+	"io/fs"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"gate.computer/gate/service"
+
+	. "import.name/type/context"
+)
+
+const (
+	serviceName     = "filesystem"
+	serviceRevision = "0"
+)
+
+const (
+	DefaultMaxFiles       = 16
+	DefaultBytesPerSecond = 4 * 1024 * 1024
+)
+
+// Mount exposes a host directory.
+type Mount struct {
+	// Principal ID and program scope which are required for the mount to
+	// apply.  Empty value doesn't restrict.
+	Principal string
+	Scope     string
+
+	Name string // Directory name in the root of the tree.
+	Dir  string // Host directory path.
+}
+
+func (m *Mount) appliesTo(ctx Context) bool {
+	return m.Dir != "" && validName(m.Name) && service.ContextMatches(ctx, m.Principal, m.Scope)
+}
+
+func validName(name string) bool {
+	return name != "." && fs.ValidPath(name) && !strings.Contains(name, "/")
+}
+
+type Config struct {
+	Mounts []Mount
+
+	MaxFiles       int // Open files per instance.
+	BytesPerSecond int // Read rate per instance.
+}
+
+var DefaultConfig = Config{
+	MaxFiles:       DefaultMaxFiles,
+	BytesPerSecond: DefaultBytesPerSecond,
+}
+
+type Service struct {
+	config Config
+}
+
+func New(c *Config) *Service {
+	s := new(Service)
+	if c != nil {
+		s.config = *c
+	}
+	if s.config.MaxFiles <= 0 {
+		s.config.MaxFiles = DefaultMaxFiles
+	}
+	if s.config.BytesPerSecond <= 0 {
+		s.config.BytesPerSecond = DefaultBytesPerSecond
+	}
+	return s
+}
+
+func (s *Service) Properties() service.Properties {
+	return service.Properties{
+		Service: service.Service{
+			Name:     serviceName,
+			Revision: serviceRevision,
+		},
+		Streams: true,
+	}
+}
+
+func (s *Service) Discoverable(ctx Context) bool {
+	return len(s.mounts(ctx)) > 0
+}
+
+func (s *Service) CreateInstance(ctx Context, config service.InstanceConfig, snapshot []byte) (service.Instance, error) {
+	inst := newInstance(s, config.Service)
+	if err := inst.restore(ctx, snapshot); err != nil {
+		inst.closeFiles()
+		return nil, err
+	}
+	return inst, nil
+}
+
+// mounts which apply to the contextual principal and program scope, sorted
+// by name.  If multiple mounts have the same name, the first one is used.
+func (s *Service) mounts(ctx Context) []Mount {
+	var mounts []Mount
+	for _, m := range s.config.Mounts {
+		if m.appliesTo(ctx) && !slices.ContainsFunc(mounts, func(x Mount) bool { return x.Name == m.Name }) {
+			mounts = append(mounts, m)
+		}
+	}
+	slices.SortStableFunc(mounts, func(a, b Mount) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return mounts
+}
+
+// limiter spaces reads so that their average rate doesn't exceed the quota.
+// Reading may get ahead by one second's worth.
+type limiter struct {
+	maxRead int
+	perByte float64 // Nanoseconds.
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+func newLimiter(bytesPerSecond int) *limiter {
+	return &limiter{
+		maxRead: bytesPerSecond,
+		perByte: float64(time.Second) / float64(bytesPerSecond),
+		next:    time.Now(),
+	}
+}
+
+// delay before the next read.
+func (l *limiter) delay() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return time.Until(l.next) - time.Second
+}
+
+func (l *limiter) charge(n int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now := time.Now(); l.next.Before(now) {
+		l.next = now
+	}
+	l.next = l.next.Add(time.Duration(float64(n) * l.perByte))
+}
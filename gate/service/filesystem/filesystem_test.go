@@ -0,0 +1,145 @@
+// Copyright (c) 2026 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package filesystem
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gate.computer/gate/packet"
+	"gate.computer/gate/principal"
+	"gate.computer/gate/service/servicetest"
+	"github.com/stretchr/testify/assert"
+
+	. "import.name/type/context"
+)
+
+func newTestService(t *testing.T, ctx Context) *Service {
+	t.Helper()
+
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "hello.txt"), []byte("hello, world"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	return New(&Config{
+		Mounts: []Mount{
+			{Principal: principal.ContextID(ctx).String(), Name: "data", Dir: dir},
+			{Principal: "ed25519:none", Name: "secret", Dir: dir},
+		},
+	})
+}
+
+func TestFactory(t *testing.T) {
+	ctx := principal.ContextWithLocalID(t.Context())
+
+	servicetest.FactoryTest(ctx, t, newTestService(t, ctx), servicetest.FactorySpec{})
+}
+
+func call(ctx Context, t *testing.T, i *servicetest.InstanceTester, op uint8, args ...string) (uint16, []byte) {
+	t.Helper()
+
+	p := append(packet.MakeCall(servicetest.Code, 0), op)
+	if len(args) > 1 {
+		p = binary.LittleEndian.AppendUint32(p, uint32(len(args[0])))
+	}
+	for _, s := range args {
+		p = append(p, s...)
+	}
+
+	c := i.Handle(ctx, t, p).Content()
+	return binary.LittleEndian.Uint16(c), c[2:]
+}
+
+func receive(ctx Context, t *testing.T, i *servicetest.InstanceTester) packet.DataBuf {
+	t.Helper()
+
+	for {
+		if p := i.Receive(ctx, t); p.Domain() == packet.DomainData {
+			return packet.DataBuf(p)
+		}
+	}
+}
+
+func TestInstance(t *testing.T) {
+	ctx := principal.ContextWithLocalID(t.Context())
+	s := newTestService(t, ctx)
+
+	i := servicetest.NewInstanceTester(ctx, t, s, servicetest.InstanceSpec{})
+
+	code, list := call(ctx, t, i, callReadDir, "/", "")
+	assert.Equal(t, code, errNone)
+	assert.Equal(t, list, []byte("\x02\x04\x00\x00\x00data"))
+
+	code, list = call(ctx, t, i, callReadDir, "data", "")
+	assert.Equal(t, code, errNone)
+	assert.Equal(t, list, []byte("\x01\x09\x00\x00\x00hello.txt\x02\x03\x00\x00\x00sub"))
+
+	code, list = call(ctx, t, i, callReadDir, "data", "hello.txt")
+	assert.Equal(t, code, errNone)
+	assert.Equal(t, list, []byte("\x02\x03\x00\x00\x00sub"))
+
+	code, _ = call(ctx, t, i, callReadDir, "data/hello.txt", "")
+	assert.Equal(t, code, errNotDir)
+
+	code, stat := call(ctx, t, i, callStat, "data/hello.txt")
+	assert.Equal(t, code, errNone)
+	assert.Equal(t, stat[0], kindFile)
+	assert.Equal(t, binary.LittleEndian.Uint64(stat[1:]), uint64(12))
+
+	code, _ = call(ctx, t, i, callStat, "secret")
+	assert.Equal(t, code, errNotFound)
+
+	code, _ = call(ctx, t, i, callStat, "data/../data")
+	assert.Equal(t, code, errInvalid)
+
+	code, _ = call(ctx, t, i, callOpen, "data/sub")
+	assert.Equal(t, code, errIsDir)
+
+	code, reply := call(ctx, t, i, callOpen, "/data/hello.txt")
+	assert.Equal(t, code, errNone)
+	id := int32(binary.LittleEndian.Uint32(reply))
+
+	i.Handle(ctx, t, packet.MakeFlow(servicetest.Code, id, 5))
+	p := receive(ctx, t, i)
+	assert.Equal(t, p.ID(), id)
+	assert.Equal(t, string(p.Data()), "hello")
+
+	snapshot := i.Suspend(ctx, t)
+	i = servicetest.NewInstanceTester(ctx, t, s, servicetest.InstanceSpec{Snapshot: snapshot})
+
+	i.Handle(ctx, t, packet.MakeFlow(servicetest.Code, id, 100))
+
+	var data []byte
+	for {
+		p := receive(ctx, t, i)
+		assert.Equal(t, p.ID(), id)
+		if p.EOF() {
+			break
+		}
+		data = append(data, p.Data()...)
+	}
+	assert.Equal(t, string(data), ", world")
+
+	i.Shutdown(ctx, t)
+}
+
+func TestLimiter(t *testing.T) {
+	l := newLimiter(1000)
+	assert.True(t, l.delay() <= 0)
+
+	l.charge(1000)
+	assert.True(t, l.delay() <= 0)
+
+	l.charge(500)
+	d := l.delay()
+	assert.True(t, d > 0 && d <= 500*time.Millisecond)
+}
@@ -0,0 +1,572 @@
+// Copyright (c) 2026 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package filesystem
+
+import (
+	"encoding/binary"
+	"errors"
+	"io/fs"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"gate.computer/gate/packet"
+	"gate.computer/gate/packet/packetio"
+	"gate.computer/gate/service"
+	"gate.computer/internal/varint"
+	"import.name/lock"
+
+	. "import.name/type/context"
+)
+
+const (
+	callOpen uint8 = iota
+	callStat
+	callReadDir
+)
+
+// Error codes.
+const (
+	errNone uint16 = iota
+	errInvalid
+	errNotFound
+	errNotDir
+	errIsDir
+	errTooMany
+	errFailed
+)
+
+// File kinds.
+const (
+	kindOther uint8 = iota
+	kindFile
+	kindDir
+)
+
+const maxPathSize = 4096
+
+var errSnapshot = errors.New("filesystem service snapshot is invalid")
+
+type file struct {
+	packetio.ReadStream
+	path    string
+	f       *os.File // Nil if not reading.
+	pos     int64    // Bytes read from file.
+	stopped chan struct{}
+}
+
+func newFile(path string, f *os.File) *file {
+	return &file{
+		ReadStream: packetio.MakeReadStream(),
+		path:       path,
+		f:          f,
+		stopped:    make(chan struct{}),
+	}
+}
+
+type instance struct {
+	service.InstanceBase
+
+	s *Service
+	packet.Service
+
+	limiter *limiter
+	ctx     Context // Set in Start.
+	send    chan<- packet.Thunk
+	stop    chan struct{}
+
+	mu       sync.Mutex // Protects the fields below.
+	files    map[int32]*file
+	shutting bool
+}
+
+func newInstance(s *Service, config packet.Service) *instance {
+	return &instance{
+		s:       s,
+		Service: config,
+		limiter: newLimiter(s.config.BytesPerSecond),
+		stop:    make(chan struct{}),
+		files:   make(map[int32]*file),
+	}
+}
+
+func (inst *instance) restore(ctx Context, snapshot []byte) error {
+	if len(snapshot) == 0 {
+		return nil
+	}
+
+	numFiles, b, err := varint.Scan(snapshot)
+	if err != nil {
+		return err
+	}
+	if int(numFiles) > inst.s.config.MaxFiles {
+		return errSnapshot
+	}
+
+	for range numFiles {
+		var id, n int32
+
+		if id, b, err = varint.Scan(b); err != nil {
+			return err
+		}
+		if n, b, err = varint.Scan(b); err != nil {
+			return err
+		}
+		if int(n) > len(b) || n > maxPathSize {
+			return errSnapshot
+		}
+		path := string(b[:n])
+		b = b[n:]
+
+		pos, size := binary.Uvarint(b)
+		if size <= 0 || int64(pos) < 0 {
+			return errSnapshot
+		}
+		b = b[size:]
+
+		if _, exist := inst.files[id]; exist {
+			return errSnapshot
+		}
+
+		x := newFile(path, nil)
+		if b, err = x.State.Unmarshal(b, inst.MaxSendSize); err != nil {
+			return err
+		}
+		inst.files[id] = x
+
+		if x.Reading() {
+			// If the file cannot be reopened, the stream is ended early.
+			if f, code := inst.open(ctx, path); code == errNone {
+				if _, err := f.Seek(int64(pos), 0); err == nil {
+					x.f = f
+					x.pos = int64(pos)
+				} else {
+					f.Close()
+				}
+			}
+		}
+	}
+
+	if len(b) != 0 {
+		return errSnapshot
+	}
+	return nil
+}
+
+func (inst *instance) closeFiles() {
+	for _, x := range inst.files {
+		if x.f != nil {
+			x.f.Close()
+		}
+	}
+}
+
+func (inst *instance) Start(ctx Context, send chan<- packet.Thunk, abort func(error)) error {
+	inst.ctx = ctx
+	inst.send = send
+
+	// All files at this point are restored ones.
+	for id, x := range inst.files {
+		go inst.transfer(id, x)
+	}
+
+	return nil
+}
+
+func (inst *instance) Handle(ctx Context, send chan<- packet.Thunk, p packet.Buf) (packet.Buf, error) {
+	switch p.Domain() {
+	case packet.DomainCall:
+		content := inst.handleCall(ctx, p.Content())
+		p = packet.MakeCall(p.Code(), len(content))
+		copy(p.Content(), content)
+		return p, nil
+
+	case packet.DomainFlow:
+		p := packet.FlowBuf(p)
+
+		for i := 0; i < p.Len(); i++ {
+			flow := p.At(i)
+
+			var x *file
+			lock.Guard(&inst.mu, func() {
+				x = inst.files[flow.ID]
+			})
+			if x == nil {
+				continue // Closed.
+			}
+
+			if _, ok := flow.Note(); !ok {
+				if err := packetio.Subscribe(x, flow.Value); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	// Data packets are ignored; files are read-only.
+	return nil, nil
+}
+
+func (inst *instance) handleCall(ctx Context, buf []byte) []byte {
+	if len(buf) == 0 {
+		return replyError(errInvalid)
+	}
+
+	switch buf[0] {
+	case callOpen:
+		path := string(buf[1:])
+
+		f, code := inst.open(ctx, path)
+		if code != errNone {
+			return replyError(code)
+		}
+
+		var id int32
+		lock.Guard(&inst.mu, func() {
+			if len(inst.files) < inst.s.config.MaxFiles && !inst.shutting {
+				for id = 0; inst.files[id] != nil; id++ {
+				}
+				x := newFile(path, f)
+				inst.files[id] = x
+				go inst.transfer(id, x)
+				f = nil
+			}
+		})
+		if f != nil {
+			f.Close()
+			return replyError(errTooMany)
+		}
+
+		return binary.LittleEndian.AppendUint32(replyError(errNone), uint32(id))
+
+	case callStat:
+		return inst.stat(ctx, string(buf[1:]))
+
+	case callReadDir:
+		buf = buf[1:]
+		if len(buf) < 4 {
+			break
+		}
+		n := binary.LittleEndian.Uint32(buf)
+		buf = buf[4:]
+		if uint64(n) > uint64(len(buf)) {
+			break
+		}
+		return inst.readDir(ctx, string(buf[:n]), string(buf[n:]))
+	}
+
+	return replyError(errInvalid)
+}
+
+// resolve a path to a mount and a path relative to the mount directory.
+// Mount is nil if path refers to the root directory.
+func (inst *instance) resolve(ctx Context, path string) (*Mount, string, uint16) {
+	if len(path) > maxPathSize {
+		return nil, "", errInvalid
+	}
+
+	path = strings.TrimPrefix(path, "/")
+	if path == "" || path == "." {
+		return nil, "", errNone
+	}
+	if !fs.ValidPath(path) {
+		return nil, "", errInvalid
+	}
+
+	name, rel, found := strings.Cut(path, "/")
+	if !found {
+		rel = "."
+	}
+
+	for _, m := range inst.s.mounts(ctx) {
+		if m.Name == name {
+			return &m, rel, errNone
+		}
+	}
+	return nil, "", errNotFound
+}
+
+func (inst *instance) open(ctx Context, path string) (*os.File, uint16) {
+	m, rel, code := inst.resolve(ctx, path)
+	if code != errNone {
+		return nil, code
+	}
+	if m == nil {
+		return nil, errIsDir
+	}
+
+	root, err := os.OpenRoot(m.Dir)
+	if err != nil {
+		return nil, errorCode(err)
+	}
+	defer root.Close()
+
+	// Non-blocking mode avoids blocking on special files such as FIFOs.  The
+	// type is checked via the opened file so that it cannot change in between.
+	f, err := root.OpenFile(rel, os.O_RDONLY|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		return nil, errorCode(err)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, errorCode(err)
+	}
+	if info.IsDir() {
+		f.Close()
+		return nil, errIsDir
+	}
+	if !info.Mode().IsRegular() {
+		f.Close()
+		return nil, errInvalid
+	}
+
+	return f, errNone
+}
+
+func (inst *instance) stat(ctx Context, path string) []byte {
+	m, rel, code := inst.resolve(ctx, path)
+	if code != errNone {
+		return replyError(code)
+	}
+
+	reply := replyError(errNone)
+
+	if m == nil {
+		reply = append(reply, kindDir)
+		reply = binary.LittleEndian.AppendUint64(reply, 0)
+		reply = binary.LittleEndian.AppendUint64(reply, 0)
+		return reply
+	}
+
+	root, err := os.OpenRoot(m.Dir)
+	if err != nil {
+		return replyError(errorCode(err))
+	}
+	defer root.Close()
+
+	info, err := root.Stat(rel)
+	if err != nil {
+		return replyError(errorCode(err))
+	}
+
+	reply = append(reply, kind(info.Mode()))
+	reply = binary.LittleEndian.AppendUint64(reply, uint64(info.Size()))
+	reply = binary.LittleEndian.AppendUint64(reply, uint64(info.ModTime().UnixNano()))
+	return reply
+}
+
+// readDir lists entries which sort after the given name.  As many entries are
+// included as fit in a packet.
+func (inst *instance) readDir(ctx Context, path, after string) []byte {
+	m, rel, code := inst.resolve(ctx, path)
+	if code != errNone {
+		return replyError(code)
+	}
+
+	type entry struct {
+		name string
+		kind uint8
+	}
+
+	var entries []entry
+
+	if m == nil {
+		for _, m := range inst.s.mounts(ctx) {
+			entries = append(entries, entry{m.Name, kindDir})
+		}
+	} else {
+		root, err := os.OpenRoot(m.Dir)
+		if err != nil {
+			return replyError(errorCode(err))
+		}
+		defer root.Close()
+
+		dir, err := root.OpenFile(rel, os.O_RDONLY|syscall.O_NONBLOCK, 0)
+		if err != nil {
+			return replyError(errorCode(err))
+		}
+		defer dir.Close()
+
+		info, err := dir.Stat()
+		if err != nil {
+			return replyError(errorCode(err))
+		}
+		if !info.IsDir() {
+			return replyError(errNotDir)
+		}
+
+		list, err := dir.ReadDir(-1)
+		if err != nil {
+			return replyError(errorCode(err))
+		}
+
+		for _, e := range list {
+			entries = append(entries, entry{e.Name(), kind(e.Type())})
+		}
+		slices.SortFunc(entries, func(a, b entry) int {
+			return strings.Compare(a.name, b.name)
+		})
+	}
+
+	maxSize := inst.MaxSendSize - packet.HeaderSize
+	reply := replyError(errNone)
+
+	for _, e := range entries {
+		if e.name <= after {
+			continue
+		}
+		if len(reply)+1+4+len(e.name) > maxSize {
+			break
+		}
+		reply = append(reply, e.kind)
+		reply = binary.LittleEndian.AppendUint32(reply, uint32(len(e.name)))
+		reply = append(reply, e.name...)
+	}
+
+	return reply
+}
+
+// transfer file contents to stream until it's fully closed.
+func (inst *instance) transfer(id int32, x *file) {
+	defer close(x.stopped)
+
+	var r *reader
+	if x.f != nil {
+		r = &reader{inst, x}
+	}
+
+	// Errors would be read or context errors.  Reading is stopped in either
+	// case.
+	if r != nil {
+		_ = x.Transfer(inst.ctx, inst.Service, id, inst.send, r)
+	} else {
+		_ = x.Transfer(inst.ctx, inst.Service, id, inst.send, nil)
+	}
+
+	if x.Live() {
+		return // Shutting down.
+	}
+
+	if x.f != nil {
+		x.f.Close()
+	}
+
+	lock.Guard(&inst.mu, func() {
+		if !inst.shutting {
+			delete(inst.files, id)
+		}
+	})
+}
+
+// reader reads a file at the rate allowed by the instance's limiter.
+type reader struct {
+	inst *instance
+	x    *file
+}
+
+func (r *reader) Read(b []byte) (int, error) {
+	l := r.inst.limiter
+
+	if d := l.delay(); d > 0 {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+		case <-r.inst.stop:
+			return 0, nil // Transfer will be stopped without reading.
+		case <-r.inst.ctx.Done():
+			return 0, r.inst.ctx.Err()
+		}
+	}
+
+	if len(b) > l.maxRead {
+		b = b[:l.maxRead]
+	}
+
+	n, err := r.x.f.Read(b)
+	l.charge(n)
+	r.x.pos += int64(n)
+	return n, err
+}
+
+func (inst *instance) Shutdown(ctx Context, suspend bool) ([]byte, error) {
+	lock.Guard(&inst.mu, func() {
+		inst.shutting = true
+	})
+
+	if inst.ctx != nil {
+		for _, x := range inst.files {
+			x.StopTransfer()
+		}
+		close(inst.stop)
+		for _, x := range inst.files {
+			<-x.stopped
+		}
+	}
+
+	defer inst.closeFiles()
+
+	if !suspend {
+		return nil, nil
+	}
+
+	var ids []int32
+	for _, id := range slices.Sorted(maps.Keys(inst.files)) {
+		if inst.files[id].Live() {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	b := binary.AppendUvarint(nil, uint64(len(ids)))
+
+	for _, id := range ids {
+		x := inst.files[id]
+
+		b = binary.AppendUvarint(b, uint64(id))
+		b = binary.AppendUvarint(b, uint64(len(x.path)))
+		b = append(b, x.path...)
+		b = binary.AppendUvarint(b, uint64(x.pos))
+
+		n := len(b)
+		b = append(b, make([]byte, x.State.MarshaledSize())...)
+		x.State.Marshal(b[n:])
+	}
+
+	return b, nil
+}
+
+func kind(mode fs.FileMode) uint8 {
+	switch {
+	case mode.IsRegular():
+		return kindFile
+	case mode.IsDir():
+		return kindDir
+	default:
+		return kindOther
+	}
+}
+
+func errorCode(err error) uint16 {
+	if errors.Is(err, fs.ErrNotExist) {
+		return errNotFound
+	}
+	return errFailed
+}
+
+func replyError(code uint16) []byte {
+	b := make([]byte, 2)
+	binary.LittleEndian.PutUint16(b, code)
+	return b
+}
@@ -0,0 +1,283 @@
+// Copyright (c) 2026 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package kv implements a persistent key-value storage service.  Values are
+// scoped per principal, and optionally per module.
+package kv
+
+import (
+	"encoding/binary"
+	"errors"
+	"log/slog"
+
+	"gate.computer/gate/packet"
+	"gate.computer/gate/principal"
+	"gate.computer/gate/server/model"
+	"gate.computer/gate/service"
+
+	. "import.name/type/context"
+)
+
+const (
+	serviceName     = "kv"
+	serviceRevision = "0"
+)
+
+const (
+	DefaultMaxKeySize   = 1024
+	DefaultMaxValueSize = 32768
+)
+
+type Config struct {
+	MaxKeySize   int
+	MaxValueSize int
+
+	// PerModule separates the values of each module.  Note that an instance
+	// which is resumed from a snapshot has a different module.
+	PerModule bool
+}
+
+var DefaultConfig = Config{
+	MaxKeySize:   DefaultMaxKeySize,
+	MaxValueSize: DefaultMaxValueSize,
+}
+
+type Service struct {
+	config Config
+	store  model.KeyValueStore
+	quota  int
+}
+
+// New service which stores values in store.  Quota is the maximum sum of key
+// and value sizes of a principal.  The service is not discoverable if store
+// is nil or quota is not positive.
+func New(c *Config, store model.KeyValueStore, quota int) *Service {
+	s := &Service{
+		store: store,
+		quota: quota,
+	}
+	if c != nil {
+		s.config = *c
+	}
+	if s.config.MaxKeySize <= 0 {
+		s.config.MaxKeySize = DefaultMaxKeySize
+	}
+	if s.config.MaxValueSize <= 0 {
+		s.config.MaxValueSize = DefaultMaxValueSize
+	}
+	return s
+}
+
+func (s *Service) Properties() service.Properties {
+	return service.Properties{
+		Service: service.Service{
+			Name:     serviceName,
+			Revision: serviceRevision,
+		},
+	}
+}
+
+func (s *Service) Discoverable(ctx Context) bool {
+	return s.store != nil && s.quota > 0 && principal.ContextID(ctx) != nil
+}
+
+func (s *Service) CreateInstance(ctx Context, config service.InstanceConfig, snapshot []byte) (service.Instance, error) {
+	return &instance{
+		s:       s,
+		maxSize: config.MaxSendSize - packet.HeaderSize,
+	}, nil
+}
+
+const (
+	callGet uint8 = iota
+	callPut
+	callDelete
+	callList
+)
+
+// Error codes.
+const (
+	errNone uint16 = iota
+	errNotFound
+	errQuota
+	errTooLarge
+	errInvalid
+	errUnavailable
+)
+
+const maxListKeys = 1000
+
+type instance struct {
+	service.InstanceBase
+	s *Service
+
+	maxSize int // Maximum reply content size.
+}
+
+func (inst *instance) Handle(ctx Context, send chan<- packet.Thunk, p packet.Buf) (packet.Buf, error) {
+	if p.Domain() != packet.DomainCall {
+		return nil, nil
+	}
+
+	content := inst.handleCall(ctx, p.Content())
+	p = packet.MakeCall(p.Code(), len(content))
+	copy(p.Content(), content)
+	return p, nil
+}
+
+func (inst *instance) handleCall(ctx Context, buf []byte) []byte {
+	if len(buf) == 0 {
+		return replyError(errInvalid)
+	}
+
+	pri := principal.ContextID(ctx)
+	if pri == nil || inst.s.store == nil {
+		return replyError(errUnavailable)
+	}
+
+	var module string
+	if inst.s.config.PerModule {
+		id, ok := principal.ContextModuleID(ctx)
+		if !ok {
+			return replyError(errUnavailable)
+		}
+		module = id
+	}
+
+	call, args := buf[0], buf[1:]
+
+	switch call {
+	case callGet:
+		return inst.get(ctx, *pri, module, args)
+
+	case callPut:
+		return inst.put(ctx, *pri, module, args)
+
+	case callDelete:
+		return inst.delete(ctx, *pri, module, args)
+
+	case callList:
+		return inst.list(ctx, *pri, module, args)
+
+	default:
+		return replyError(errInvalid)
+	}
+}
+
+func (inst *instance) get(ctx Context, pri principal.ID, module string, key []byte) []byte {
+	if code := inst.checkKey(key); code != errNone {
+		return replyError(code)
+	}
+
+	value, found, err := inst.s.store.GetValue(ctx, pri, module, key)
+	if err != nil {
+		return unavailable(ctx, err)
+	}
+	if !found {
+		return replyError(errNotFound)
+	}
+	if 2+len(value) > inst.maxSize {
+		return replyError(errTooLarge)
+	}
+
+	return append(replyError(errNone), value...)
+}
+
+func (inst *instance) put(ctx Context, pri principal.ID, module string, args []byte) []byte {
+	key, value, ok := splitArgs(args)
+	if !ok {
+		return replyError(errInvalid)
+	}
+	if code := inst.checkKey(key); code != errNone {
+		return replyError(code)
+	}
+	if len(value) > inst.s.config.MaxValueSize {
+		return replyError(errTooLarge)
+	}
+
+	if err := inst.s.store.PutValue(ctx, pri, module, key, value, inst.s.quota); err != nil {
+		if errors.Is(err, model.ErrKeyValueQuota) {
+			return replyError(errQuota)
+		}
+		return unavailable(ctx, err)
+	}
+
+	return replyError(errNone)
+}
+
+func (inst *instance) delete(ctx Context, pri principal.ID, module string, key []byte) []byte {
+	if code := inst.checkKey(key); code != errNone {
+		return replyError(code)
+	}
+
+	found, err := inst.s.store.DeleteValue(ctx, pri, module, key)
+	if err != nil {
+		return unavailable(ctx, err)
+	}
+	if !found {
+		return replyError(errNotFound)
+	}
+
+	return replyError(errNone)
+}
+
+// list replies with as many keys as fit in a packet.  The keys are encoded
+// with 32-bit length prefixes.
+func (inst *instance) list(ctx Context, pri principal.ID, module string, args []byte) []byte {
+	prefix, after, ok := splitArgs(args)
+	if !ok || len(prefix) > inst.s.config.MaxKeySize || len(after) > inst.s.config.MaxKeySize {
+		return replyError(errInvalid)
+	}
+
+	keys, err := inst.s.store.ListKeys(ctx, pri, module, prefix, after, maxListKeys)
+	if err != nil {
+		return unavailable(ctx, err)
+	}
+
+	reply := replyError(errNone)
+	for _, key := range keys {
+		if len(reply)+4+len(key) > inst.maxSize {
+			break
+		}
+		reply = binary.LittleEndian.AppendUint32(reply, uint32(len(key)))
+		reply = append(reply, key...)
+	}
+	return reply
+}
+
+// unavailable logs a storage error.  The call fails without aborting the
+// program instance, as the error may be transient.
+func unavailable(ctx Context, err error) []byte {
+	slog.WarnContext(ctx, "kv: storage error", "error", err)
+	return replyError(errUnavailable)
+}
+
+func (inst *instance) checkKey(key []byte) uint16 {
+	switch {
+	case len(key) == 0:
+		return errInvalid
+	case len(key) > inst.s.config.MaxKeySize:
+		return errTooLarge
+	default:
+		return errNone
+	}
+}
+
+// splitArgs of a call which has two parameters.  The first one has a 32-bit
+// length prefix.
+func splitArgs(buf []byte) (first, second []byte, ok bool) {
+	if len(buf) < 4 {
+		return nil, nil, false
+	}
+	n := binary.LittleEndian.Uint32(buf)
+	buf = buf[4:]
+	if uint64(n) > uint64(len(buf)) {
+		return nil, nil, false
+	}
+	return buf[:n], buf[n:], true
+}
+
+func replyError(code uint16) []byte {
+	return binary.LittleEndian.AppendUint16(nil, code)
+}
@@ -0,0 +1,206 @@
+// Copyright (c) 2026 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"gate.computer/gate/packet"
+	"gate.computer/gate/principal"
+	"gate.computer/gate/server/model"
+	"gate.computer/gate/service/servicetest"
+	"github.com/stretchr/testify/assert"
+
+	. "import.name/type/context"
+)
+
+type entryKey struct {
+	pri    string
+	module string
+	key    string
+}
+
+type memStore map[entryKey][]byte
+
+func (m memStore) GetValue(ctx Context, pri principal.ID, module string, key []byte) ([]byte, bool, error) {
+	value, found := m[entryKey{pri.String(), module, string(key)}]
+	return value, found, nil
+}
+
+func (m memStore) PutValue(ctx Context, pri principal.ID, module string, key, value []byte, maxTotalSize int) error {
+	k := entryKey{pri.String(), module, string(key)}
+
+	size := len(key) + len(value)
+	for x, v := range m {
+		if x.pri == k.pri && x != k {
+			size += len(x.key) + len(v)
+		}
+	}
+	if size > maxTotalSize {
+		return model.ErrKeyValueQuota
+	}
+
+	m[k] = bytes.Clone(value)
+	return nil
+}
+
+func (m memStore) DeleteValue(ctx Context, pri principal.ID, module string, key []byte) (bool, error) {
+	k := entryKey{pri.String(), module, string(key)}
+	_, found := m[k]
+	delete(m, k)
+	return found, nil
+}
+
+func (m memStore) ListKeys(ctx Context, pri principal.ID, module string, prefix, after []byte, limit int) ([][]byte, error) {
+	var keys []string
+	for x := range m {
+		if x.pri == pri.String() && x.module == module && strings.HasPrefix(x.key, string(prefix)) && x.key > string(after) {
+			keys = append(keys, x.key)
+		}
+	}
+	slices.Sort(keys)
+
+	var result [][]byte
+	for _, k := range keys[:min(len(keys), limit)] {
+		result = append(result, []byte(k))
+	}
+	return result, nil
+}
+
+type failStore struct{}
+
+var errFailStore = errors.New("database is unavailable")
+
+func (failStore) GetValue(Context, principal.ID, string, []byte) ([]byte, bool, error) {
+	return nil, false, errFailStore
+}
+
+func (failStore) PutValue(Context, principal.ID, string, []byte, []byte, int) error {
+	return errFailStore
+}
+
+func (failStore) DeleteValue(Context, principal.ID, string, []byte) (bool, error) {
+	return false, errFailStore
+}
+
+func (failStore) ListKeys(Context, principal.ID, string, []byte, []byte, int) ([][]byte, error) {
+	return nil, errFailStore
+}
+
+func TestFactory(t *testing.T) {
+	ctx := principal.ContextWithLocalID(t.Context())
+
+	servicetest.FactoryTest(ctx, t, New(nil, memStore{}, 100), servicetest.FactorySpec{
+		NoStreams: true,
+	})
+}
+
+func call(ctx Context, t *testing.T, i *servicetest.InstanceTester, op uint8, args ...[]byte) (uint16, []byte) {
+	t.Helper()
+
+	p := append(packet.MakeCall(servicetest.Code, 0), op)
+	if len(args) > 1 {
+		p = binary.LittleEndian.AppendUint32(p, uint32(len(args[0])))
+	}
+	for _, b := range args {
+		p = append(p, b...)
+	}
+
+	c := i.Handle(ctx, t, p).Content()
+	return binary.LittleEndian.Uint16(c), c[2:]
+}
+
+func TestInstance(t *testing.T) {
+	ctx := principal.ContextWithLocalID(t.Context())
+
+	i := servicetest.NewInstanceTester(ctx, t, New(nil, memStore{}, 20), servicetest.InstanceSpec{})
+
+	code, _ := call(ctx, t, i, callGet, []byte("foo"))
+	assert.Equal(t, code, errNotFound)
+
+	code, _ = call(ctx, t, i, callPut, []byte("foo"), []byte("hello"))
+	assert.Equal(t, code, errNone)
+
+	code, _ = call(ctx, t, i, callPut, []byte("foo/bar"), []byte("world"))
+	assert.Equal(t, code, errNone)
+
+	code, _ = call(ctx, t, i, callPut, []byte("baz"), []byte("quota"))
+	assert.Equal(t, code, errQuota)
+
+	code, value := call(ctx, t, i, callGet, []byte("foo"))
+	assert.Equal(t, code, errNone)
+	assert.Equal(t, string(value), "hello")
+
+	code, list := call(ctx, t, i, callList, []byte("foo"), nil)
+	assert.Equal(t, code, errNone)
+	assert.Equal(t, list, []byte("\x03\x00\x00\x00foo\x07\x00\x00\x00foo/bar"))
+
+	code, list = call(ctx, t, i, callList, []byte("foo"), []byte("foo"))
+	assert.Equal(t, code, errNone)
+	assert.Equal(t, list, []byte("\x07\x00\x00\x00foo/bar"))
+
+	code, _ = call(ctx, t, i, callDelete, []byte("foo"))
+	assert.Equal(t, code, errNone)
+
+	code, _ = call(ctx, t, i, callDelete, []byte("foo"))
+	assert.Equal(t, code, errNotFound)
+
+	code, _ = call(ctx, t, i, callPut, []byte{}, []byte("empty"))
+	assert.Equal(t, code, errInvalid)
+
+	i.Shutdown(ctx, t)
+}
+
+func TestInstancePerModule(t *testing.T) {
+	store := memStore{}
+	config := &Config{PerModule: true}
+
+	ctx := principal.ContextWithLocalID(t.Context())
+	ctx1 := principal.ContextWithModuleID(ctx, "module1")
+	ctx2 := principal.ContextWithModuleID(ctx, "module2")
+
+	i := servicetest.NewInstanceTester(ctx, t, New(config, store, 100), servicetest.InstanceSpec{})
+
+	code, _ := call(ctx, t, i, callGet, []byte("key"))
+	assert.Equal(t, code, errUnavailable)
+
+	code, _ = call(ctx1, t, i, callPut, []byte("key"), []byte("value"))
+	assert.Equal(t, code, errNone)
+
+	code, _ = call(ctx2, t, i, callGet, []byte("key"))
+	assert.Equal(t, code, errNotFound)
+
+	code, value := call(ctx1, t, i, callGet, []byte("key"))
+	assert.Equal(t, code, errNone)
+	assert.Equal(t, string(value), "value")
+
+	i.Shutdown(ctx, t)
+}
+
+func TestInstanceStoreError(t *testing.T) {
+	ctx := principal.ContextWithLocalID(t.Context())
+
+	i := servicetest.NewInstanceTester(ctx, t, New(nil, failStore{}, 100), servicetest.InstanceSpec{})
+
+	for _, x := range []struct {
+		op   uint8
+		args [][]byte
+	}{
+		{callGet, [][]byte{[]byte("key")}},
+		{callPut, [][]byte{[]byte("key"), []byte("value")}},
+		{callDelete, [][]byte{[]byte("key")}},
+		{callList, [][]byte{[]byte("key"), nil}},
+	} {
+		code, _ := call(ctx, t, i, x.op, x.args...)
+		assert.Equal(t, code, errUnavailable)
+	}
+
+	i.Shutdown(ctx, t)
+}
@@ -0,0 +1,24 @@
+// Copyright (c) 2026 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"gate.computer/gate/principal"
+	"gate.computer/gate/scope/program"
+
+	. "import.name/type/context"
+)
+
+// ContextMatches checks if the contextual principal ID and program scope
+// satisfy restrictions of a configured resource.  Empty principal ID or scope
+// doesn't restrict.
+func ContextMatches(ctx Context, principalID, scope string) bool {
+	if principalID != "" {
+		if pri := principal.ContextID(ctx); pri == nil || pri.String() != principalID {
+			return false
+		}
+	}
+	return scope == "" || program.ContextContains(ctx, scope)
+}
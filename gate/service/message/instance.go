@@ -0,0 +1,537 @@
+// Copyright (c) 2026 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package message
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"maps"
+	"math"
+	"slices"
+	"sync"
+
+	"gate.computer/gate/packet"
+	"gate.computer/gate/principal"
+	"gate.computer/gate/service"
+	"gate.computer/internal/varint"
+	"github.com/google/uuid"
+
+	. "import.name/type/context"
+)
+
+const (
+	callBind uint8 = iota
+	callSend
+)
+
+// Error codes.
+const (
+	errNone uint16 = iota
+	errInvalid
+	errNotFound
+	errExists
+	errTooMany
+	errTooLarge
+	errFull
+	errUnavailable
+)
+
+const (
+	maxNameSize = 255
+	mailboxID   = 0 // Stream id of the instance mailbox.
+)
+
+var errSnapshot = errors.New("message service snapshot is invalid")
+
+type channel struct {
+	name   string // Empty for the instance mailbox.
+	bound  bool
+	credit int32 // Subscribed bytes.
+	eof    bool  // Send EOF after queue has been drained.
+	closed bool  // Subscription has been finished.
+	queue  [][]byte
+	size   int
+}
+
+type instance struct {
+	service.InstanceBase
+
+	s  *Service
+	ep *Endpoint // Nil if not created through endpoint.
+	packet.Service
+
+	principal string // Empty if unknown.
+	uuid      string // Empty if unknown.
+	wakeup    chan struct{}
+	stop      chan struct{} // Non-nil after Start.
+	done      chan struct{}
+
+	mu       sync.Mutex // Protects the fields below.
+	channels map[int32]*channel
+}
+
+func newInstance(ctx Context, s *Service, ep *Endpoint, config packet.Service) *instance {
+	inst := &instance{
+		s:        s,
+		ep:       ep,
+		Service:  config,
+		wakeup:   make(chan struct{}, 1),
+		channels: make(map[int32]*channel),
+	}
+	if pri := principal.ContextID(ctx); pri != nil {
+		inst.principal = pri.String()
+	}
+	if b, ok := principal.ContextInstanceUUID(ctx); ok {
+		inst.uuid = uuid.Must(uuid.FromBytes(b[:])).String()
+	}
+	return inst
+}
+
+func (inst *instance) restore(snapshot []byte) error {
+	if len(snapshot) == 0 {
+		return nil
+	}
+
+	numChannels, b, err := varint.Scan(snapshot)
+	if err != nil {
+		return err
+	}
+	if int(numChannels) > 1+inst.s.config.MaxChannels {
+		return errSnapshot
+	}
+
+	for range numChannels {
+		var (
+			id, flags, credit, n int32
+			c                    = new(channel)
+		)
+
+		if id, b, err = varint.Scan(b); err != nil {
+			return err
+		}
+		if flags, b, err = varint.Scan(b); err != nil {
+			return err
+		}
+		if credit, b, err = varint.Scan(b); err != nil {
+			return err
+		}
+		if n, b, err = varint.Scan(b); err != nil {
+			return err
+		}
+		if int(n) > len(b) || int(n) > maxNameSize || (id == mailboxID) != (n == 0) {
+			return errSnapshot
+		}
+
+		c.name = string(b[:n])
+		b = b[n:]
+		c.eof = flags&1 != 0
+		c.credit = credit
+
+		if n, b, err = varint.Scan(b); err != nil {
+			return err
+		}
+
+		for range n {
+			var size int32
+
+			if size, b, err = varint.Scan(b); err != nil {
+				return err
+			}
+			if size == 0 || int(size) > len(b) || c.size+int(size) > inst.s.config.MaxQueueSize {
+				return errSnapshot
+			}
+
+			c.queue = append(c.queue, bytes.Clone(b[:size]))
+			c.size += int(size)
+			b = b[size:]
+		}
+
+		if _, exist := inst.channels[id]; exist {
+			return errSnapshot
+		}
+		inst.channels[id] = c
+	}
+
+	if len(b) != 0 {
+		return errSnapshot
+	}
+	return nil
+}
+
+// initMailbox unless it was restored.
+func (inst *instance) initMailbox() {
+	if _, exist := inst.channels[mailboxID]; !exist && inst.uuid != "" {
+		inst.channels[mailboxID] = new(channel)
+	}
+}
+
+func (inst *instance) address(c *channel) address {
+	name := c.name
+	if name == "" {
+		name = inst.uuid
+	}
+	return address{inst.principal, name}
+}
+
+func (inst *instance) mailbox(id int32) mailbox {
+	return mailbox{inst, inst.ep, id}
+}
+
+func (inst *instance) Start(ctx Context, send chan<- packet.Thunk, abort func(error)) error {
+	// Restored channels which cannot be bound anymore are drained and closed.
+	// Service mutex must not be locked while instance mutex is locked.
+	for id, c := range inst.channels {
+		if c.eof {
+			continue
+		}
+
+		bound := inst.principal != "" && inst.address(c).name != "" && inst.s.bind(inst.address(c), inst.mailbox(id))
+
+		inst.mu.Lock()
+		c.bound = bound
+		c.eof = !bound
+		inst.mu.Unlock()
+	}
+
+	inst.stop = make(chan struct{})
+	inst.done = make(chan struct{})
+	go inst.deliverLoop(ctx, send)
+	return nil
+}
+
+func (inst *instance) Handle(ctx Context, send chan<- packet.Thunk, p packet.Buf) (packet.Buf, error) {
+	switch p.Domain() {
+	case packet.DomainCall:
+		content := inst.handleCall(p.Content())
+		p = packet.MakeCall(p.Code(), len(content))
+		copy(p.Content(), content)
+		return p, nil
+
+	case packet.DomainFlow:
+		p := packet.FlowBuf(p)
+
+		for i := 0; i < p.Len(); i++ {
+			flow := p.At(i)
+			if _, ok := flow.Note(); !ok {
+				inst.subscribe(flow.ID, flow.Value)
+			}
+		}
+	}
+
+	// Data packets are ignored; messages are sent using calls.
+	return nil, nil
+}
+
+func (inst *instance) handleCall(buf []byte) []byte {
+	if len(buf) == 0 {
+		return replyError(errInvalid)
+	}
+
+	switch buf[0] {
+	case callBind:
+		code, id := inst.bind(string(buf[1:]))
+		if code != errNone {
+			return replyError(code)
+		}
+		return binary.LittleEndian.AppendUint32(replyError(errNone), uint32(id))
+
+	case callSend:
+		buf = buf[1:]
+		if len(buf) < 4 {
+			break
+		}
+		n := binary.LittleEndian.Uint32(buf)
+		buf = buf[4:]
+		if uint64(n) > uint64(len(buf)) || n == 0 || int(n) == len(buf) {
+			break
+		}
+		if inst.principal == "" {
+			return replyError(errUnavailable)
+		}
+		if len(buf)-int(n) > inst.s.config.MaxMessageSize {
+			return replyError(errTooLarge)
+		}
+		return replyError(inst.s.deliver(address{inst.principal, string(buf[:n])}, buf[n:]))
+	}
+
+	return replyError(errInvalid)
+}
+
+func (inst *instance) bind(name string) (uint16, int32) {
+	if name == "" || len(name) > maxNameSize {
+		return errInvalid, 0
+	}
+	if _, err := uuid.Parse(name); err == nil {
+		return errInvalid, 0 // Reserved for instance mailboxes.
+	}
+	if inst.principal == "" {
+		return errUnavailable, 0
+	}
+
+	var (
+		id int32
+		c  *channel
+	)
+	func() {
+		inst.mu.Lock()
+		defer inst.mu.Unlock()
+
+		named := len(inst.channels)
+		if _, exist := inst.channels[mailboxID]; exist {
+			named--
+		}
+		if named < inst.s.config.MaxChannels {
+			for id = mailboxID + 1; inst.channels[id] != nil; id++ {
+			}
+			c = &channel{name: name}
+			inst.channels[id] = c
+		}
+	}()
+	if c == nil {
+		return errTooMany, 0
+	}
+
+	if !inst.s.bind(inst.address(c), inst.mailbox(id)) {
+		inst.mu.Lock()
+		delete(inst.channels, id)
+		inst.mu.Unlock()
+		return errExists, 0
+	}
+
+	inst.mu.Lock()
+	c.bound = true
+	inst.mu.Unlock()
+	return errNone, id
+}
+
+func (inst *instance) subscribe(id, increment int32) {
+	var unbind *channel
+
+	func() {
+		inst.mu.Lock()
+		defer inst.mu.Unlock()
+
+		c := inst.channels[id]
+		if c == nil || c.closed {
+			return
+		}
+
+		if increment > 0 {
+			c.credit = int32(min(int64(c.credit)+int64(increment), math.MaxInt32))
+		} else {
+			// Subscription finished: close channel.
+			if c.bound {
+				c.bound = false
+				unbind = c
+			}
+			c.eof = true
+			c.closed = true
+			c.queue = nil
+			c.size = 0
+		}
+	}()
+
+	if unbind != nil {
+		inst.s.unbind(inst.address(unbind), inst.mailbox(id))
+	}
+	inst.poke()
+}
+
+// enqueue is called by Service with its mutex locked.
+func (inst *instance) enqueue(id int32, msg []byte) uint16 {
+	if len(msg) > inst.MaxSendSize-packet.DataHeaderSize {
+		return errTooLarge
+	}
+
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	c := inst.channels[id]
+	if c == nil || c.eof {
+		return errNotFound
+	}
+	if c.size+len(msg) > inst.s.config.MaxQueueSize {
+		return errFull
+	}
+
+	c.queue = append(c.queue, bytes.Clone(msg))
+	c.size += len(msg)
+	inst.poke()
+	return errNone
+}
+
+// takeover messages which were queued while the instance was suspended.  It
+// is called by Service with its mutex locked.
+func (inst *instance) takeover(id int32, queue [][]byte) {
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	c := inst.channels[id]
+	if c == nil || c.eof {
+		return
+	}
+
+	for _, msg := range queue {
+		c.queue = append(c.queue, msg)
+		c.size += len(msg)
+	}
+	inst.poke()
+}
+
+func (inst *instance) poke() {
+	select {
+	case inst.wakeup <- struct{}{}:
+	default:
+	}
+}
+
+// next packet to be delivered, or nil.  Message is removed from queue; it must
+// be put back if it's not sent.
+func (inst *instance) next() (int32, packet.Buf) {
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	for _, id := range slices.Sorted(maps.Keys(inst.channels)) {
+		c := inst.channels[id]
+
+		if len(c.queue) > 0 {
+			msg := c.queue[0]
+			if int(c.credit) < len(msg) {
+				continue
+			}
+
+			c.queue = c.queue[1:]
+			c.size -= len(msg)
+			c.credit -= int32(len(msg))
+
+			p := packet.MakeData(inst.Code, id, len(msg))
+			copy(p.Data(), msg)
+			return id, packet.Buf(p)
+		}
+
+		if c.eof {
+			return id, packet.Buf(packet.MakeDataEOF(inst.Code, id))
+		}
+	}
+
+	return 0, nil
+}
+
+// requeue a message which was not sent.
+func (inst *instance) requeue(id int32, p packet.DataBuf) {
+	if p.EOF() {
+		return
+	}
+
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	if c := inst.channels[id]; c != nil && !c.closed {
+		c.queue = slices.Insert(c.queue, 0, p.Data())
+		c.size += p.DataLen()
+		c.credit += int32(p.DataLen())
+	}
+}
+
+// sent a packet successfully.
+func (inst *instance) sent(id int32, p packet.DataBuf) {
+	if p.EOF() {
+		inst.mu.Lock()
+		delete(inst.channels, id)
+		inst.mu.Unlock()
+	}
+}
+
+func (inst *instance) deliverLoop(ctx Context, send chan<- packet.Thunk) {
+	defer close(inst.done)
+
+	for {
+		id, p := inst.next()
+		if p == nil {
+			select {
+			case <-inst.wakeup:
+				continue
+			case <-inst.stop:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		select {
+		case send <- p.Thunk():
+			inst.sent(id, packet.DataBuf(p))
+		case <-inst.stop:
+			inst.requeue(id, packet.DataBuf(p))
+			return
+		case <-ctx.Done():
+			inst.requeue(id, packet.DataBuf(p))
+			return
+		}
+	}
+}
+
+func (inst *instance) Shutdown(ctx Context, suspend bool) ([]byte, error) {
+	if inst.stop != nil {
+		close(inst.stop)
+		<-inst.done
+	}
+
+	var unbind []int32
+
+	inst.mu.Lock()
+	for id, c := range inst.channels {
+		if c.bound {
+			unbind = append(unbind, id)
+			c.bound = false
+		}
+	}
+	inst.mu.Unlock()
+
+	for _, id := range unbind {
+		if suspend {
+			inst.s.suspend(inst.address(inst.channels[id]), inst.mailbox(id))
+		} else {
+			inst.s.unbind(inst.address(inst.channels[id]), inst.mailbox(id))
+		}
+	}
+
+	// No more messages can be enqueued.
+
+	if !suspend || len(inst.channels) == 0 {
+		return nil, nil
+	}
+
+	b := binary.AppendUvarint(nil, uint64(len(inst.channels)))
+
+	for _, id := range slices.Sorted(maps.Keys(inst.channels)) {
+		c := inst.channels[id]
+
+		var flags uint64
+		if c.eof {
+			flags |= 1
+		}
+
+		b = binary.AppendUvarint(b, uint64(id))
+		b = binary.AppendUvarint(b, flags)
+		b = binary.AppendUvarint(b, uint64(c.credit))
+		b = binary.AppendUvarint(b, uint64(len(c.name)))
+		b = append(b, c.name...)
+		b = binary.AppendUvarint(b, uint64(len(c.queue)))
+		for _, msg := range c.queue {
+			b = binary.AppendUvarint(b, uint64(len(msg)))
+			b = append(b, msg...)
+		}
+	}
+
+	return b, nil
+}
+
+func replyError(code uint16) []byte {
+	b := make([]byte, 2)
+	binary.LittleEndian.PutUint16(b, code)
+	return b
+}
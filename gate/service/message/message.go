@@ -0,0 +1,281 @@
+// Copyright (c) 2026 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package message implements a service which delivers datagrams between the
+// instances of a principal.
+//
+// Each instance has a mailbox which is addressed by the instance UUID, and it
+// can bind named channels.  Messages are sent using calls, and they are
+// received as data packets on the stream of the mailbox or channel.  Each
+// stream is subscribed to in bytes like a normal stream, and each data packet
+// contains one message.  Finishing a subscription closes the channel.
+//
+// Undelivered messages and channel bindings are retained when the instance is
+// suspended, but messages cannot be sent to a suspended instance unless it was
+// created through an Endpoint.  The addresses of such an instance stay
+// reserved until the endpoint is closed; messages sent to them are queued and
+// the endpoint is woken up.
+package message
+
+import (
+	"bytes"
+	"sync"
+
+	"gate.computer/gate/packet"
+	"gate.computer/gate/principal"
+	"gate.computer/gate/service"
+
+	. "import.name/type/context"
+)
+
+const (
+	serviceName     = "message"
+	serviceRevision = "0"
+)
+
+const (
+	DefaultMaxChannels    = 16
+	DefaultMaxMessageSize = 32768
+	DefaultMaxQueueSize   = 262144
+)
+
+type Config struct {
+	MaxChannels    int // Named channels per instance.
+	MaxMessageSize int
+	MaxQueueSize   int // Total size of undelivered messages per channel.
+}
+
+var DefaultConfig = Config{
+	MaxChannels:    DefaultMaxChannels,
+	MaxMessageSize: DefaultMaxMessageSize,
+	MaxQueueSize:   DefaultMaxQueueSize,
+}
+
+// Service is shared by all instances which may communicate with each other.
+type Service struct {
+	config Config
+
+	mu        sync.Mutex
+	mailboxes map[address]mailbox
+}
+
+type address struct {
+	principal string
+	name      string // Channel name or instance UUID.
+}
+
+type mailbox struct {
+	inst *instance // Nil if dormant.
+	ep   *Endpoint // Nil unless the instance was created through endpoint.
+	id   int32
+}
+
+func New(c *Config) *Service {
+	s := &Service{
+		mailboxes: make(map[address]mailbox),
+	}
+	if c != nil {
+		s.config = *c
+	}
+	if s.config.MaxChannels <= 0 {
+		s.config.MaxChannels = DefaultMaxChannels
+	}
+	if s.config.MaxMessageSize <= 0 {
+		s.config.MaxMessageSize = DefaultMaxMessageSize
+	}
+	if s.config.MaxQueueSize <= 0 {
+		s.config.MaxQueueSize = DefaultMaxQueueSize
+	}
+	return s
+}
+
+func (s *Service) Properties() service.Properties {
+	return service.Properties{
+		Service: service.Service{
+			Name:     serviceName,
+			Revision: serviceRevision,
+		},
+		Streams: true,
+	}
+}
+
+func (s *Service) Discoverable(ctx Context) bool {
+	return principal.ContextID(ctx) != nil
+}
+
+func (s *Service) CreateInstance(ctx Context, config service.InstanceConfig, snapshot []byte) (service.Instance, error) {
+	return s.createInstance(ctx, config, snapshot, nil)
+}
+
+func (s *Service) createInstance(ctx Context, config service.InstanceConfig, snapshot []byte, ep *Endpoint) (service.Instance, error) {
+	inst := newInstance(ctx, s, ep, config.Service)
+	if err := inst.restore(snapshot); err != nil {
+		return nil, err
+	}
+	inst.initMailbox()
+	return inst, nil
+}
+
+// bind an address unless it's already bound.  A dormant address is taken over
+// by a mailbox of the same endpoint, along with the messages queued for it.
+func (s *Service) bind(a address, m mailbox) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if x, found := s.mailboxes[a]; found {
+		if x.inst != nil || x.ep != m.ep || x.id != m.id {
+			return false
+		}
+		m.ep.resume(m.inst, m.id)
+	}
+	s.mailboxes[a] = m
+	return true
+}
+
+// suspend the address of a mailbox if it's bound to it.  The address becomes
+// dormant if the mailbox has an open endpoint, otherwise it's unbound.
+func (s *Service) suspend(a address, m mailbox) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.mailboxes[a] != m {
+		return
+	}
+	if m.ep == nil || m.ep.closed {
+		delete(s.mailboxes, a)
+		return
+	}
+	s.mailboxes[a] = mailbox{ep: m.ep, id: m.id}
+	m.ep.suspend(a, m.inst, m.id)
+}
+
+// unbind an address if it's bound to the mailbox.
+func (s *Service) unbind(a address, m mailbox) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.mailboxes[a] == m {
+		delete(s.mailboxes, a)
+	}
+}
+
+// deliver a message to an address.  Error code is returned.
+func (s *Service) deliver(a address, msg []byte) uint16 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m, found := s.mailboxes[a]
+	if !found {
+		return errNotFound
+	}
+	if m.inst == nil {
+		return m.ep.enqueue(m.id, msg)
+	}
+	return m.inst.enqueue(m.id, msg)
+}
+
+// Endpoint creates the successive service instances of a single program
+// instance.  Messages sent to the instance while it's suspended are delivered
+// when it's restored through the same endpoint.
+type Endpoint struct {
+	s    *Service
+	wake chan struct{}
+
+	// Guarded by Service mutex.
+	dormant map[int32]*dormantChannel
+	closed  bool
+}
+
+type dormantChannel struct {
+	addr    address
+	maxSize int // Message size limit of the suspended instance.
+	size    int // Including the messages in the suspended instance.
+	queue   [][]byte
+}
+
+// Endpoint for a program instance.  It must be closed when the program
+// instance is not going to be restored anymore.
+func (s *Service) Endpoint() *Endpoint {
+	return &Endpoint{
+		s:       s,
+		wake:    make(chan struct{}, 1),
+		dormant: make(map[int32]*dormantChannel),
+	}
+}
+
+func (ep *Endpoint) Properties() service.Properties { return ep.s.Properties() }
+func (ep *Endpoint) Discoverable(ctx Context) bool  { return ep.s.Discoverable(ctx) }
+
+func (ep *Endpoint) CreateInstance(ctx Context, config service.InstanceConfig, snapshot []byte) (service.Instance, error) {
+	return ep.s.createInstance(ctx, config, snapshot, ep)
+}
+
+// Wake channel receives a value when a message has been queued for the
+// suspended instance.
+func (ep *Endpoint) Wake() <-chan struct{} {
+	return ep.wake
+}
+
+// Close releases the addresses of the suspended instance.  Messages queued
+// for it are discarded.
+func (ep *Endpoint) Close() error {
+	ep.s.mu.Lock()
+	defer ep.s.mu.Unlock()
+
+	for id, d := range ep.dormant {
+		if ep.s.mailboxes[d.addr] == (mailbox{ep: ep, id: id}) {
+			delete(ep.s.mailboxes, d.addr)
+		}
+	}
+	ep.dormant = nil
+	ep.closed = true
+	return nil
+}
+
+// suspend is called by Service with its mutex locked.
+func (ep *Endpoint) suspend(a address, inst *instance, id int32) {
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	ep.dormant[id] = &dormantChannel{
+		addr:    a,
+		maxSize: inst.MaxSendSize - packet.DataHeaderSize,
+		size:    inst.channels[id].size,
+	}
+}
+
+// resume is called by Service with its mutex locked.
+func (ep *Endpoint) resume(inst *instance, id int32) {
+	if d := ep.dormant[id]; d != nil {
+		delete(ep.dormant, id)
+		inst.takeover(id, d.queue)
+	}
+
+	if len(ep.dormant) == 0 {
+		select {
+		case <-ep.wake:
+		default:
+		}
+	}
+}
+
+// enqueue is called by Service with its mutex locked.
+func (ep *Endpoint) enqueue(id int32, msg []byte) uint16 {
+	d := ep.dormant[id]
+	if len(msg) > d.maxSize {
+		return errTooLarge
+	}
+	if d.size+len(msg) > ep.s.config.MaxQueueSize {
+		return errFull
+	}
+
+	d.queue = append(d.queue, bytes.Clone(msg))
+	d.size += len(msg)
+
+	select {
+	case ep.wake <- struct{}{}:
+	default:
+	}
+	return errNone
+}
@@ -0,0 +1,188 @@
+// Copyright (c) 2026 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package message
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"gate.computer/gate/packet"
+	"gate.computer/gate/principal"
+	"gate.computer/gate/service/servicetest"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	. "import.name/type/context"
+)
+
+func TestFactory(t *testing.T) {
+	ctx := principal.ContextWithLocalID(t.Context())
+
+	servicetest.FactoryTest(ctx, t, New(nil), servicetest.FactorySpec{})
+}
+
+func instanceContext(ctx Context) (Context, string) {
+	id := uuid.New()
+	return principal.ContextWithInstanceUUID(ctx, id), id.String()
+}
+
+func bind(ctx Context, t *testing.T, i *servicetest.InstanceTester, name string) (uint16, int32) {
+	t.Helper()
+
+	p := append(packet.MakeCall(servicetest.Code, 0), callBind)
+	p = append(p, name...)
+
+	c := i.Handle(ctx, t, p).Content()
+	if len(c) < 2+4 {
+		return binary.LittleEndian.Uint16(c), -1
+	}
+	return binary.LittleEndian.Uint16(c), int32(binary.LittleEndian.Uint32(c[2:]))
+}
+
+func send(ctx Context, t *testing.T, i *servicetest.InstanceTester, addr, msg string) uint16 {
+	t.Helper()
+
+	p := append(packet.MakeCall(servicetest.Code, 0), callSend)
+	p = binary.LittleEndian.AppendUint32(p, uint32(len(addr)))
+	p = append(p, addr...)
+	p = append(p, msg...)
+	return binary.LittleEndian.Uint16(i.Handle(ctx, t, p).Content())
+}
+
+func receive(ctx Context, t *testing.T, i *servicetest.InstanceTester) packet.DataBuf {
+	t.Helper()
+
+	for {
+		if p := i.Receive(ctx, t); p.Domain() == packet.DomainData {
+			return packet.DataBuf(p)
+		}
+	}
+}
+
+func TestInstance(t *testing.T) {
+	s := New(nil)
+
+	ctx := principal.ContextWithLocalID(t.Context())
+	ctx1, _ := instanceContext(ctx)
+	ctx2, uuid2 := instanceContext(ctx)
+
+	i1 := servicetest.NewInstanceTester(ctx1, t, s, servicetest.InstanceSpec{})
+	i2 := servicetest.NewInstanceTester(ctx2, t, s, servicetest.InstanceSpec{})
+
+	code, id := bind(ctx1, t, i1, "foo")
+	assert.Equal(t, code, errNone)
+	assert.Equal(t, id, int32(1))
+
+	code, _ = bind(ctx2, t, i2, "foo")
+	assert.Equal(t, code, errExists)
+
+	code, _ = bind(ctx2, t, i2, uuid2)
+	assert.Equal(t, code, errInvalid)
+
+	assert.Equal(t, send(ctx2, t, i2, "foo", "hello"), errNone)
+	assert.Equal(t, send(ctx2, t, i2, "bar", "hello"), errNotFound)
+	assert.Equal(t, send(ctx2, t, i2, "foo", ""), errInvalid)
+
+	i1.Handle(ctx1, t, packet.MakeFlow(servicetest.Code, id, 3))
+	i1.Handle(ctx1, t, packet.MakeFlow(servicetest.Code, id, 2))
+
+	p := receive(ctx1, t, i1)
+	assert.Equal(t, p.ID(), id)
+	assert.Equal(t, string(p.Data()), "hello")
+
+	assert.Equal(t, send(ctx1, t, i1, uuid2, "world"), errNone)
+
+	snapshot := i2.Suspend(ctx2, t)
+	assert.Equal(t, send(ctx1, t, i1, uuid2, "again"), errNotFound)
+
+	i2 = servicetest.NewInstanceTester(ctx2, t, s, servicetest.InstanceSpec{Snapshot: snapshot})
+	assert.Equal(t, send(ctx1, t, i1, uuid2, "again"), errNone)
+
+	i2.Handle(ctx2, t, packet.MakeFlow(servicetest.Code, mailboxID, 100))
+
+	p = receive(ctx2, t, i2)
+	assert.Equal(t, p.ID(), int32(mailboxID))
+	assert.Equal(t, string(p.Data()), "world")
+
+	p = receive(ctx2, t, i2)
+	assert.Equal(t, string(p.Data()), "again")
+
+	i1.Handle(ctx1, t, packet.MakeFlowEOF(servicetest.Code, id))
+
+	p = receive(ctx1, t, i1)
+	assert.Equal(t, p.ID(), id)
+	assert.True(t, p.EOF())
+
+	assert.Equal(t, send(ctx2, t, i2, "foo", "hello"), errNotFound)
+
+	code, id = bind(ctx2, t, i2, "foo")
+	assert.Equal(t, code, errNone)
+	assert.Equal(t, id, int32(1))
+
+	i1.Shutdown(ctx1, t)
+	i2.Shutdown(ctx2, t)
+}
+
+func TestInstanceQueue(t *testing.T) {
+	s := New(&Config{MaxQueueSize: 10})
+
+	ctx := principal.ContextWithLocalID(t.Context())
+	ctx, uuid := instanceContext(ctx)
+
+	i := servicetest.NewInstanceTester(ctx, t, s, servicetest.InstanceSpec{})
+
+	assert.Equal(t, send(ctx, t, i, uuid, "hello"), errNone)
+	assert.Equal(t, send(ctx, t, i, uuid, "world"), errNone)
+	assert.Equal(t, send(ctx, t, i, uuid, "!"), errFull)
+
+	i.Shutdown(ctx, t)
+}
+
+func TestEndpoint(t *testing.T) {
+	s := New(nil)
+
+	ctx := principal.ContextWithLocalID(t.Context())
+	ctx1, _ := instanceContext(ctx)
+	ctx2, uuid2 := instanceContext(ctx)
+
+	ep := s.Endpoint()
+
+	i1 := servicetest.NewInstanceTester(ctx1, t, s, servicetest.InstanceSpec{})
+	i2 := servicetest.NewInstanceTester(ctx2, t, ep, servicetest.InstanceSpec{})
+
+	assert.Equal(t, send(ctx1, t, i1, uuid2, "hello"), errNone)
+
+	snapshot := i2.Suspend(ctx2, t)
+
+	select {
+	case <-ep.Wake():
+		t.Error("woken before message")
+	default:
+	}
+
+	assert.Equal(t, send(ctx1, t, i1, uuid2, "world"), errNone)
+
+	select {
+	case <-ep.Wake():
+	default:
+		t.Error("not woken")
+	}
+
+	i2 = servicetest.NewInstanceTester(ctx2, t, ep, servicetest.InstanceSpec{Snapshot: snapshot})
+	i2.Handle(ctx2, t, packet.MakeFlow(servicetest.Code, mailboxID, 100))
+
+	p := receive(ctx2, t, i2)
+	assert.Equal(t, string(p.Data()), "hello")
+
+	p = receive(ctx2, t, i2)
+	assert.Equal(t, string(p.Data()), "world")
+
+	i2.Suspend(ctx2, t)
+	assert.NoError(t, ep.Close())
+
+	assert.Equal(t, send(ctx1, t, i1, uuid2, "again"), errNotFound)
+
+	i1.Shutdown(ctx1, t)
+}
@@ -0,0 +1,264 @@
+// Copyright (c) 2026 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package timer implements a service which notifies programs at wall-clock
+// times.  Pending timers are retained when the instance is suspended, and the
+// server is asked to resume the instance when the earliest one expires.
+package timer
+
+import (
+	"encoding/binary"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"gate.computer/gate/packet"
+	"gate.computer/gate/service"
+
+	. "import.name/type/context"
+)
+
+const (
+	serviceName     = "timer"
+	serviceRevision = "0"
+)
+
+// MaxTimers pending per instance.
+const MaxTimers = 64
+
+var Service timer
+
+type timer struct{}
+
+func (timer) Properties() service.Properties {
+	return service.Properties{
+		Service: service.Service{
+			Name:     serviceName,
+			Revision: serviceRevision,
+		},
+	}
+}
+
+func (timer) Discoverable(Context) bool {
+	return true
+}
+
+func (timer) CreateInstance(ctx Context, config service.InstanceConfig, snapshot []byte) (service.Instance, error) {
+	inst := &instance{
+		code:    config.Code,
+		changed: make(chan struct{}, 1),
+	}
+	if err := inst.restore(snapshot); err != nil {
+		return nil, err
+	}
+	return inst, nil
+}
+
+const (
+	callSet uint8 = iota
+	callCancel
+)
+
+// Error codes.
+const (
+	errNone uint16 = iota
+	errInvalid
+	errTooMany
+	errNotFound
+)
+
+type instance struct {
+	service.InstanceBase
+
+	code    packet.Code
+	changed chan struct{}
+	stop    chan struct{} // Non-nil after Start.
+	done    chan struct{}
+
+	mu     sync.Mutex
+	timers []int64 // Unix nanoseconds in ascending order.
+}
+
+func (inst *instance) restore(snapshot []byte) error {
+	if len(snapshot)%8 != 0 {
+		return errors.New("timer service snapshot has invalid size")
+	}
+
+	for b := snapshot; len(b) > 0; b = b[8:] {
+		inst.timers = append(inst.timers, int64(binary.LittleEndian.Uint64(b)))
+	}
+	if len(inst.timers) > MaxTimers || !slices.IsSorted(inst.timers) {
+		return errors.New("timer service snapshot is invalid")
+	}
+	return nil
+}
+
+func (inst *instance) Start(ctx Context, send chan<- packet.Thunk, abort func(error)) error {
+	inst.stop = make(chan struct{})
+	inst.done = make(chan struct{})
+	go inst.loop(ctx, send)
+	return nil
+}
+
+func (inst *instance) Handle(ctx Context, send chan<- packet.Thunk, p packet.Buf) (packet.Buf, error) {
+	if p.Domain() != packet.DomainCall {
+		return nil, nil
+	}
+
+	code := errInvalid
+
+	if buf := p.Content(); len(buf) == 1+8 {
+		t := int64(binary.LittleEndian.Uint64(buf[1:]))
+
+		switch buf[0] {
+		case callSet:
+			code = inst.set(t)
+
+		case callCancel:
+			code = inst.cancel(t)
+		}
+	}
+
+	reply := packet.MakeCall(p.Code(), 2)
+	binary.LittleEndian.PutUint16(reply.Content(), code)
+	return reply, nil
+}
+
+func (inst *instance) set(t int64) uint16 {
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	i, found := slices.BinarySearch(inst.timers, t)
+	if found {
+		return errNone
+	}
+	if len(inst.timers) >= MaxTimers {
+		return errTooMany
+	}
+	inst.timers = slices.Insert(inst.timers, i, t)
+	inst.notify()
+	return errNone
+}
+
+func (inst *instance) cancel(t int64) uint16 {
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	i, found := slices.BinarySearch(inst.timers, t)
+	if !found {
+		return errNotFound
+	}
+	inst.timers = slices.Delete(inst.timers, i, i+1)
+	inst.notify()
+	return errNone
+}
+
+// notify the loop goroutine.  Must be called with mutex locked.
+func (inst *instance) notify() {
+	select {
+	case inst.changed <- struct{}{}:
+	default:
+	}
+}
+
+func (inst *instance) next() (t int64, ok bool) {
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	if len(inst.timers) == 0 {
+		return 0, false
+	}
+	return inst.timers[0], true
+}
+
+// remove an expired timer after it has been sent.  It might have been
+// cancelled in the meantime.
+func (inst *instance) remove(t int64) {
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	if i, found := slices.BinarySearch(inst.timers, t); found {
+		inst.timers = slices.Delete(inst.timers, i, i+1)
+	}
+}
+
+func (inst *instance) loop(ctx Context, send chan<- packet.Thunk) {
+	defer close(inst.done)
+
+	for {
+		t, ok := inst.next()
+		if !ok {
+			select {
+			case <-inst.changed:
+				continue
+			case <-inst.stop:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		if d := time.Until(time.Unix(0, t)); d > 0 {
+			timer := time.NewTimer(d)
+			select {
+			case <-timer.C:
+			case <-inst.changed:
+				timer.Stop()
+				continue
+			case <-inst.stop:
+				timer.Stop()
+				return
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			}
+		}
+
+		select {
+		case send <- inst.makeInfo(t):
+			inst.remove(t)
+		case <-inst.changed:
+			continue // Might have been cancelled.
+		case <-inst.stop:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (inst *instance) makeInfo(t int64) packet.Thunk {
+	return func() (packet.Buf, error) {
+		p := packet.MakeInfo(inst.code, 8)
+		binary.LittleEndian.PutUint64(p.Content(), uint64(t))
+		return p, nil
+	}
+}
+
+func (inst *instance) Shutdown(ctx Context, suspend bool) ([]byte, error) {
+	if inst.stop != nil {
+		close(inst.stop)
+		<-inst.done
+	}
+
+	if !suspend {
+		return nil, nil
+	}
+
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	if len(inst.timers) == 0 {
+		return nil, nil
+	}
+
+	service.RequestWakeup(ctx, time.Unix(0, inst.timers[0]))
+
+	b := make([]byte, 0, 8*len(inst.timers))
+	for _, t := range inst.timers {
+		b = binary.LittleEndian.AppendUint64(b, uint64(t))
+	}
+	return b, nil
+}
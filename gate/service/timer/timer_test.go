@@ -0,0 +1,66 @@
+// Copyright (c) 2026 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package timer
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"gate.computer/gate/packet"
+	"gate.computer/gate/service"
+	"gate.computer/gate/service/servicetest"
+	"github.com/stretchr/testify/assert"
+
+	. "import.name/type/context"
+)
+
+func TestFactory(t *testing.T) {
+	servicetest.FactoryTest(t.Context(), t, Service, servicetest.FactorySpec{
+		NoStreams:          true,
+		AlwaysDiscoverable: true,
+	})
+}
+
+func call(ctx Context, t *testing.T, i *servicetest.InstanceTester, op uint8, at time.Time) uint16 {
+	t.Helper()
+
+	p := append(packet.MakeCall(servicetest.Code, 0), op)
+	p = binary.LittleEndian.AppendUint64(p, uint64(at.UnixNano()))
+	return binary.LittleEndian.Uint16(i.Handle(ctx, t, p).Content())
+}
+
+func TestInstance(t *testing.T) {
+	var wakeup time.Time
+
+	ctx := service.ContextWithWakeup(t.Context(), func(t time.Time) {
+		wakeup = t
+	})
+
+	past := time.Unix(0, time.Now().Add(-time.Second).UnixNano())
+	future := time.Unix(0, time.Now().Add(time.Hour).UnixNano())
+	later := future.Add(time.Hour)
+
+	i := servicetest.NewInstanceTester(ctx, t, Service, servicetest.InstanceSpec{})
+
+	assert.Equal(t, call(ctx, t, i, callSet, later), errNone)
+	assert.Equal(t, call(ctx, t, i, callSet, past), errNone)
+
+	p := i.Receive(ctx, t)
+	assert.Equal(t, p.Domain(), packet.DomainInfo)
+	assert.Equal(t, int64(binary.LittleEndian.Uint64(p.Content())), past.UnixNano())
+
+	assert.Equal(t, call(ctx, t, i, callSet, future), errNone)
+	assert.Equal(t, call(ctx, t, i, callCancel, later), errNone)
+	assert.Equal(t, call(ctx, t, i, callCancel, later), errNotFound)
+
+	snapshot := i.Suspend(ctx, t)
+	assert.Equal(t, len(snapshot), 8)
+	assert.True(t, wakeup.Equal(future))
+
+	i = servicetest.NewInstanceTester(ctx, t, Service, servicetest.InstanceSpec{Snapshot: snapshot})
+	assert.Equal(t, call(ctx, t, i, callCancel, future), errNone)
+	i.Shutdown(ctx, t)
+}
@@ -0,0 +1,33 @@
+// Copyright (c) 2026 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package service
+
+import (
+	"context"
+	"time"
+
+	. "import.name/type/context"
+)
+
+type contextWakeupValueKey struct{}
+
+// ContextWithWakeup returns a context for serving an instance.  The function
+// is invoked with the times requested using RequestWakeup.
+func ContextWithWakeup(ctx Context, f func(time.Time)) Context {
+	return context.WithValue(ctx, contextWakeupValueKey{}, f)
+}
+
+// RequestWakeup asks the server to resume the instance at the specified time.
+// It should be called by Instance.Shutdown when the instance is being
+// suspended; the earliest requested time is used.  False is returned if the
+// execution environment doesn't support wakeups.
+func RequestWakeup(ctx Context, t time.Time) bool {
+	f, ok := ctx.Value(contextWakeupValueKey{}).(func(time.Time))
+	if !ok {
+		return false
+	}
+	f(t)
+	return true
+}
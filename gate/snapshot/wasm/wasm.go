@@ -16,11 +16,18 @@ import (
 	"gate.computer/wag/section"
 )
 
+// Snapshot section format versions.  There are no older versions yet.  When
+// the format changes, ReadSnapshotSection must decode the versions between
+// MinSnapshotVersion and SnapshotVersion; the image package always writes
+// the current version, so older sections get rewritten when the program is
+// snapshotted again.
 const (
-	minSnapshotVersion = 0
-	maxServiceNameLen  = 127
+	SnapshotVersion    = 0 // Written by this implementation.
+	MinSnapshotVersion = 0 // Oldest readable version.
 )
 
+const maxServiceNameLen = 127
+
 // Custom WebAssembly sections.
 const (
 	SectionSnapshot = "gate.snapshot" // Must appear once before gate.export or gate.buffer section.
@@ -35,9 +42,12 @@ func ReadSnapshotSection(r section.Reader) (snap *snapshot.Snapshot, readLen int
 	if err != nil {
 		return nil, readLen, err
 	}
-	if version < minSnapshotVersion {
+	if version < MinSnapshotVersion {
 		return nil, readLen, badprogram.Error(fmt.Sprintf("unsupported snapshot version: %d", version))
 	}
+	if version > SnapshotVersion {
+		return nil, readLen, badprogram.Error(fmt.Sprintf("snapshot version %d is newer than supported version %d", version, SnapshotVersion))
+	}
 
 	flags, n, err := binary.Varuint64(r)
 	readLen += n
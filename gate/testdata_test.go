@@ -24,6 +24,7 @@ var (
 	wasmSnapshotARM64 = readFile("../testdata/snapshot.arm64.wasm.gz")
 	wasmSuspend       = readFile("../testdata/suspend.wasm")
 	wasmTime          = readFile("../testdata/time.wasm")
+	wasmTrap          = readFile("../testdata/trap.wasm")
 )
 
 var (
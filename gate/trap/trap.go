@@ -27,4 +27,5 @@ const (
 	ABIViolation                  = pb.ID_ABI_VIOLATION
 	InternalError                 = pb.ID_INTERNAL_ERROR
 	Killed                        = pb.ID_KILLED
+	Timeout                       = pb.ID_TIMEOUT
 )
@@ -0,0 +1,225 @@
+// Copyright (c) 2026 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package migrate moves instances between servers using the web API.  The
+// source server pushes the instance directly to the destination server.
+package migrate
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"gate.computer/gate/server/api"
+	"gate.computer/gate/web"
+
+	. "import.name/type/context"
+)
+
+// Endpoint of a server's web API.
+type Endpoint struct {
+	URL           string                 // Scheme and authority, e.g. "https://example.net".
+	Client        *http.Client           // Defaults to http.DefaultClient.
+	Authorization func() (string, error) // Called for each request if set.
+}
+
+// Instance asks the source server to move an instance to the destination
+// server.  The destination authorization is forwarded by the source server,
+// which makes a single request to the destination.  See Push.
+func Instance(ctx Context, src, dst *Endpoint, instance string) error {
+	params := url.Values{
+		web.ParamAction:      []string{web.ActionMigrate},
+		web.ParamDestination: []string{dst.URL},
+	}
+
+	req, err := src.newRequest(ctx, http.MethodPost, web.PathInstances+instance, params, nil)
+	if err != nil {
+		return err
+	}
+
+	if dst.Authorization != nil {
+		auth, err := dst.Authorization()
+		if err != nil {
+			return err
+		}
+		if auth != "" {
+			req.Header.Set(web.HeaderDestinationAuthorization, auth)
+		}
+	}
+
+	resp, err := src.do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Push an instance from server to destination.  The instance is suspended and
+// snapshotted, and the snapshot is launched on the destination with the same
+// instance id and tags.  The instance is deleted only after the destination
+// has accepted it.
+//
+// The instance must be running, suspended or halted.  A running instance is
+// resumed on the destination; otherwise it stays suspended or halted.  If the
+// migration fails before the destination accepts the instance, a running
+// instance is resumed.
+func Push(ctx Context, server api.Server, dst *Endpoint, instance string) error {
+	info, err := server.InstanceInfo(ctx, instance)
+	if err != nil {
+		return err
+	}
+
+	state := info.Status.State
+	running := state == api.StateRunning
+
+	if running {
+		inst, err := server.SuspendInstance(ctx, instance)
+		if err != nil {
+			return err
+		}
+		state = inst.Wait(ctx).State
+	}
+
+	switch state {
+	case api.StateSuspended, api.StateHalted:
+
+	default:
+		if running {
+			return fmt.Errorf("instance %s did not suspend: %s", instance, state)
+		}
+		return fmt.Errorf("instance %s is %s", instance, state)
+	}
+
+	// Resume running instance on destination, or here if migration fails.
+	resume := running && state == api.StateSuspended
+
+	migrated := false
+	if resume {
+		defer func() {
+			if !migrated {
+				server.ResumeInstance(ctx, instance, nil)
+			}
+		}()
+	}
+
+	module, err := server.Snapshot(ctx, instance, &api.ModuleOptions{Pin: true})
+	if err != nil {
+		return err
+	}
+
+	if err := transfer(ctx, server, dst, module, instance, info.Tags, !resume); err != nil {
+		server.UnpinModule(ctx, module)
+		return err
+	}
+	migrated = true
+
+	if err := server.DeleteInstance(ctx, instance); err != nil {
+		return fmt.Errorf("instance migrated but not deleted from source: %w", err)
+	}
+	if err := server.UnpinModule(ctx, module); err != nil {
+		return fmt.Errorf("instance migrated but snapshot not unpinned at source: %w", err)
+	}
+
+	return nil
+}
+
+// transfer snapshot module and launch it on destination.
+func transfer(ctx Context, server api.Server, dst *Endpoint, module, instance string, tags []string, suspend bool) error {
+	content, length, err := server.ModuleContent(ctx, module)
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	params := url.Values{
+		web.ParamAction:      []string{web.ActionLaunch},
+		web.ParamInstance:    []string{instance},
+		web.ParamInstanceTag: tags,
+	}
+	if suspend {
+		params.Add(web.ParamAction, web.ActionSuspend)
+	}
+
+	req, err := dst.newRequest(ctx, http.MethodPost, web.PathKnownModules+module, params, content)
+	if err != nil {
+		return destinationError{err}
+	}
+	req.Header.Set(web.HeaderContentType, web.ContentTypeWebAssembly)
+	req.ContentLength = length
+
+	resp, err := dst.do(req)
+	if err != nil {
+		return destinationError{err}
+	}
+	resp.Body.Close()
+
+	if id := resp.Header.Get(web.HeaderInstance); id != instance {
+		return destinationError{fmt.Errorf("launched instance %q instead of %s", id, instance)}
+	}
+
+	return nil
+}
+
+// destinationError is a public error which is reported as bad gateway.
+type destinationError struct {
+	err error
+}
+
+func (e destinationError) Error() string       { return "migration destination: " + e.err.Error() }
+func (e destinationError) PublicError() string { return e.Error() }
+func (e destinationError) Status() int         { return http.StatusBadGateway }
+func (e destinationError) Unwrap() error       { return e.err }
+
+func (e *Endpoint) newRequest(ctx Context, method, uri string, params url.Values, body io.Reader) (*http.Request, error) {
+	u := strings.TrimSuffix(e.URL, "/") + uri
+	if len(params) > 0 {
+		u += "?" + params.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if e.Authorization != nil {
+		auth, err := e.Authorization()
+		if err != nil {
+			return nil, err
+		}
+		if auth != "" {
+			req.Header.Set(web.HeaderAuthorization, auth)
+		}
+	}
+
+	return req, nil
+}
+
+func (e *Endpoint) do(req *http.Request) (*http.Response, error) {
+	client := e.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusNoContent:
+		return resp, nil
+	}
+	defer resp.Body.Close()
+
+	msg := resp.Status
+	if x := strings.SplitN(resp.Header.Get(web.HeaderContentType), ";", 2); x[0] == "text/plain" {
+		if text, _ := io.ReadAll(resp.Body); len(text) > 0 {
+			msg = strings.TrimSpace(string(text))
+		}
+	}
+	return nil, fmt.Errorf("%s %s: %s", req.Method, req.URL.Path, msg)
+}
@@ -22,6 +22,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"time"
 )
 
 // KnownModuleSource is the name of the built-in directory of modules the
@@ -53,10 +54,17 @@ const (
 	ParamFeature     = "feature"
 	ParamAction      = "action"
 	ParamModuleTag   = "module-tag"   // For pin or snapshot action.
-	ParamFunction    = "function"     // For call, launch or resume action.
-	ParamInstance    = "instance"     // For call or launch action.
-	ParamInstanceTag = "instance-tag" // For call, launch or update action.
-	ParamLog         = "log"          // For call, launch or resume action.
+	ParamModule      = "module"       // For resume action.
+	ParamFunction    = "function"     // For call, launch, resume or clone action.
+	ParamInstance    = "instance"     // For call, launch or clone action.
+	ParamInstanceTag = "instance-tag" // For call, launch, clone or update action.
+	ParamLog         = "log"          // For call, launch, resume or clone action.
+	ParamTag         = "tag"          // For module or instance listing; all must match.
+	ParamAnyTag      = "any-tag"      // For module or instance listing; one must match.
+	ParamState       = "state"        // For instance listing.
+	ParamCursor      = "cursor"       // For module or instance listing.
+	ParamLimit       = "limit"        // For module or instance listing.
+	ParamDestination = "destination"  // For migrate action.
 )
 
 // Queryable features.
@@ -77,7 +85,8 @@ const (
 // Actions on instances.  ActionWait can be combined with ActionKill or
 // ActionSuspend in a single request (ParamAction appears twice in the URL).
 // ActionSuspend can be combined with ActionLaunch on a module: the instance
-// will be created in StateSuspended or StateHalted.
+// will be created in StateSuspended or StateHalted.  Similarly, ActionSuspend
+// can be combined with ActionClone.
 const (
 	ActionIO       = "io"       // Post or websocket.
 	ActionWait     = "wait"     // Post.
@@ -88,6 +97,13 @@ const (
 	ActionDelete   = "delete"   // Post.
 	ActionUpdate   = "update"   // Post.
 	ActionDebug    = "debug"    // Post.
+	ActionClone    = "clone"    // Post.
+	ActionMigrate  = "migrate"  // Post.
+)
+
+// Actions on the instance collection.
+const (
+	ActionWatch = "watch" // Websocket.
 )
 
 // HTTP request headers.
@@ -96,6 +112,9 @@ const (
 	HeaderAuthorization = "Authorization" // "Bearer" JSON Web Token.
 	HeaderOrigin        = "Origin"
 	HeaderTE            = "Te" // Accepted transfer encodings.
+
+	// Authorization for destination server of migrate action.
+	HeaderDestinationAuthorization = "Gate-Destination-Authorization"
 )
 
 // HTTP request or response headers.
@@ -336,30 +355,59 @@ func (status Status) String() (s string) {
 	return
 }
 
-// Response to PathKnownModules request.
+// Response to PathKnownModules request.  NextCursor is set if the listing was
+// limited and there are more modules.
 type Modules struct {
-	Modules []ModuleInfo `json:"modules"`
+	Modules    []ModuleInfo `json:"modules"`
+	NextCursor string       `json:"nextCursor,omitempty"`
 }
 
 // ModuleInfo 'r' mation.
 type ModuleInfo struct {
-	Module string   `json:"module"`
-	Tags   []string `json:"tags,omitempty"`
+	Module  string         `json:"module"`
+	Tags    []string       `json:"tags,omitempty"`
+	Lineage *ModuleLineage `json:"lineage,omitempty"` // Set for snapshots.
+}
+
+// ModuleLineage of a snapshot.
+type ModuleLineage struct {
+	Parent   string    `json:"parent,omitempty"`   // Module of the snapshotted instance.
+	Instance string    `json:"instance,omitempty"` // Snapshotted instance.
+	Created  time.Time `json:"created,omitzero"`
 }
 
-// Response to a PathInstances request.
+// Response to a PathInstances request.  NextCursor is set if the listing was
+// limited and there are more instances.
 type Instances struct {
-	Instances []InstanceInfo `json:"instances"`
+	Instances  []InstanceInfo `json:"instances"`
+	NextCursor string         `json:"nextCursor,omitempty"`
 }
 
 // InstanceInfo 'r' mation.
 type InstanceInfo struct {
-	Instance  string   `json:"instance"`
-	Module    string   `json:"module"`
-	Status    Status   `json:"status"`
-	Transient bool     `json:"transient,omitempty"`
-	Debugging bool     `json:"debugging,omitempty"`
-	Tags      []string `json:"tags,omitempty"`
+	Instance   string        `json:"instance"`
+	Module     string        `json:"module"`
+	Status     Status        `json:"status"`
+	Transient  bool          `json:"transient,omitempty"`
+	Debugging  bool          `json:"debugging,omitempty"`
+	Tags       []string      `json:"tags,omitempty"`
+	Hibernated bool          `json:"hibernated,omitempty"`
+	Usage      InstanceUsage `json:"usage"`
+	Created    time.Time     `json:"created,omitzero"`
+	Resumed    time.Time     `json:"resumed,omitzero"` // Zero if never run.
+}
+
+// InstanceUsage of resources.  Packets are counted from the program's point of
+// view.  CPU time doesn't include the current run.
+type InstanceUsage struct {
+	MemorySize      uint32 `json:"memorySize,omitempty"`
+	MaxMemorySize   uint32 `json:"maxMemorySize,omitempty"`
+	StackUsage      uint32 `json:"stackUsage,omitempty"`
+	CPUTime         string `json:"cpuTime,omitempty"` // Seconds with "s" suffix.
+	PacketsSent     uint64 `json:"packetsSent,string,omitempty"`
+	BytesSent       uint64 `json:"bytesSent,string,omitempty"`
+	PacketsReceived uint64 `json:"packetsReceived,string,omitempty"`
+	BytesReceived   uint64 `json:"bytesReceived,string,omitempty"`
 }
 
 // Instance update request content.
@@ -391,6 +439,13 @@ type IOConnection struct {
 	Connected bool `json:"connected"`
 }
 
+// ActionWatch websocket request message.  It is followed by a text message for
+// each instance change: InstanceInfo as JSON.  Deleted instances are reported
+// with empty state.
+type Watch struct {
+	Authorization string `json:"authorization"`
+}
+
 // Secondary text message on successful ActionCall or ActionIO websocket
 // connection.  There may be multiple, and binary messages may be interleaved
 // between them.  The final status is reported just before normal connection
@@ -7,10 +7,15 @@ package web
 import (
 	"bytes"
 	"crypto/ed25519"
+	"encoding/json"
 	"testing"
 	"time"
 
+	pb "gate.computer/gate/pb/server"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	. "import.name/testing/mustr"
 )
@@ -33,3 +38,35 @@ func TestBearerLocal(t *testing.T) {
 		Aud: []string{"test"},
 	}))))
 }
+
+func TestInstanceInfoProtoJSON(t *testing.T) {
+	created := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+	data := Must(t, R(protojson.Marshal(&pb.InstanceInfo{
+		Instance: "test",
+		Status:   &pb.Status{State: pb.State_SUSPENDED},
+		Usage: &pb.InstanceUsage{
+			MemorySize:      65536,
+			MaxMemorySize:   131072,
+			CpuTime:         durationpb.New(1500 * time.Millisecond),
+			PacketsSent:     3,
+			BytesSent:       1 << 40,
+			PacketsReceived: 2,
+		},
+		Created: timestamppb.New(created),
+	})))
+
+	var info InstanceInfo
+	require.NoError(t, json.Unmarshal(data, &info))
+	require.Equal(t, StateSuspended, info.Status.State)
+	require.Equal(t, InstanceUsage{
+		MemorySize:      65536,
+		MaxMemorySize:   131072,
+		CPUTime:         "1.500s",
+		PacketsSent:     3,
+		BytesSent:       1 << 40,
+		PacketsReceived: 2,
+	}, info.Usage)
+	require.True(t, info.Created.Equal(created))
+	require.True(t, info.Resumed.IsZero())
+}
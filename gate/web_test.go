@@ -27,6 +27,7 @@ import (
 	"gate.computer/gate/snapshot/wasm"
 	"gate.computer/gate/source"
 	"gate.computer/gate/web"
+	"gate.computer/gate/web/migrate"
 	_ "gate.computer/internal/test/service-ext"
 	"gate.computer/wag"
 	"gate.computer/wag/binding"
@@ -117,11 +118,19 @@ func newServer() (*server.Server, error) {
 func newHandler(t *testing.T) http.Handler {
 	t.Helper()
 
+	return newMigrationHandler(t)
+}
+
+// newMigrationHandler allows migration to destinations.
+func newMigrationHandler(t *testing.T, destinations ...string) http.Handler {
+	t.Helper()
+
 	config := &webserver.Config{
-		Server:       Must(t, R(newServer())),
-		Authority:    "example.invalid",
-		Origins:      []string{"null"},
-		NonceChecker: newTestNonceChecker(),
+		Server:                Must(t, R(newServer())),
+		Authority:             "example.invalid",
+		Origins:               []string{"null"},
+		NonceChecker:          newTestNonceChecker(),
+		MigrationDestinations: destinations,
 	}
 
 	h := webserver.NewHandler("/", config)
@@ -1076,6 +1085,66 @@ func TestInstanceSuspend(t *testing.T) {
 	})
 }
 
+func TestMigrate(t *testing.T) {
+	dstHandler := newHandler(t)
+	dstServer := httptest.NewServer(dstHandler)
+	defer dstServer.Close()
+
+	srcHandler := newMigrationHandler(t, dstServer.URL)
+	srcServer := httptest.NewServer(srcHandler)
+	defer srcServer.Close()
+
+	pri := newPrincipalKey()
+
+	var instID string
+
+	{
+		req := newSignedRequest(pri, http.MethodPost, web.PathKnownModules+hashSuspend+"?action=launch&function=loop&instance-tag=migrant", wasmSuspend)
+		req.Header.Set(web.HeaderContentType, web.ContentTypeWebAssembly)
+		resp, _ := checkResponse(t, srcHandler, req, http.StatusOK)
+
+		instID = resp.Header.Get(web.HeaderInstance)
+	}
+
+	time.Sleep(time.Second / 3)
+
+	auth := func() (string, error) {
+		return pri.authorization(&web.AuthorizationClaims{
+			Exp:   time.Now().Add(time.Minute).Unix(),
+			Aud:   []string{"https://example.invalid/gate-0/"},
+			Nonce: strconv.Itoa(rand.Int()),
+		}), nil
+	}
+
+	src := &migrate.Endpoint{URL: srcServer.URL, Authorization: auth}
+	dst := &migrate.Endpoint{URL: dstServer.URL, Authorization: auth}
+
+	t.Run("NotAllowed", func(t *testing.T) {
+		other := &migrate.Endpoint{URL: "http://example.invalid", Authorization: auth}
+		assert.ErrorContains(t, migrate.Instance(context.Background(), src, other, instID), "not allowed")
+	})
+
+	require.NoError(t, migrate.Instance(context.Background(), src, dst, instID))
+
+	req := newSignedRequest(pri, http.MethodPost, web.PathInstances+instID, nil)
+	checkResponse(t, srcHandler, req, http.StatusNotFound)
+
+	req = newSignedRequest(pri, http.MethodPost, web.PathInstances+instID, nil)
+	_, content := checkResponse(t, dstHandler, req, http.StatusOK)
+
+	var info web.InstanceInfo
+	require.NoError(t, json.Unmarshal(content, &info))
+	assert.Equal(t, info.Status, web.Status{State: web.StateRunning})
+	assert.Equal(t, info.Tags, []string{"migrant"})
+
+	req = newSignedRequest(pri, http.MethodPost, web.PathInstances+instID+"?action=suspend&action=wait", nil)
+	resp, _ := checkResponse(t, dstHandler, req, http.StatusOK)
+
+	checkStatusHeader(t, resp.Header.Get(web.HeaderStatus), web.Status{
+		State: web.StateSuspended,
+	})
+}
+
 func TestInstanceTerminated(t *testing.T) {
 	handler := newHandler(t)
 	pri := newPrincipalKey()
@@ -8,3 +8,7 @@ const (
 	DaemonIface = "computer.gate.Daemon"
 	DaemonPath  = "/computer/gate/Daemon"
 )
+
+// DaemonInstanceChanged signal arguments: instance id, state, cause, result,
+// tags.
+const DaemonInstanceChanged = DaemonIface + ".InstanceChanged"
@@ -4,9 +4,11 @@
 
 package common
 
-// Internal container API/ABI compatibility version.
+// Internal container API/ABI compatibility version.  The major version is
+// incremented when the executor protocol changes (ExecRequest and ExecStatus
+// structs in gate/runtime/executor/executor.cpp).
 const (
-	CompatMajor   = "0"
+	CompatMajor   = "1"
 	CompatVersion = CompatMajor + ".0"
 )
 
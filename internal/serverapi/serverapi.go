@@ -12,9 +12,15 @@ import (
 	. "import.name/type/context"
 )
 
-type contextOpKey struct{}
+type (
+	contextOpKey         struct{}
+	contextRemoteAddrKey struct{}
+)
 
-var contextOp any = contextOpKey{}
+var (
+	contextOp         any = contextOpKey{}
+	contextRemoteAddr any = contextRemoteAddrKey{}
+)
 
 func ContextWithOp(ctx Context, op pb.Op) Context {
 	return context.WithValue(ctx, contextOp, op)
@@ -24,3 +30,13 @@ func ContextOp(ctx Context) pb.Op {
 	op, _ := ctx.Value(contextOp).(pb.Op)
 	return op
 }
+
+// ContextWithRemoteAddr of the client, in host:port form if applicable.
+func ContextWithRemoteAddr(ctx Context, addr string) Context {
+	return context.WithValue(ctx, contextRemoteAddr, addr)
+}
+
+func ContextRemoteAddr(ctx Context) string {
+	addr, _ := ctx.Value(contextRemoteAddr).(string)
+	return addr
+}
@@ -8,35 +8,86 @@ import (
 	"log/slog"
 
 	"gate.computer/gate/server"
+	"gate.computer/gate/server/model"
 	"gate.computer/gate/service"
 	"gate.computer/gate/service/catalog"
+	"gate.computer/gate/service/fetch"
+	"gate.computer/gate/service/filesystem"
 	"gate.computer/gate/service/identity"
+	"gate.computer/gate/service/kv"
+	"gate.computer/gate/service/message"
 	"gate.computer/gate/service/origin"
 	"gate.computer/gate/service/random"
 	"gate.computer/gate/service/scope"
+	"gate.computer/gate/service/timer"
 	internal "gate.computer/internal/service"
 
 	. "import.name/type/context"
 )
 
-func Init(ctx Context, originConfig *origin.Config, randomConfig *random.Config, log *slog.Logger) (func(Context) server.InstanceServices, error) {
+// Config of the built-in services.
+type Config struct {
+	Origin     origin.Config
+	Random     random.Config
+	Fetch      fetch.Config
+	Filesystem filesystem.Config
+	KV         kv.Config
+	Message    message.Config
+
+	KeyValueStore model.KeyValueStore // Storage of the kv service.
+}
+
+var DefaultConfig = Config{
+	Origin:     origin.DefaultConfig,
+	Random:     random.DefaultConfig,
+	Fetch:      fetch.DefaultConfig,
+	Filesystem: filesystem.DefaultConfig,
+	KV:         kv.DefaultConfig,
+	Message:    message.DefaultConfig,
+}
+
+// Register the service configurations in a map by service name.
+func (c *Config) Register(m map[string]any) {
+	m["origin"] = &c.Origin
+	m["random"] = &c.Random
+	m["fetch"] = &c.Fetch
+	m["filesystem"] = &c.Filesystem
+	m["kv"] = &c.KV
+	m["message"] = &c.Message
+}
+
+func Init(ctx Context, c *Config, log *slog.Logger) (func(Context) server.InstanceServices, error) {
 	registry := new(service.Registry)
 
 	if err := service.Init(internal.ContextWithLogger(ctx, log), registry); err != nil {
 		return nil, err
 	}
 
+	// Instances communicate through the shared service.
+	m := message.New(&c.Message)
+
 	services := func(ctx Context) server.InstanceServices {
-		o := origin.New(originConfig)
+		o := origin.New(&c.Origin)
+
+		var kvQuota int
+		if res := server.ContextResourcePolicy(ctx); res != nil {
+			kvQuota = res.TotalKeyValueSize
+		}
 
 		r := registry.Clone()
 		r.MustRegister(o)
 		r.MustRegister(catalog.New(r))
+		r.MustRegister(fetch.New(&c.Fetch))
+		r.MustRegister(filesystem.New(&c.Filesystem))
 		r.MustRegister(identity.Service)
-		r.MustRegister(random.New(randomConfig))
+		r.MustRegister(kv.New(&c.KV, c.KeyValueStore, kvQuota))
+		e := m.Endpoint() // Wakes the instance from hibernation.
+		r.MustRegister(e)
+		r.MustRegister(random.New(&c.Random))
 		r.MustRegister(scope.Service)
+		r.MustRegister(timer.Service)
 
-		return server.NewInstanceServices(o, r)
+		return server.NewWakingInstanceServices(o, r, e)
 	}
 
 	return services, nil
@@ -0,0 +1,173 @@
+// Copyright (c) 2026 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package kv accesses persistent key-value storage.  Values are shared by the
+// instances of the program owner (the principal), unless the execution
+// environment separates them per module.
+package kv
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"gate.computer/uapi/service"
+)
+
+const (
+	callGet    uint8 = 0
+	callPut    uint8 = 1
+	callDelete uint8 = 2
+	callList   uint8 = 3
+)
+
+// Errors returned by the service.
+var (
+	ErrNotFound    = errors.New("key not found")
+	ErrQuota       = errors.New("storage quota exceeded")
+	ErrTooLarge    = errors.New("key or value is too large")
+	ErrInvalid     = errors.New("invalid key or call")
+	ErrUnavailable = errors.New("key-value storage is unavailable")
+)
+
+var callErrors = []error{
+	nil,
+	ErrNotFound,
+	ErrQuota,
+	ErrTooLarge,
+	ErrInvalid,
+	ErrUnavailable,
+}
+
+var srv = sync.OnceValue(func() *service.Service {
+	return service.MustRegister("kv", func([]byte) {
+		slog.Debug("gate: kv: info packet received")
+	})
+})
+
+// GetResult contains a value or an error.
+type GetResult struct {
+	Value []byte
+	Err   error
+}
+
+// Get a value.  Error is ErrNotFound if the key doesn't exist.
+func Get(key string) <-chan GetResult {
+	c := make(chan GetResult, 1)
+
+	srv().Call(append([]byte{callGet}, key...), func(reply []byte) {
+		if err := replyError(reply); err != nil {
+			c <- GetResult{Err: err}
+			return
+		}
+		c <- GetResult{Value: reply[2:]}
+	})
+
+	return c
+}
+
+// Put a value.  Key must not be empty.
+func Put(key string, value []byte) <-chan error {
+	return call(makeCall(callPut, key, value))
+}
+
+// Delete a value.  Error is ErrNotFound if the key doesn't exist.
+func Delete(key string) <-chan error {
+	return call(append([]byte{callDelete}, key...))
+}
+
+// ListResult contains keys or an error.
+type ListResult struct {
+	Keys []string
+	Err  error
+}
+
+// List keys which have the prefix, in ascending order.
+func List(prefix string) <-chan ListResult {
+	c := make(chan ListResult, 1)
+
+	go func() {
+		var keys []string
+
+		for {
+			var after []byte
+			if len(keys) > 0 {
+				after = []byte(keys[len(keys)-1])
+			}
+
+			page := make(chan ListResult, 1)
+
+			srv().Call(makeCall(callList, prefix, after), func(reply []byte) {
+				page <- parseListReply(reply)
+			})
+
+			r := <-page
+			if r.Err != nil {
+				c <- r
+				return
+			}
+			if len(r.Keys) == 0 {
+				c <- ListResult{Keys: keys}
+				return
+			}
+			keys = append(keys, r.Keys...)
+		}
+	}()
+
+	return c
+}
+
+func parseListReply(reply []byte) ListResult {
+	if err := replyError(reply); err != nil {
+		return ListResult{Err: err}
+	}
+
+	var keys []string
+
+	for buf := reply[2:]; len(buf) > 0; {
+		if len(buf) < 4 {
+			return ListResult{Err: errors.New("kv: list reply is truncated")}
+		}
+		n := binary.LittleEndian.Uint32(buf)
+		buf = buf[4:]
+		if uint64(n) > uint64(len(buf)) {
+			return ListResult{Err: errors.New("kv: list reply is truncated")}
+		}
+		keys = append(keys, string(buf[:n]))
+		buf = buf[n:]
+	}
+
+	return ListResult{Keys: keys}
+}
+
+func makeCall(op uint8, first string, second []byte) []byte {
+	b := make([]byte, 0, 1+4+len(first)+len(second))
+	b = append(b, op)
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(first)))
+	b = append(b, first...)
+	b = append(b, second...)
+	return b
+}
+
+func call(content []byte) <-chan error {
+	c := make(chan error, 1)
+	srv().Call(content, func(reply []byte) {
+		c <- replyError(reply)
+	})
+	return c
+}
+
+func replyError(reply []byte) error {
+	if len(reply) < 2 {
+		return ErrUnavailable
+	}
+
+	code := binary.LittleEndian.Uint16(reply)
+	if int(code) < len(callErrors) {
+		return callErrors[code]
+	}
+	return fmt.Errorf("unknown kv service call error %d", code)
+}
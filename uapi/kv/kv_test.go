@@ -0,0 +1,30 @@
+// Copyright (c) 2026 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPutGetDelete(t *testing.T) {
+	assert.NoError(t, <-Put("test/foo", []byte("hello")))
+	assert.NoError(t, <-Put("test/bar", nil))
+
+	r := <-Get("test/foo")
+	assert.NoError(t, r.Err)
+	assert.Equal(t, string(r.Value), "hello")
+
+	l := <-List("test/")
+	assert.NoError(t, l.Err)
+	assert.Equal(t, l.Keys, []string{"test/bar", "test/foo"})
+
+	assert.NoError(t, <-Delete("test/foo"))
+	assert.NoError(t, <-Delete("test/bar"))
+	assert.ErrorIs(t, <-Delete("test/foo"), ErrNotFound)
+	assert.ErrorIs(t, (<-Get("test/foo")).Err, ErrNotFound)
+	assert.ErrorIs(t, <-Put("", nil), ErrInvalid)
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2026 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package timer sets wall-clock timers.  The program may be suspended while
+// timers are pending; the execution environment resumes it when the earliest
+// one expires.
+package timer
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"gate.computer/uapi/service"
+)
+
+const (
+	callSet    uint8 = 0
+	callCancel uint8 = 1
+)
+
+// MaxTimers which may be pending at the same time.
+const MaxTimers = 64
+
+// Errors returned by the service.
+var (
+	ErrInvalid  = errors.New("invalid timer call")
+	ErrTooMany  = errors.New("too many pending timers")
+	ErrNotFound = errors.New("timer not found")
+)
+
+var callErrors = []error{
+	nil,
+	ErrInvalid,
+	ErrTooMany,
+	ErrNotFound,
+}
+
+var expired = make(chan time.Time, MaxTimers)
+
+var srv = sync.OnceValue(func() *service.Service {
+	return service.MustRegister("timer", func(b []byte) {
+		if len(b) != 8 {
+			slog.Debug("gate: timer: unknown info packet received")
+			return
+		}
+
+		select {
+		case expired <- time.Unix(0, int64(binary.LittleEndian.Uint64(b))):
+		default:
+			slog.Debug("gate: timer: expiration dropped")
+		}
+	})
+})
+
+// Set a timer.  Setting a pending timer again has no effect.
+func Set(t time.Time) <-chan error {
+	return call(callSet, t)
+}
+
+// Cancel a pending timer.
+func Cancel(t time.Time) <-chan error {
+	return call(callCancel, t)
+}
+
+// Expired returns a channel which receives the times of expired timers.
+func Expired() <-chan time.Time {
+	srv()
+	return expired
+}
+
+func call(op uint8, t time.Time) <-chan error {
+	c := make(chan error, 1)
+
+	b := binary.LittleEndian.AppendUint64([]byte{op}, uint64(t.UnixNano()))
+
+	srv().Call(b, func(reply []byte) {
+		if len(reply) < 2 {
+			c <- errors.New("timer service is unavailable")
+			return
+		}
+
+		code := binary.LittleEndian.Uint16(reply)
+		if int(code) < len(callErrors) {
+			c <- callErrors[code]
+		} else {
+			c <- fmt.Errorf("unknown timer service call error %d", code)
+		}
+	})
+
+	return c
+}
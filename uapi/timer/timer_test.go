@@ -0,0 +1,26 @@
+// Copyright (c) 2026 Timo Savola. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package timer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSet(t *testing.T) {
+	at := time.Now().Add(100 * time.Millisecond)
+	assert.NoError(t, <-Set(at))
+	assert.True(t, (<-Expired()).Equal(time.Unix(0, at.UnixNano())))
+	assert.False(t, time.Now().Before(at))
+}
+
+func TestCancel(t *testing.T) {
+	at := time.Now().Add(time.Hour)
+	assert.NoError(t, <-Set(at))
+	assert.NoError(t, <-Cancel(at))
+	assert.ErrorIs(t, <-Cancel(at), ErrNotFound)
+}